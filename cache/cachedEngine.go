@@ -0,0 +1,283 @@
+// Package cache 为 TTS 引擎提供合成结果缓存
+// 相同文本、语音、语音参数和输出格式的合成结果只向引擎请求一次，
+// 之后按原有分块直接回放，适合反复播报的提示语
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// Stats 缓存统计信息
+type Stats struct {
+	Hits   int64 // 命中次数
+	Misses int64 // 未命中次数
+	Stores int64 // 写入缓存的次数
+	Errors int64 // 写入缓存失败的次数
+}
+
+// CachedEngine 带结果缓存的引擎包装
+// 拦截 SetVoice/SetVoiceParameters 以跟踪缓存键的组成，其余方法直接委托给被包装的引擎
+type CachedEngine struct {
+	realtimetts.TTSEngine
+
+	store Store
+
+	mu     sync.RWMutex
+	voice  realtimetts.Voice
+	params map[string]interface{}
+
+	hits   int64
+	misses int64
+	stores int64
+	errors int64
+
+	// 后台预热
+	prewarmMu     sync.Mutex
+	prewarmConfig *PrewarmConfig // 语音变化时重新预热的配置
+	prewarmCancel context.CancelFunc
+
+	logger  *slog.Logger
+	metrics realtimetts.MetricsRecorder
+}
+
+// NewCachedEngine 用指定存储包装引擎
+func NewCachedEngine(engine realtimetts.TTSEngine, store Store) *CachedEngine {
+	return &CachedEngine{
+		TTSEngine: engine,
+		store:     store,
+		params:    make(map[string]interface{}),
+	}
+}
+
+// Unwrap 返回被包装的引擎
+func (ce *CachedEngine) Unwrap() realtimetts.TTSEngine {
+	return ce.TTSEngine
+}
+
+// SupportsText 转发被包装引擎的文本支持判断，未实现 TextSupporter 的引擎视为支持所有文本
+func (ce *CachedEngine) SupportsText(text string) bool {
+	if supporter, ok := ce.TTSEngine.(realtimetts.TextSupporter); ok {
+		return supporter.SupportsText(text)
+	}
+	return true
+}
+
+// Close 停止后台预热并关闭被包装的引擎
+func (ce *CachedEngine) Close() error {
+	ce.StopPrewarm()
+	return ce.TTSEngine.Close()
+}
+
+// SetLogger 设置诊断日志记录器，并传递给被包装的引擎
+func (ce *CachedEngine) SetLogger(logger *slog.Logger) {
+	logger = realtimetts.RedactingLogger(logger)
+	ce.mu.Lock()
+	ce.logger = logger
+	ce.mu.Unlock()
+
+	if setter, ok := ce.TTSEngine.(realtimetts.LoggerSetter); ok {
+		setter.SetLogger(logger)
+	}
+}
+
+// SetMetrics 设置指标接收方，记录缓存命中情况，并传递给被包装的引擎
+func (ce *CachedEngine) SetMetrics(metrics realtimetts.MetricsRecorder) {
+	ce.mu.Lock()
+	ce.metrics = metrics
+	ce.mu.Unlock()
+
+	if setter, ok := ce.TTSEngine.(realtimetts.MetricsSetter); ok {
+		setter.SetMetrics(metrics)
+	}
+}
+
+// SetVoice 设置语音并记录到缓存键中
+func (ce *CachedEngine) SetVoice(voice realtimetts.Voice) error {
+	if err := ce.TTSEngine.SetVoice(voice); err != nil {
+		return err
+	}
+	ce.mu.Lock()
+	ce.voice = voice
+	ce.mu.Unlock()
+
+	ce.onVoiceChanged()
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数并记录到缓存键中
+// 与引擎的行为一致，新参数合并到已有参数之上
+func (ce *CachedEngine) SetVoiceParameters(params map[string]interface{}) error {
+	if err := ce.TTSEngine.SetVoiceParameters(params); err != nil {
+		return err
+	}
+	ce.mu.Lock()
+	for k, v := range params {
+		ce.params[k] = v
+	}
+	ce.mu.Unlock()
+
+	ce.onVoiceChanged()
+	return nil
+}
+
+// Synthesize 命中缓存时回放缓存的帧，否则调用引擎合成并在成功结束后写入缓存
+func (ce *CachedEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	key := ce.Key(text)
+
+	ce.mu.RLock()
+	logger, metrics := ce.logger, ce.metrics
+	ce.mu.RUnlock()
+
+	entry, ok := ce.store.Get(key)
+	if metrics != nil {
+		metrics.IncCacheLookup(ok)
+	}
+	if ok {
+		atomic.AddInt64(&ce.hits, 1)
+		if logger != nil {
+			logger.Debug("命中合成缓存", realtimetts.LogKeyBytes, entry.Size())
+		}
+		return replay(ctx, entry), nil
+	}
+	atomic.AddInt64(&ce.misses, 1)
+
+	upstream, err := ce.TTSEngine.Synthesize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return ce.record(ctx, key, upstream), nil
+}
+
+// replay 按原有分块回放缓存条目
+func replay(ctx context.Context, entry *Entry) *realtimetts.SynthesisStream {
+	stream := realtimetts.NewSynthesisStream(len(entry.Frames))
+	go func() {
+		for _, frame := range entry.Frames {
+			if err := stream.Send(ctx, frame); err != nil {
+				stream.CloseWithError(err)
+				return
+			}
+		}
+		stream.Close()
+	}()
+	return stream
+}
+
+// record 转发引擎输出的帧，同时记录副本；只有完整成功的合成才会写入缓存
+func (ce *CachedEngine) record(ctx context.Context, key string, upstream *realtimetts.SynthesisStream) *realtimetts.SynthesisStream {
+	stream := realtimetts.NewSynthesisStream(cap(upstream.Frames()))
+	go func() {
+		entry := &Entry{}
+		for frame := range upstream.Frames() {
+			entry.Frames = append(entry.Frames, cloneFrame(frame))
+			if err := stream.Send(ctx, frame); err != nil {
+				// 排空上游，避免引擎协程阻塞
+				for range upstream.Frames() {
+				}
+				stream.CloseWithError(err)
+				return
+			}
+		}
+
+		if err := upstream.Err(); err != nil {
+			stream.CloseWithError(err)
+			return
+		}
+		if len(entry.Frames) > 0 {
+			if err := ce.store.Put(key, entry); err != nil {
+				atomic.AddInt64(&ce.errors, 1)
+			} else {
+				atomic.AddInt64(&ce.stores, 1)
+			}
+		}
+		stream.Close()
+	}()
+	return stream
+}
+
+// cloneFrame 复制帧数据，避免引擎复用缓冲区时污染缓存
+// 话语信息由 TextToAudioStream 填写，不属于缓存内容
+func cloneFrame(frame realtimetts.Frame) realtimetts.Frame {
+	clone := frame
+	clone.Data = append([]byte(nil), frame.Data...)
+	clone.UtteranceID = 0
+	clone.SentenceID = 0
+	if frame.Format != nil {
+		format := *frame.Format
+		clone.Format = &format
+	}
+	if frame.Timing != nil {
+		timing := *frame.Timing
+		clone.Timing = &timing
+	}
+	return clone
+}
+
+// Key 计算文本在当前语音、参数和输出格式下的缓存键
+// 被包装的引擎实现 realtimetts.Fingerprinter 时，其构造时的语音和参数也计入缓存键
+func (ce *CachedEngine) Key(text string) string {
+	ce.mu.RLock()
+	voice := ce.voice
+	params := make([]string, 0, len(ce.params))
+	for k, v := range ce.params {
+		params = append(params, fmt.Sprintf("%s=%v", k, v))
+	}
+	ce.mu.RUnlock()
+	sort.Strings(params)
+
+	voiceConfig := make([]string, 0, len(voice.Config))
+	for k, v := range voice.Config {
+		voiceConfig = append(voiceConfig, k+"="+v)
+	}
+	sort.Strings(voiceConfig)
+
+	var format string
+	if info := ce.TTSEngine.GetStreamInfo(); info != nil {
+		format = fmt.Sprintf("%s/%d/%d/%d", info.Format, info.SampleRate, info.Channels, info.BitsPerSample)
+	}
+
+	var fingerprint string
+	if fp, ok := ce.TTSEngine.(realtimetts.Fingerprinter); ok {
+		fingerprint = fp.Fingerprint()
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		ce.TTSEngine.GetEngineInfo().Name,
+		fingerprint,
+		voice.ID,
+		strings.Join(voiceConfig, "&"),
+		strings.Join(params, "&"),
+		format,
+		NormalizeText(text),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NormalizeText 规范化文本：去除首尾空白并将连续空白合并为一个空格
+func NormalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// GetStats 获取缓存统计信息
+func (ce *CachedEngine) GetStats() Stats {
+	return Stats{
+		Hits:   atomic.LoadInt64(&ce.hits),
+		Misses: atomic.LoadInt64(&ce.misses),
+		Stores: atomic.LoadInt64(&ce.stores),
+		Errors: atomic.LoadInt64(&ce.errors),
+	}
+}
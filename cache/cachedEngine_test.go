@@ -0,0 +1,231 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"realtimetts/cache"
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// countingEngine 记录合成次数的测试引擎
+type countingEngine struct {
+	calls  int
+	chunks [][]byte
+	err    error
+}
+
+func (e *countingEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	return realtimetts.DefaultAudioConfig()
+}
+
+func (e *countingEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	e.calls++
+	stream := realtimetts.NewSynthesisStream(len(e.chunks))
+	for _, chunk := range e.chunks {
+		stream.Writer() <- realtimetts.NewAudioFrame(chunk, e.GetStreamInfo())
+	}
+	stream.CloseWithError(e.err)
+	return stream, nil
+}
+
+func (e *countingEngine) GetVoices() ([]realtimetts.Voice, error)                { return nil, nil }
+func (e *countingEngine) SetVoice(voice realtimetts.Voice) error                 { return nil }
+func (e *countingEngine) SetVoiceParameters(params map[string]interface{}) error { return nil }
+func (e *countingEngine) SetAudioBuffer(audioBuffer *realtimetts.AudioBuffer)    {}
+func (e *countingEngine) GetEngineInfo() realtimetts.EngineInfo {
+	return realtimetts.EngineInfo{Name: "counting"}
+}
+func (e *countingEngine) Initialize() error { return nil }
+func (e *countingEngine) Close() error      { return nil }
+
+// collect 读取流中的全部音频块
+func collect(t *testing.T, stream *realtimetts.SynthesisStream) ([][]byte, error) {
+	t.Helper()
+	var chunks [][]byte
+	for frame := range stream.Frames() {
+		chunks = append(chunks, frame.Data)
+	}
+	return chunks, stream.Err()
+}
+
+func TestCachedEngineReplaysWithIdenticalChunking(t *testing.T) {
+	engine := &countingEngine{chunks: [][]byte{{1, 2}, {3, 4, 5, 6}, {7, 8}}}
+	cached := cache.NewCachedEngine(engine, cache.NewMemoryStore(0, 0))
+
+	for i := 0; i < 3; i++ {
+		stream, err := cached.Synthesize(context.Background(), "  请稍等 ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks, err := collect(t, stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chunks) != len(engine.chunks) {
+			t.Fatalf("第 %d 次回放块数 = %d, 期望 %d", i, len(chunks), len(engine.chunks))
+		}
+		for j := range chunks {
+			if !bytes.Equal(chunks[j], engine.chunks[j]) {
+				t.Fatalf("第 %d 块内容不一致", j)
+			}
+		}
+	}
+
+	if engine.calls != 1 {
+		t.Fatalf("引擎被调用 %d 次, 期望 1", engine.calls)
+	}
+	if stats := cached.GetStats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("统计不正确: %+v", stats)
+	}
+}
+
+func TestCachedEngineKeyTracksVoiceAndParameters(t *testing.T) {
+	cached := cache.NewCachedEngine(&countingEngine{}, cache.NewMemoryStore(0, 0))
+
+	base := cached.Key("你好")
+	if cached.Key(" 你好\n") != base {
+		t.Fatal("规范化后相同的文本应使用相同的键")
+	}
+
+	cached.SetVoiceParameters(map[string]interface{}{"speed": 1.2})
+	withParams := cached.Key("你好")
+	if withParams == base {
+		t.Fatal("语音参数变化后键应改变")
+	}
+
+	cached.SetVoice(realtimetts.Voice{ID: "zh_female"})
+	if cached.Key("你好") == withParams {
+		t.Fatal("语音变化后键应改变")
+	}
+}
+
+func TestCachedEngineKeyTracksEngineSettings(t *testing.T) {
+	key := func(voiceType, accessToken string) string {
+		engine := engines.NewVolcengineEngine("app", accessToken, "cluster")
+		config := engine.GetVolcengineConfig()
+		config.VoiceType = voiceType
+		if err := engine.SetVolcengineConfig(config); err != nil {
+			t.Fatal(err)
+		}
+		return cache.NewCachedEngine(engine, cache.NewMemoryStore(0, 0)).Key("你好")
+	}
+
+	base := key("BV700_streaming", "token-a")
+	if key("BV001_streaming", "token-a") == base {
+		t.Fatal("引擎构造时的语音不同, 键应不同")
+	}
+	if key("BV700_streaming", "token-b") != base {
+		t.Fatal("凭据不应影响缓存键")
+	}
+}
+
+func TestCachedEngineSkipsFailedSynthesis(t *testing.T) {
+	engine := &countingEngine{chunks: [][]byte{{1, 2}}, err: errors.New("boom")}
+	cached := cache.NewCachedEngine(engine, cache.NewMemoryStore(0, 0))
+
+	for i := 0; i < 2; i++ {
+		stream, _ := cached.Synthesize(context.Background(), "失败")
+		if _, err := collect(t, stream); err == nil {
+			t.Fatal("应透传引擎的错误")
+		}
+	}
+	if engine.calls != 2 {
+		t.Fatalf("失败的合成不应被缓存，引擎调用 %d 次", engine.calls)
+	}
+}
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := cache.NewMemoryStore(8, 0)
+	entry := func(n int) *cache.Entry {
+		return &cache.Entry{Frames: []realtimetts.Frame{{Data: make([]byte, n)}}}
+	}
+
+	store.Put("a", entry(4))
+	store.Put("b", entry(4))
+	store.Get("a")
+	store.Put("c", entry(4))
+
+	if _, ok := store.Get("b"); ok {
+		t.Fatal("最久未使用的条目应被淘汰")
+	}
+	if _, ok := store.Get("a"); !ok {
+		t.Fatal("最近使用的条目应保留")
+	}
+	if store.Size() != 8 {
+		t.Fatalf("Size = %d, 期望 8", store.Size())
+	}
+
+	store.Put("big", entry(16))
+	if _, ok := store.Get("big"); ok {
+		t.Fatal("超过上限的条目不应缓存")
+	}
+}
+
+func TestDiskStoreSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	engine := &countingEngine{chunks: [][]byte{{1, 2}, {3, 4}}}
+
+	store, err := cache.NewDiskStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, _ := cache.NewCachedEngine(engine, store).Synthesize(context.Background(), "欢迎")
+	collect(t, stream)
+
+	// 重新打开存储，模拟进程重启
+	reopened, err := cache.NewDiskStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiered := cache.NewTieredStore(cache.NewMemoryStore(0, 0), reopened)
+	stream, _ = cache.NewCachedEngine(engine, tiered).Synthesize(context.Background(), "欢迎")
+	chunks, err := collect(t, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if engine.calls != 1 || len(chunks) != 2 || !bytes.Equal(chunks[1], []byte{3, 4}) {
+		t.Fatalf("应从磁盘命中缓存: calls=%d chunks=%v", engine.calls, chunks)
+	}
+}
+
+func TestPrewarmFillsCacheWithBoundedConcurrency(t *testing.T) {
+	engine := &countingEngine{chunks: [][]byte{{1, 2}}}
+	cached := cache.NewCachedEngine(engine, cache.NewMemoryStore(0, 0))
+
+	phrases := []string{"请稍等", "您好", "网络异常，请重试", "您好"}
+	var progress []cache.PrewarmProgress
+	var mu sync.Mutex
+	err := cached.Prewarm(context.Background(), cache.PrewarmConfig{
+		Phrases:     phrases,
+		Concurrency: 1,
+		OnProgress: func(p cache.PrewarmProgress) {
+			mu.Lock()
+			progress = append(progress, p)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != len(phrases) || progress[len(progress)-1].Done != len(phrases) {
+		t.Fatalf("进度回调不完整: %+v", progress)
+	}
+	if !progress[3].Cached {
+		t.Fatal("重复的短语应直接命中缓存")
+	}
+	if engine.calls != 3 {
+		t.Fatalf("引擎被调用 %d 次, 期望 3", engine.calls)
+	}
+
+	stream, _ := cached.Synthesize(context.Background(), "请稍等")
+	collect(t, stream)
+	if stats := cached.GetStats(); stats.Hits != 1 || stats.Misses != 0 || engine.calls != 3 {
+		t.Fatalf("预热后的短语应直接命中: %+v calls=%d", stats, engine.calls)
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiskStore 磁盘存储
+// 每个条目保存为目录下的一个 gob 文件，进程重启后仍然有效；
+// 超过字节上限时按最后访问时间淘汰
+type DiskStore struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+// NewDiskStore 创建磁盘存储，maxBytes 为 0 表示不限制
+func NewDiskStore(dir string, maxBytes int64) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	return &DiskStore{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// path 返回键对应的文件路径，键已是十六进制摘要
+func (ds *DiskStore) path(key string) string {
+	return filepath.Join(ds.dir, key+".gob")
+}
+
+// Get 读取条目并更新访问时间
+func (ds *DiskStore) Get(key string) (*Entry, bool) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	file, err := os.Open(ds.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var entry Entry
+	if err := gob.NewDecoder(file).Decode(&entry); err != nil {
+		// 损坏的条目直接删除
+		os.Remove(ds.path(key))
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(ds.path(key), now, now)
+	return &entry, true
+}
+
+// Put 写入条目，先写临时文件再重命名，避免读到不完整的条目
+func (ds *DiskStore) Put(key string, entry *Entry) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	tmp, err := os.CreateTemp(ds.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建缓存文件失败: %w", err)
+	}
+	if err := gob.NewEncoder(tmp).Encode(entry); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), ds.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("保存缓存文件失败: %w", err)
+	}
+
+	return ds.prune()
+}
+
+// prune 超过字节上限时删除最久未访问的文件
+func (ds *DiskStore) prune() error {
+	if ds.maxBytes <= 0 {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(ds.dir, "*.gob"))
+	if err != nil {
+		return err
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var total int64
+	infos := make([]cacheFile, 0, len(files))
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].modTime.Before(infos[j].modTime) })
+	for _, info := range infos {
+		if total <= ds.maxBytes {
+			break
+		}
+		if err := os.Remove(info.path); err == nil {
+			total -= info.size
+		}
+	}
+	return nil
+}
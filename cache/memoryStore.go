@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MemoryStore 内存 LRU 存储
+// 超过字节数或条目数上限时淘汰最久未使用的条目
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxBytes   int64
+	maxEntries int
+	size       int64
+	order      *list.List // 最近使用的在前
+	items      map[string]*list.Element
+}
+
+// memoryItem LRU 链表元素
+type memoryItem struct {
+	key   string
+	entry *Entry
+	size  int64
+}
+
+// NewMemoryStore 创建内存存储，上限为 0 表示不限制
+func NewMemoryStore(maxBytes int64, maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get 获取条目并标记为最近使用
+func (ms *MemoryStore) Get(key string) (*Entry, bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	elem, ok := ms.items[key]
+	if !ok {
+		return nil, false
+	}
+	ms.order.MoveToFront(elem)
+	return elem.Value.(*memoryItem).entry, true
+}
+
+// Put 写入条目，单个条目超过字节上限时不缓存
+func (ms *MemoryStore) Put(key string, entry *Entry) error {
+	size := entry.Size()
+	if ms.maxBytes > 0 && size > ms.maxBytes {
+		return nil
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if elem, ok := ms.items[key]; ok {
+		item := elem.Value.(*memoryItem)
+		ms.size += size - item.size
+		item.entry, item.size = entry, size
+		ms.order.MoveToFront(elem)
+	} else {
+		ms.items[key] = ms.order.PushFront(&memoryItem{key: key, entry: entry, size: size})
+		ms.size += size
+	}
+
+	ms.evict()
+	return nil
+}
+
+// evict 淘汰条目直到满足上限
+func (ms *MemoryStore) evict() {
+	for ms.order.Len() > 0 &&
+		((ms.maxBytes > 0 && ms.size > ms.maxBytes) || (ms.maxEntries > 0 && ms.order.Len() > ms.maxEntries)) {
+		elem := ms.order.Back()
+		item := elem.Value.(*memoryItem)
+		ms.order.Remove(elem)
+		delete(ms.items, item.key)
+		ms.size -= item.size
+	}
+}
+
+// Len 返回条目数
+func (ms *MemoryStore) Len() int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.order.Len()
+}
+
+// Size 返回缓存的音频字节数
+func (ms *MemoryStore) Size() int64 {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.size
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// PrewarmConfig 预热配置
+type PrewarmConfig struct {
+	Phrases       []string              // 需要预先合成的短语
+	Concurrency   int                   // 同时合成的短语数，默认为 2
+	OnProgress    func(PrewarmProgress) // 每个短语完成后回调，可能在多个协程中调用
+	OnVoiceChange bool                  // 语音或语音参数变化后自动重新预热（仅 StartPrewarm）
+}
+
+// PrewarmProgress 预热进度
+type PrewarmProgress struct {
+	Phrase string // 刚完成的短语
+	Done   int    // 已完成的短语数
+	Total  int    // 短语总数
+	Cached bool   // 是否已在缓存中，无需合成
+	Err    error  // 合成失败的原因
+}
+
+// defaultPrewarmConcurrency 默认预热并发数
+const defaultPrewarmConcurrency = 2
+
+// Prewarm 预先合成短语并写入缓存，阻塞直到全部完成或 ctx 取消
+// 已在缓存中的短语会被跳过；预热不计入命中统计。返回所有失败短语的错误
+func (ce *CachedEngine) Prewarm(ctx context.Context, config PrewarmConfig) error {
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultPrewarmConcurrency
+	}
+
+	var (
+		mu   sync.Mutex
+		done int
+		errs []error
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+	total := len(config.Phrases)
+
+	for _, phrase := range config.Phrases {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(phrase string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cached, err := ce.prewarmPhrase(ctx, phrase)
+
+			mu.Lock()
+			done++
+			progress := PrewarmProgress{Phrase: phrase, Done: done, Total: total, Cached: cached, Err: err}
+			if err != nil {
+				errs = append(errs, err)
+			}
+			mu.Unlock()
+
+			if config.OnProgress != nil {
+				config.OnProgress(progress)
+			}
+		}(phrase)
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// prewarmPhrase 合成单个短语并等待写入缓存
+func (ce *CachedEngine) prewarmPhrase(ctx context.Context, phrase string) (cached bool, err error) {
+	key := ce.Key(phrase)
+	if _, ok := ce.store.Get(key); ok {
+		return true, nil
+	}
+
+	upstream, err := ce.TTSEngine.Synthesize(ctx, phrase)
+	if err != nil {
+		return false, err
+	}
+	stream := ce.record(ctx, key, upstream)
+	for range stream.Frames() {
+	}
+	return false, stream.Err()
+}
+
+// StartPrewarm 在后台执行预热，立即返回
+// 再次调用或 StopPrewarm 会取消尚未完成的预热；
+// 设置 OnVoiceChange 时，之后每次 SetVoice/SetVoiceParameters 都会以新的缓存键重新预热
+func (ce *CachedEngine) StartPrewarm(config PrewarmConfig) {
+	ce.prewarmMu.Lock()
+	defer ce.prewarmMu.Unlock()
+
+	if config.OnVoiceChange {
+		ce.prewarmConfig = &config
+	} else {
+		ce.prewarmConfig = nil
+	}
+	ce.startPrewarmLocked(config)
+}
+
+// StopPrewarm 取消后台预热，并停止语音变化时的自动预热
+func (ce *CachedEngine) StopPrewarm() {
+	ce.prewarmMu.Lock()
+	defer ce.prewarmMu.Unlock()
+
+	ce.prewarmConfig = nil
+	if ce.prewarmCancel != nil {
+		ce.prewarmCancel()
+		ce.prewarmCancel = nil
+	}
+}
+
+// startPrewarmLocked 取消当前预热并启动新的预热，调用方需持有 prewarmMu
+func (ce *CachedEngine) startPrewarmLocked(config PrewarmConfig) {
+	if ce.prewarmCancel != nil {
+		ce.prewarmCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ce.prewarmCancel = cancel
+
+	go func() {
+		defer cancel()
+		ce.Prewarm(ctx, config)
+	}()
+}
+
+// onVoiceChanged 语音或参数变化后按需重新预热
+func (ce *CachedEngine) onVoiceChanged() {
+	ce.prewarmMu.Lock()
+	defer ce.prewarmMu.Unlock()
+
+	if ce.prewarmConfig != nil {
+		ce.startPrewarmLocked(*ce.prewarmConfig)
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	realtimetts "realtimetts/pkg"
+)
+
+// Entry 缓存条目
+// 保存一次合成输出的全部帧，命中时按原有分块顺序回放
+type Entry struct {
+	Frames []realtimetts.Frame
+}
+
+// Size 返回条目中音频数据的字节数
+func (e *Entry) Size() int64 {
+	var size int64
+	for _, frame := range e.Frames {
+		size += int64(len(frame.Data))
+	}
+	return size
+}
+
+// Store 缓存存储接口
+type Store interface {
+	// Get 按键获取条目
+	Get(key string) (*Entry, bool)
+
+	// Put 写入条目
+	Put(key string, entry *Entry) error
+}
+
+// TieredStore 多级存储
+// 按顺序查找，较低层级命中时回填到之前的层级，写入时写入所有层级
+type TieredStore struct {
+	stores []Store
+}
+
+// NewTieredStore 创建多级存储，通常为内存存储在前、磁盘存储在后
+func NewTieredStore(stores ...Store) *TieredStore {
+	return &TieredStore{stores: stores}
+}
+
+// Get 按层级顺序获取条目
+func (ts *TieredStore) Get(key string) (*Entry, bool) {
+	for i, store := range ts.stores {
+		if entry, ok := store.Get(key); ok {
+			for _, upper := range ts.stores[:i] {
+				upper.Put(key, entry)
+			}
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// Put 写入所有层级，返回遇到的第一个错误
+func (ts *TieredStore) Put(key string, entry *Entry) error {
+	var firstErr error
+	for _, store := range ts.stores {
+		if err := store.Put(key, entry); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
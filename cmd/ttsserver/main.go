@@ -0,0 +1,157 @@
+// ttsserver 以 HTTP 服务的形式提供语音合成，引擎链从配置文件读取；指定 -grpc-addr 时同时提供 gRPC 服务
+//
+//	ttsserver -config tts.yaml -addr :8080 -grpc-addr :9090 -cache-dir /var/cache/tts
+//
+// 配置文件格式见 realtimetts.Config，使用其中的 engines、credentials 和 audio
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"realtimetts/cache"
+	_ "realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+	"realtimetts/server"
+)
+
+func main() {
+	configPath := flag.String("config", "", "配置文件路径 (JSON 或 YAML)")
+	addr := flag.String("addr", ":8080", "监听地址")
+	grpcAddr := flag.String("grpc-addr", "", "gRPC 监听地址，为空时不提供 gRPC 服务")
+	cacheDir := flag.String("cache-dir", "", "磁盘缓存目录，为空时只使用内存缓存")
+	cacheMB := flag.Int64("cache-mb", 64, "内存缓存上限 (MB)，0 表示不缓存")
+	recordDir := flag.String("record-dir", "", "把每次合成的音频另存为 WAV 的目录")
+	voices := flag.String("voices", "", "引擎 GetVoices 未列出但允许请求的语音ID，逗号分隔")
+	allowedOrigins := flag.String("allowed-origins", "", "允许建立 WebSocket 会话的来源，逗号分隔，* 表示任意来源；为空时只允许同源")
+	debug := flag.Bool("debug", false, "输出调试日志")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(realtimetts.NewRedactingHandler(
+		slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	if *configPath == "" {
+		log.Fatal("请通过 -config 指定引擎链配置")
+	}
+	config, err := realtimetts.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	serverConfig := server.DefaultConfig()
+	serverConfig.Chain = config.EngineChain()
+	serverConfig.Audio = config.Audio
+	serverConfig.RecordDir = *recordDir
+	serverConfig.Logger = logger
+	if *voices != "" {
+		for _, voice := range strings.Split(*voices, ",") {
+			serverConfig.Voices = append(serverConfig.Voices, strings.TrimSpace(voice))
+		}
+	}
+	if *allowedOrigins != "" {
+		serverConfig.CheckOrigin = originChecker(strings.Split(*allowedOrigins, ","))
+	}
+
+	var stores []cache.Store
+	if *cacheMB > 0 {
+		stores = append(stores, cache.NewMemoryStore(*cacheMB<<20, 0))
+	}
+	if *cacheDir != "" {
+		disk, err := cache.NewDiskStore(*cacheDir, 0)
+		if err != nil {
+			log.Fatalf("创建磁盘缓存失败: %v", err)
+		}
+		stores = append(stores, disk)
+	}
+	if len(stores) > 0 {
+		serverConfig.Cache = cache.NewTieredStore(stores...)
+	}
+
+	tts, err := server.New(serverConfig)
+	if err != nil {
+		log.Fatalf("创建服务失败: %v", err)
+	}
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           tts,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalf("监听 gRPC 地址失败: %v", err)
+		}
+		grpcServer = grpc.NewServer()
+		tts.RegisterGRPC(grpcServer)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC 服务异常退出: %v", err)
+			}
+		}()
+		logger.Info("gRPC 服务已启动", "addr", *grpcAddr)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		// 等待进行中的合成结束，超时后强制关闭
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("关闭服务超时", realtimetts.LogKeyError, err)
+		}
+		if grpcServer != nil {
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				grpcServer.Stop()
+			}
+		}
+	}()
+
+	logger.Info("语音合成服务已启动", "addr", *addr)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("服务异常退出: %v", err)
+	}
+	<-shutdown
+	if err := tts.Close(); err != nil {
+		logger.Warn("关闭引擎失败", realtimetts.LogKeyError, err)
+	}
+}
+
+// originChecker 返回按来源列表检查 WebSocket 握手的函数
+func originChecker(origins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSpace(origin)] = true
+	}
+	return func(r *http.Request) bool {
+		return allowed["*"] || allowed[r.Header.Get("Origin")]
+	}
+}
@@ -0,0 +1,308 @@
+package engines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	realtimetts "realtimetts/pkg"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// ClipRule 预录音频规则
+// Text 与 Pattern 二选一：Text 按规范化后的文本精确匹配，Pattern 为正则表达式
+type ClipRule struct {
+	Text    string `json:"text,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	File    string `json:"file"`
+}
+
+// ClipManifest 预录音频清单文件
+type ClipManifest struct {
+	Clips []ClipRule `json:"clips"`
+}
+
+// clip 已解码并转换为输出格式的预录音频
+type clip struct {
+	file string
+	data []byte
+}
+
+// patternClip 按正则匹配的预录音频
+type patternClip struct {
+	pattern *regexp.Regexp
+	clip    *clip
+}
+
+// ClipEngine 预录音频引擎
+// 将已知短语映射到 WAV 文件，解码并转换为输出格式后按帧输出；
+// 未知文本通过 SupportsText 和 ErrTextNotSupported 拒绝，由故障切换链交给下一个引擎
+type ClipEngine struct {
+	*realtimetts.BaseEngine
+	name      string
+	format    *realtimetts.AudioConfiguration
+	chunkSize int
+
+	exact    map[string]*clip
+	patterns []patternClip
+	mu       sync.RWMutex
+}
+
+// NewClipEngine 创建新的预录音频引擎，format 为输出格式，为空时使用 16kHz 单声道 16 位
+func NewClipEngine(name string, format *realtimetts.AudioConfiguration) *ClipEngine {
+	if name == "" {
+		name = "Clips"
+	}
+	if format == nil {
+		format = &realtimetts.AudioConfiguration{
+			Format:        realtimetts.FormatWAV,
+			SampleRate:    16000,
+			Channels:      1,
+			BitsPerSample: 16,
+			Volume:        1.0,
+		}
+	}
+
+	return &ClipEngine{
+		BaseEngine: realtimetts.NewBaseEngine(name),
+		name:       name,
+		format:     format,
+		chunkSize:  3200,
+		exact:      make(map[string]*clip),
+	}
+}
+
+// SetChunkSize 设置每帧的最大字节数
+func (ce *ClipEngine) SetChunkSize(size int) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	if size > 0 {
+		ce.chunkSize = size
+	}
+}
+
+// normalizeClipText 规范化文本：合并空白，去除首尾空白和标点
+func normalizeClipText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.TrimFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+// loadClip 读取 WAV 文件并转换为输出格式
+func (ce *ClipEngine) loadClip(file string) (*clip, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("打开预录音频失败: %w", err)
+	}
+	defer f.Close()
+
+	data, format, err := realtimetts.DecodeWAV(f)
+	if err != nil {
+		return nil, fmt.Errorf("解码预录音频 %s 失败: %w", file, err)
+	}
+	data, err = realtimetts.ConvertPCM(data, format, ce.format)
+	if err != nil {
+		return nil, fmt.Errorf("转换预录音频 %s 失败: %w", file, err)
+	}
+	return &clip{file: file, data: data}, nil
+}
+
+// AddClip 为精确文本注册预录音频
+func (ce *ClipEngine) AddClip(text, file string) error {
+	key := normalizeClipText(text)
+	if key == "" {
+		return fmt.Errorf("预录音频文本为空: %s", file)
+	}
+	c, err := ce.loadClip(file)
+	if err != nil {
+		return err
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	ce.exact[key] = c
+	return nil
+}
+
+// AddPattern 为匹配正则表达式的文本注册预录音频，按注册顺序匹配
+func (ce *ClipEngine) AddPattern(pattern, file string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("无效的预录音频规则 %q: %w", pattern, err)
+	}
+	c, err := ce.loadClip(file)
+	if err != nil {
+		return err
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	ce.patterns = append(ce.patterns, patternClip{pattern: re, clip: c})
+	return nil
+}
+
+// LoadDirectory 注册目录下的所有 WAV 文件，文件名（不含扩展名）即对应的文本
+func (ce *ClipEngine) LoadDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("读取预录音频目录失败: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".wav") {
+			continue
+		}
+		text := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if err := ce.AddClip(text, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadManifest 读取 JSON 清单并注册其中的规则，相对路径相对于清单所在目录
+func (ce *ClipEngine) LoadManifest(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取预录音频清单失败: %w", err)
+	}
+	var manifest ClipManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return fmt.Errorf("解析预录音频清单失败: %w", err)
+	}
+
+	base := filepath.Dir(path)
+	for _, rule := range manifest.Clips {
+		file := rule.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(base, file)
+		}
+		switch {
+		case rule.Text != "" && rule.Pattern == "":
+			err = ce.AddClip(rule.Text, file)
+		case rule.Pattern != "" && rule.Text == "":
+			err = ce.AddPattern(rule.Pattern, file)
+		default:
+			err = fmt.Errorf("预录音频规则必须且只能指定 text 或 pattern 之一: %s", rule.File)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// match 查找文本对应的预录音频，精确匹配优先
+func (ce *ClipEngine) match(text string) *clip {
+	key := normalizeClipText(text)
+
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	if c, ok := ce.exact[key]; ok {
+		return c
+	}
+	for _, p := range ce.patterns {
+		if p.pattern.MatchString(key) {
+			return p.clip
+		}
+	}
+	return nil
+}
+
+// SupportsText 检查是否有文本对应的预录音频
+func (ce *ClipEngine) SupportsText(text string) bool {
+	return ce.match(text) != nil
+}
+
+// TTSEngine 接口实现
+
+// GetStreamInfo 返回音频配置信息
+func (ce *ClipEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	return ce.format
+}
+
+// Synthesize 输出文本对应的预录音频，未知文本返回 ErrTextNotSupported
+func (ce *ClipEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	c := ce.match(text)
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", realtimetts.ErrTextNotSupported, text)
+	}
+
+	ce.mu.RLock()
+	chunkSize := ce.chunkSize
+	ce.mu.RUnlock()
+	if bytesPerFrame := ce.format.GetBytesPerFrame(); bytesPerFrame > 0 {
+		chunkSize -= chunkSize % bytesPerFrame
+		if chunkSize <= 0 {
+			chunkSize = bytesPerFrame
+		}
+	}
+
+	stream := realtimetts.NewSynthesisStream(len(c.data)/chunkSize + 1)
+	go func() {
+		var pts time.Duration
+		for offset := 0; offset < len(c.data); offset += chunkSize {
+			end := offset + chunkSize
+			if end > len(c.data) {
+				end = len(c.data)
+			}
+			frame := realtimetts.NewAudioFrame(c.data[offset:end], ce.format)
+			frame.PTS = pts
+			pts += frame.Duration()
+			if err := stream.Send(ctx, frame); err != nil {
+				stream.CloseWithError(err)
+				return
+			}
+		}
+		stream.Close()
+	}()
+	return stream, nil
+}
+
+// GetVoices 获取可用语音列表，预录音频没有可选语音
+func (ce *ClipEngine) GetVoices() ([]realtimetts.Voice, error) {
+	return []realtimetts.Voice{}, nil
+}
+
+// SetVoice 设置使用的语音，预录音频忽略此设置
+func (ce *ClipEngine) SetVoice(voice realtimetts.Voice) error {
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数，预录音频忽略此设置
+func (ce *ClipEngine) SetVoiceParameters(params map[string]interface{}) error {
+	return nil
+}
+
+// GetEngineInfo 获取引擎信息
+func (ce *ClipEngine) GetEngineInfo() realtimetts.EngineInfo {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	return realtimetts.EngineInfo{
+		Name:         ce.name,
+		Version:      "1.0.0",
+		Description:  "预录音频引擎",
+		Capabilities: []string{"pcm-format", "prerecorded", "text-routing"},
+		Config: map[string]string{
+			"clips":    fmt.Sprint(len(ce.exact)),
+			"patterns": fmt.Sprint(len(ce.patterns)),
+		},
+	}
+}
+
+// Initialize 初始化引擎
+func (ce *ClipEngine) Initialize() error {
+	return nil
+}
+
+// Close 关闭引擎
+func (ce *ClipEngine) Close() error {
+	return nil
+}
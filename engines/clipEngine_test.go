@@ -0,0 +1,143 @@
+package engines_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// writeTestWAV 写入指定时长的 WAV 文件
+func writeTestWAV(t *testing.T, path string, duration time.Duration, format *realtimetts.AudioConfiguration) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	samples := int(duration * time.Duration(format.SampleRate) / time.Second)
+	data := make([]byte, samples*format.GetBytesPerFrame())
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := realtimetts.EncodeWAV(f, data, format); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClipEngineMatchesAndConverts(t *testing.T) {
+	dir := t.TempDir()
+	stereo8k := &realtimetts.AudioConfiguration{SampleRate: 8000, Channels: 2, BitsPerSample: 16}
+	writeTestWAV(t, filepath.Join(dir, "请稍等.wav"), 500*time.Millisecond, stereo8k)
+	writeTestWAV(t, filepath.Join(dir, "order.wav"), 200*time.Millisecond, stereo8k)
+
+	manifest := `{"clips": [{"pattern": "^您的订单\\d+已发货$", "file": "order.wav"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "clips.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := engines.NewClipEngine("", nil)
+	if err := engine.LoadDirectory(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.LoadManifest(filepath.Join(dir, "clips.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !engine.SupportsText(" 请稍等。") || !engine.SupportsText("您的订单123已发货") {
+		t.Fatal("应匹配精确文本和正则规则")
+	}
+	if engine.SupportsText("今天天气怎么样") {
+		t.Fatal("未知文本应被拒绝")
+	}
+	if _, err := engine.Synthesize(context.Background(), "今天天气怎么样"); !errors.Is(err, realtimetts.ErrTextNotSupported) {
+		t.Fatalf("未知文本应返回 ErrTextNotSupported, 实际: %v", err)
+	}
+
+	frames, err := collectFrames(t, engine, "请稍等")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total time.Duration
+	for _, frame := range frames {
+		if !realtimetts.SameFormat(frame.Format, engine.GetStreamInfo()) {
+			t.Fatalf("帧格式应为输出格式: %+v", frame.Format)
+		}
+		total += frame.Duration()
+	}
+	if total != 500*time.Millisecond {
+		t.Fatalf("转换后时长 %v, 期望 500ms", total)
+	}
+}
+
+func TestTextToAudioStreamRoutesKnownPhrasesToClips(t *testing.T) {
+	dir := t.TempDir()
+	writeTestWAV(t, filepath.Join(dir, "请稍等.wav"), 100*time.Millisecond, realtimetts.DefaultAudioConfig())
+
+	clips := engines.NewClipEngine("clips", nil)
+	if err := clips.LoadDirectory(dir); err != nil {
+		t.Fatal(err)
+	}
+	live := engines.NewToneEngine(engines.ToneConfig{Name: "live", WordDuration: 20 * time.Millisecond})
+
+	config := realtimetts.DefaultStreamConfig()
+	config.SentenceSilenceDuration = 0
+	config.Output = realtimetts.NewNullOutput(config.AudioConfig, false)
+	stream := realtimetts.NewTextToAudioStream([]realtimetts.TTSEngine{clips, live}, config)
+	defer stream.Close()
+
+	var mu sync.Mutex
+	var reasons []string
+	var engineErrors []error
+	done := make(chan struct{})
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnEngineSwitch = func(from, to, reason string) {
+		mu.Lock()
+		reasons = append(reasons, reason)
+		mu.Unlock()
+	}
+	callbacks.OnEngineError = func(name string, err error) {
+		mu.Lock()
+		engineErrors = append(engineErrors, err)
+		mu.Unlock()
+	}
+	callbacks.OnAudioStreamStop = func() { close(done) }
+	stream.SetCallbacks(callbacks)
+
+	if err := stream.Feed("请稍等. 正在为您查询. 请稍等."); err != nil {
+		t.Fatal(err)
+	}
+	stream.PlayAsync()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待播放结束超时")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(engineErrors) != 0 {
+		t.Fatalf("拒绝文本不应视为引擎错误: %v", engineErrors)
+	}
+	if live.GetCallCount() != 1 {
+		t.Fatalf("只有未知文本应交给在线引擎, 调用 %d 次", live.GetCallCount())
+	}
+	for _, reason := range reasons {
+		if reason != realtimetts.SwitchReasonRouting {
+			t.Fatalf("切换原因应为按文本选择: %v", reasons)
+		}
+	}
+	for _, health := range stream.GetEngineHealth() {
+		if health.ErrorRate != 0 {
+			t.Fatalf("拒绝文本不应影响健康评分: %+v", health)
+		}
+	}
+}
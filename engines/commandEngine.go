@@ -0,0 +1,546 @@
+package engines
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	realtimetts "realtimetts/pkg"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 命令输出格式
+const (
+	CommandOutputPCM = "pcm" // 原始小端 PCM，格式由配置指定
+	CommandOutputWAV = "wav" // WAV，格式从文件头读取
+)
+
+// CommandConfig 外部命令引擎配置
+// Args 中的 {text}、{voice} 以及 SetVoiceParameters 设置的 {参数名} 会被替换；
+// 包含 {text} 时文本作为参数传入，否则写入标准输入。文本原样传入，其中的占位符不会被展开。
+// 以 - 开头的文本会被命令当作选项，因此被拒绝；需要支持时在 {text} 之前加入 "--" 参数
+type CommandConfig struct {
+	Name          string              `json:"name"`            // 引擎名称
+	Command       string              `json:"command"`         // 可执行文件
+	Args          []string            `json:"args"`            // 命令参数
+	Env           []string            `json:"env"`             // 额外的环境变量 (KEY=VALUE)
+	Dir           string              `json:"dir"`             // 工作目录
+	OutputFormat  string              `json:"output_format"`   // 标准输出格式: pcm / wav
+	SampleRate    int                 `json:"sample_rate"`     // pcm 输出的采样率
+	Channels      int                 `json:"channels"`        // pcm 输出的声道数
+	BitsPerSample int                 `json:"bits_per_sample"` // pcm 输出的位深度
+	ChunkSize     int                 `json:"chunk_size"`      // 每帧的最大字节数
+	Voices        []realtimetts.Voice `json:"voices"`
+
+	// Persistent 常驻进程模式：进程只启动一次，每句文本作为一行写入标准输入，
+	// 仅支持 pcm 输出。每句音频的结束必须由 EndMarker 标识：按输出停顿判断会把
+	// 停顿后的音频算入下一句
+	Persistent bool   `json:"persistent"`
+	EndMarker  []byte `json:"end_marker"`
+}
+
+// DefaultCommandConfig 返回默认外部命令引擎配置（适用于 Piper 的 --output-raw）
+func DefaultCommandConfig() CommandConfig {
+	return CommandConfig{
+		Name:          "Command TTS",
+		OutputFormat:  CommandOutputPCM,
+		SampleRate:    22050,
+		Channels:      1,
+		BitsPerSample: 16,
+		ChunkSize:     4096,
+	}
+}
+
+// CommandEngine 外部命令引擎
+// 启动本地 TTS 程序（如 Piper、eSpeak-NG），把标准输出作为音频流按帧输出；
+// ctx 取消时终止进程。常驻进程模式下多句请求依次复用同一个进程
+type CommandEngine struct {
+	*realtimetts.BaseEngine
+	config CommandConfig
+	voice  string
+	params map[string]string
+	mu     sync.RWMutex
+
+	// 常驻进程，请求之间由 procMu 串行化
+	procMu sync.Mutex
+	proc   *commandProcess
+}
+
+// NewCommandEngine 创建新的外部命令引擎，配置中的零值使用默认值
+func NewCommandEngine(config CommandConfig) *CommandEngine {
+	defaults := DefaultCommandConfig()
+	if config.Name == "" {
+		config.Name = defaults.Name
+	}
+	if config.OutputFormat == "" {
+		config.OutputFormat = defaults.OutputFormat
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.Channels <= 0 {
+		config.Channels = defaults.Channels
+	}
+	if config.BitsPerSample <= 0 {
+		config.BitsPerSample = defaults.BitsPerSample
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaults.ChunkSize
+	}
+
+	return &CommandEngine{
+		BaseEngine: realtimetts.NewBaseEngine(config.Name),
+		config:     config,
+		params:     make(map[string]string),
+	}
+}
+
+// buildArgs 替换参数中的占位符，返回参数和文本是否已作为参数传入
+// 先替换模板中的其他占位符，最后一次性填入文本，文本中的占位符保持原样
+func (ce *CommandEngine) buildArgs(text string) ([]string, bool, error) {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	pairs := []string{"{voice}", ce.voice}
+	for k, v := range ce.params {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	args := make([]string, len(ce.config.Args))
+	inArgs, separated := false, false
+	for i, arg := range ce.config.Args {
+		parts := strings.Split(arg, "{text}")
+		for j, part := range parts {
+			parts[j] = replacer.Replace(part)
+		}
+		if len(parts) > 1 {
+			inArgs = true
+			if parts[0] == "" && strings.HasPrefix(text, "-") && !separated {
+				return nil, false, &realtimetts.EngineError{
+					Engine:  ce.config.Name,
+					Kind:    realtimetts.ErrEngineInvalidInput,
+					Message: "文本以 - 开头，会被命令当作选项；需在 {text} 前配置 \"--\" 参数",
+				}
+			}
+		}
+		args[i] = strings.Join(parts, text)
+		if arg == "--" {
+			separated = true
+		}
+	}
+	return args, inArgs, nil
+}
+
+// newCmd 创建命令，ctx 取消时进程被终止
+func (ce *CommandEngine) newCmd(ctx context.Context, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, ce.config.Command, args...)
+	cmd.Dir = ce.config.Dir
+	if len(ce.config.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), ce.config.Env...)
+	}
+	return cmd
+}
+
+// pcmFormat 返回配置的 pcm 输出格式
+func (ce *CommandEngine) pcmFormat() *realtimetts.AudioConfiguration {
+	return &realtimetts.AudioConfiguration{
+		Format:        realtimetts.FormatWAV,
+		Channels:      ce.config.Channels,
+		SampleRate:    ce.config.SampleRate,
+		BitsPerSample: ce.config.BitsPerSample,
+		Volume:        1.0,
+	}
+}
+
+// commandError 将进程失败包装为分类错误，附带标准错误输出
+func (ce *CommandEngine) commandError(err error, stderr *limitedBuffer) error {
+	message := strings.TrimSpace(stderr.String())
+	if message == "" {
+		message = err.Error()
+	}
+	return &realtimetts.EngineError{
+		Engine:  ce.config.Name,
+		Kind:    realtimetts.ErrEngineServer,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// DoSynthesize 执行合成，将命令输出按帧写入 outputChan
+func (ce *CommandEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	startTime := time.Now()
+	var err error
+	if ce.config.Persistent {
+		err = ce.synthesizePersistent(ctx, text, outputChan)
+	} else {
+		err = ce.synthesizeOnce(ctx, text, outputChan)
+	}
+	if err != nil && ctx.Err() == nil {
+		ce.Logger().Warn("命令合成失败", realtimetts.LogKeyError, err, "duration", time.Since(startTime))
+	}
+	return err
+}
+
+// synthesizeOnce 为一句文本启动一次命令
+func (ce *CommandEngine) synthesizeOnce(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	args, inArgs, err := ce.buildArgs(text)
+	if err != nil {
+		return err
+	}
+	cmd := ce.newCmd(ctx, args)
+	if !inArgs {
+		cmd.Stdin = strings.NewReader(text)
+	}
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动命令失败: %w", err)
+	}
+
+	// 进程被终止后其子进程可能仍持有标准输出，关闭读端保证读取及时返回
+	stop := context.AfterFunc(ctx, func() { stdout.Close() })
+	defer stop()
+
+	streamErr := ce.streamOutput(ctx, stdout, outputChan)
+	if streamErr != nil {
+		// 提前结束时终止进程，避免其阻塞在写标准输出上
+		cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+
+	// 进程以非零状态自行退出时以其标准错误输出为准，比读取输出时的错误更有用
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case errors.As(waitErr, &exitErr) && exitErr.Exited():
+		return ce.commandError(waitErr, stderr)
+	case streamErr != nil:
+		return streamErr
+	case waitErr != nil:
+		return ce.commandError(waitErr, stderr)
+	}
+	return nil
+}
+
+// streamOutput 读取单次命令的标准输出直到 EOF
+func (ce *CommandEngine) streamOutput(ctx context.Context, stdout io.Reader, outputChan chan<- realtimetts.Frame) error {
+	format := ce.pcmFormat()
+	reader := bufio.NewReader(stdout)
+	if ce.config.OutputFormat == CommandOutputWAV {
+		header, _, err := realtimetts.ReadWAVHeader(reader)
+		if err != nil {
+			return fmt.Errorf("读取命令输出的WAV头失败: %w", err)
+		}
+		format = header
+	}
+
+	chunker := realtimetts.NewFrameChunker(format, ce.config.ChunkSize)
+	buf := make([]byte, ce.config.ChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if sendErr := sendFrames(ctx, outputChan, chunker.Push(buf[:n])); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取命令输出失败: %w", err)
+		}
+	}
+}
+
+// sendFrames 依次发送帧
+func sendFrames(ctx context.Context, outputChan chan<- realtimetts.Frame, frames []realtimetts.Frame) error {
+	for _, frame := range frames {
+		select {
+		case outputChan <- frame:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// commandProcess 常驻进程
+type commandProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	output chan []byte // 标准输出数据，进程退出后关闭
+	stderr *limitedBuffer
+}
+
+// startProcess 启动常驻进程，调用方需持有 procMu
+func (ce *CommandEngine) startProcess() (*commandProcess, error) {
+	args, inArgs, _ := ce.buildArgs("")
+	if inArgs {
+		return nil, fmt.Errorf("常驻进程模式不支持通过参数传入文本")
+	}
+
+	// 常驻进程的生命周期不受单句 ctx 约束，由 stopProcess 终止
+	cmd := ce.newCmd(context.Background(), args)
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("启动命令失败: %w", err)
+	}
+
+	ce.Logger().Info("启动常驻进程", "command", ce.config.Command, "pid", cmd.Process.Pid)
+
+	proc := &commandProcess{cmd: cmd, stdin: stdin, output: make(chan []byte, 16), stderr: stderr}
+	go func() {
+		defer close(proc.output)
+		for {
+			buf := make([]byte, ce.config.ChunkSize)
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				proc.output <- buf[:n]
+			}
+			if err != nil {
+				cmd.Wait()
+				return
+			}
+		}
+	}()
+	return proc, nil
+}
+
+// stopProcess 终止常驻进程，调用方需持有 procMu
+func (ce *CommandEngine) stopProcess() {
+	if ce.proc == nil {
+		return
+	}
+	ce.proc.stdin.Close()
+	ce.proc.cmd.Process.Kill()
+	for range ce.proc.output {
+	}
+	ce.proc = nil
+}
+
+// synthesizePersistent 通过常驻进程合成一句文本
+func (ce *CommandEngine) synthesizePersistent(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	if ce.config.OutputFormat != CommandOutputPCM {
+		return fmt.Errorf("常驻进程模式仅支持 pcm 输出")
+	}
+	if len(ce.config.EndMarker) == 0 {
+		return fmt.Errorf("%w: 常驻进程模式需要设置 end_marker", realtimetts.ErrInvalidEngineConfig)
+	}
+
+	ce.procMu.Lock()
+	defer ce.procMu.Unlock()
+
+	if ce.proc == nil {
+		proc, err := ce.startProcess()
+		if err != nil {
+			return err
+		}
+		ce.proc = proc
+	}
+	proc := ce.proc
+
+	// 每句一行，文本中的换行会被当作多句请求
+	line := strings.Join(strings.Fields(text), " ") + "\n"
+	if _, err := io.WriteString(proc.stdin, line); err != nil {
+		ce.stopProcess()
+		return ce.commandError(err, proc.stderr)
+	}
+
+	err := ce.readUntilEnd(ctx, proc, outputChan)
+	if err != nil {
+		// 输出可能只读了一半，残留数据会混入下一句，因此重启进程
+		ce.stopProcess()
+	}
+	return err
+}
+
+// readUntilEnd 读取常驻进程的输出直到结束标记
+func (ce *CommandEngine) readUntilEnd(ctx context.Context, proc *commandProcess, outputChan chan<- realtimetts.Frame) error {
+	chunker := realtimetts.NewFrameChunker(ce.pcmFormat(), ce.config.ChunkSize)
+	marker := ce.config.EndMarker
+	var held []byte // 可能是结束标记前缀的尾部数据
+
+	for {
+		select {
+		case data, ok := <-proc.output:
+			if !ok {
+				return ce.commandError(errors.New("命令进程已退出"), proc.stderr)
+			}
+			held = append(held, data...)
+			if i := bytes.Index(held, marker); i >= 0 {
+				return sendFrames(ctx, outputChan, chunker.Push(held[:i]))
+			}
+			// 保留可能构成结束标记前缀的尾部
+			keep := len(marker) - 1
+			if keep > len(held) {
+				keep = len(held)
+			}
+			emit := held[:len(held)-keep]
+			if err := sendFrames(ctx, outputChan, chunker.Push(emit)); err != nil {
+				return err
+			}
+			held = append([]byte(nil), held[len(held)-keep:]...)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// limitedBuffer 只保留前 limit 字节的缓冲区，用于收集标准错误输出
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write 写入数据，超出上限的部分被丢弃
+func (lb *limitedBuffer) Write(p []byte) (int, error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if remaining := lb.limit - lb.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			lb.buf.Write(p[:remaining])
+		} else {
+			lb.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// String 返回收集的内容
+func (lb *limitedBuffer) String() string {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.buf.String()
+}
+
+// TTSEngine 接口实现
+
+// GetStreamInfo 返回音频配置信息
+// wav 输出的实际格式以文件头为准，帧中携带的格式会覆盖此处的配置
+func (ce *CommandEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	return ce.pcmFormat()
+}
+
+// Synthesize 执行文本到音频的合成
+// 命令失败时通过返回流的 Err 报告错误，错误信息包含标准错误输出
+func (ce *CommandEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(100)
+
+	go func() {
+		stream.CloseWithError(ce.DoSynthesize(ctx, text, stream.Writer()))
+	}()
+
+	return stream, nil
+}
+
+// GetVoices 获取可用语音列表
+func (ce *CommandEngine) GetVoices() ([]realtimetts.Voice, error) {
+	return ce.config.Voices, nil
+}
+
+// SetVoice 设置使用的语音，替换参数中的 {voice}
+// 常驻进程会在下一句时以新参数重启
+func (ce *CommandEngine) SetVoice(voice realtimetts.Voice) error {
+	ce.mu.Lock()
+	ce.voice = voice.ID
+	ce.mu.Unlock()
+
+	ce.restartPersistent()
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数，替换参数中对应的 {参数名}
+func (ce *CommandEngine) SetVoiceParameters(params map[string]interface{}) error {
+	ce.mu.Lock()
+	for k, v := range params {
+		ce.params[k] = fmt.Sprint(v)
+	}
+	ce.mu.Unlock()
+
+	ce.restartPersistent()
+	return nil
+}
+
+// restartPersistent 终止常驻进程，下一句合成时重新启动
+func (ce *CommandEngine) restartPersistent() {
+	ce.procMu.Lock()
+	defer ce.procMu.Unlock()
+	ce.stopProcess()
+}
+
+// GetEngineInfo 获取引擎信息
+func (ce *CommandEngine) GetEngineInfo() realtimetts.EngineInfo {
+	mode := "per-sentence"
+	if ce.config.Persistent {
+		mode = "persistent"
+	}
+	return realtimetts.EngineInfo{
+		Name:         ce.config.Name,
+		Version:      "1.0.0",
+		Description:  "外部命令语音合成",
+		Capabilities: []string{"pcm-format", "real-time-synthesis", "offline", "text-to-speech"},
+		Config: map[string]string{
+			"command": ce.config.Command,
+			"output":  ce.config.OutputFormat,
+			"mode":    mode,
+		},
+	}
+}
+
+// Fingerprint 实现 realtimetts.Fingerprinter，包含命令、参数、当前语音和语音参数，不含敏感的环境变量
+func (ce *CommandEngine) Fingerprint() string {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	config := ce.config
+	config.Env = nil
+	for _, kv := range ce.config.Env {
+		if key, _, _ := strings.Cut(kv, "="); !realtimetts.IsSensitiveKey(key) {
+			config.Env = append(config.Env, kv)
+		}
+	}
+	return realtimetts.FingerprintOf(struct {
+		Config CommandConfig
+		Voice  string
+		Params map[string]string
+	}{config, ce.voice, ce.params})
+}
+
+// Initialize 初始化引擎，检查命令是否存在
+func (ce *CommandEngine) Initialize() error {
+	if _, err := exec.LookPath(ce.config.Command); err != nil {
+		return fmt.Errorf("%w: %v", realtimetts.ErrEngineNotInitialized, err)
+	}
+	return nil
+}
+
+// Close 关闭引擎，终止常驻进程
+func (ce *CommandEngine) Close() error {
+	ce.procMu.Lock()
+	defer ce.procMu.Unlock()
+	ce.stopProcess()
+	return nil
+}
@@ -0,0 +1,174 @@
+package engines_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// writeStubScript 写入模拟本地 TTS 程序的 shell 脚本
+func writeStubScript(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("需要 /bin/sh")
+	}
+	path := filepath.Join(t.TempDir(), "tts.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// audioBytes 统计帧中的音频字节数
+func audioBytes(frames []realtimetts.Frame) int {
+	total := 0
+	for _, frame := range frames {
+		total += len(frame.Data)
+	}
+	return total
+}
+
+func TestCommandEngineStreamsStdout(t *testing.T) {
+	// 每个输入字节输出 100 字节的静音 PCM
+	script := writeStubScript(t, `n=$(wc -c); head -c $((n * 100)) /dev/zero`)
+	engine := engines.NewCommandEngine(engines.CommandConfig{Command: script, SampleRate: 16000, ChunkSize: 1000})
+
+	frames, err := collectFrames(t, engine, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := audioBytes(frames); got != 500 {
+		t.Fatalf("输出 %d 字节, 期望 500", got)
+	}
+	for _, frame := range frames {
+		if len(frame.Data)%2 != 0 || len(frame.Data) > 1000 {
+			t.Fatalf("帧未按采样帧对齐或超过 ChunkSize: %d", len(frame.Data))
+		}
+	}
+}
+
+func TestCommandEngineTextArgumentAndWAV(t *testing.T) {
+	dir := t.TempDir()
+	wav := filepath.Join(dir, "out.wav")
+	writeTestWAV(t, wav, 100*time.Millisecond, &realtimetts.AudioConfiguration{SampleRate: 8000, Channels: 1, BitsPerSample: 16})
+
+	script := writeStubScript(t, `[ "$1" = "你好" ] || { echo "unexpected text: $1" >&2; exit 3; }; cat "$2"`)
+	engine := engines.NewCommandEngine(engines.CommandConfig{
+		Command:      script,
+		Args:         []string{"{text}", wav},
+		OutputFormat: engines.CommandOutputWAV,
+	})
+
+	frames, err := collectFrames(t, engine, "你好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) == 0 || frames[0].Format.SampleRate != 8000 || audioBytes(frames) != 1600 {
+		t.Fatalf("应按WAV头的格式输出: %d 帧, %d 字节", len(frames), audioBytes(frames))
+	}
+
+	_, err = collectFrames(t, engine, "再见")
+	var engineErr *realtimetts.EngineError
+	if !errors.As(err, &engineErr) || engineErr.Message != "unexpected text: 再见" {
+		t.Fatalf("应报告包含标准错误输出的错误: %v", err)
+	}
+}
+
+func TestCommandEnginePassesTextVerbatim(t *testing.T) {
+	// 把第一个参数原样输出为音频，便于比较传入的文本
+	script := writeStubScript(t, `printf '%s' "$1"`)
+	engine := engines.NewCommandEngine(engines.CommandConfig{Command: script, Args: []string{"{text}", "{voice}"}})
+	engine.SetVoice(realtimetts.Voice{ID: "zh"})
+	engine.SetVoiceParameters(map[string]interface{}{"speed": 1.5})
+
+	const text = "说 {voice} 和 {speed}!"
+	frames, err := collectFrames(t, engine, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	for _, frame := range frames {
+		got = append(got, frame.Data...)
+	}
+	if string(got) != text {
+		t.Fatalf("文本中的占位符不应展开: %q", got)
+	}
+}
+
+func TestCommandEngineRejectsOptionLikeText(t *testing.T) {
+	script := writeStubScript(t, `[ "$1" = "--" ] && [ "$2" = "-5 度" ] || { echo "unexpected args: $*" >&2; exit 3; }; printf 'ok'`)
+
+	engine := engines.NewCommandEngine(engines.CommandConfig{Command: script, Args: []string{"{text}"}})
+	_, err := collectFrames(t, engine, "-5 度")
+	if !errors.Is(err, realtimetts.ErrEngineInvalidInput) {
+		t.Fatalf("以 - 开头的文本应被拒绝: %v", err)
+	}
+
+	engine = engines.NewCommandEngine(engines.CommandConfig{Command: script, Args: []string{"--", "{text}"}})
+	if _, err := collectFrames(t, engine, "-5 度"); err != nil {
+		t.Fatalf("配置 -- 分隔后应原样传入: %v", err)
+	}
+}
+
+func TestCommandEngineKillsProcessOnCancel(t *testing.T) {
+	script := writeStubScript(t, `head -c 3200 /dev/zero; exec sleep 30`)
+	engine := engines.NewCommandEngine(engines.CommandConfig{Command: script})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := engine.Synthesize(ctx, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-stream.Frames()
+	start := time.Now()
+	cancel()
+	for range stream.Frames() {
+	}
+
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Fatalf("应以取消结束: %v", stream.Err())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("取消后进程未及时终止: %v", elapsed)
+	}
+}
+
+func TestCommandEnginePersistentProcess(t *testing.T) {
+	dir := t.TempDir()
+	starts := filepath.Join(dir, "starts")
+	script := writeStubScript(t, `echo start >> "`+starts+`"
+while read line; do head -c $((${#line} * 10)) /dev/zero; printf '<END>'; done`)
+
+	engine := engines.NewCommandEngine(engines.CommandConfig{
+		Command:    script,
+		Persistent: true,
+		EndMarker:  []byte("<END>"),
+		ChunkSize:  64,
+	})
+	defer engine.Close()
+
+	for _, text := range []string{"one", "three", "eleven"} {
+		frames, err := collectFrames(t, engine, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := audioBytes(frames); got != len(text)*10 {
+			t.Fatalf("%q 输出 %d 字节, 期望 %d", text, got, len(text)*10)
+		}
+	}
+
+	data, err := os.ReadFile(starts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "start\n" {
+		t.Fatalf("常驻进程应只启动一次: %q", data)
+	}
+}
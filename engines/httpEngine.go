@@ -0,0 +1,704 @@
+package engines
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	realtimetts "realtimetts/pkg"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// HTTP 引擎的响应模式
+const (
+	HTTPResponseRaw     = "raw"     // 响应体即音频，边到达边输出
+	HTTPResponseJSON    = "json"    // 单个 JSON 响应，音频为 base64 字段
+	HTTPResponseChunked = "chunked" // 分块传输的逐行 JSON，每行含一段 base64 音频
+	HTTPResponseSSE     = "sse"     // Server-Sent Events，每个事件的 data 含一段 base64 音频
+)
+
+// HTTP 引擎的认证方式
+const (
+	HTTPAuthNone   = ""
+	HTTPAuthBearer = "bearer" // Authorization: Bearer <token>
+	HTTPAuthHeader = "header" // <name>: <prefix><token>
+	HTTPAuthQuery  = "query"  // URL 参数 <name>=<token>
+	HTTPAuthBasic  = "basic"  // HTTP Basic 认证
+)
+
+// HTTP 引擎的音频格式
+const (
+	HTTPAudioPCM = "pcm"
+	HTTPAudioWAV = "wav"
+)
+
+// HTTPAuthConfig 认证配置
+type HTTPAuthConfig struct {
+	Scheme   string `json:"scheme"`
+	Token    string `json:"token"`
+	Name     string `json:"name"`   // header/query 方式的头或参数名
+	Prefix   string `json:"prefix"` // header 方式的值前缀，例如 "Bearer;"
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// HTTPResponseConfig 响应解析配置
+// 字段路径以点分隔，数组元素用下标表示，例如 "data.audio" 或 "choices.0.audio"
+type HTTPResponseConfig struct {
+	Mode         string   `json:"mode"`
+	AudioField   string   `json:"audio_field"`   // base64 音频字段；SSE 模式为空时 data 本身即 base64
+	ErrorField   string   `json:"error_field"`   // 错误信息字段，非空即视为失败
+	CodeField    string   `json:"code_field"`    // 服务商状态码字段
+	SuccessCodes []string `json:"success_codes"` // 表示成功的状态码，设置 CodeField 时必填
+	MessageField string   `json:"message_field"` // 状态码失败时的错误信息字段
+	DoneValue    string   `json:"done_value"`    // SSE 结束标记，默认 [DONE]
+}
+
+// HTTPEngineConfig 声明式 HTTP/JSON 引擎配置
+// URL 和 Body 为 text/template 模板，可用 .Text、.Voice、.Params，
+// 以及 json（JSON 编码）、query（URL 编码）、default（默认值）函数，例如
+//
+//	{"text": {{json .Text}}, "voice": {{json .Voice}}, "speed": {{default 1.0 .Params.speed}}}
+type HTTPEngineConfig struct {
+	Name     string                 `json:"name"`
+	Method   string                 `json:"method"` // 默认 POST
+	URL      string                 `json:"url"`
+	Headers  map[string]string      `json:"headers"`
+	Body     string                 `json:"body"`
+	Auth     HTTPAuthConfig         `json:"auth"`
+	Response HTTPResponseConfig     `json:"response"`
+	Voice    string                 `json:"voice"`
+	Voices   []realtimetts.Voice    `json:"voices"`
+	Params   map[string]interface{} `json:"params"`
+
+	AudioFormat   string `json:"audio_format"` // pcm / wav
+	SampleRate    int    `json:"sample_rate"`  // pcm 音频的采样率
+	Channels      int    `json:"channels"`
+	BitsPerSample int    `json:"bits_per_sample"`
+	ChunkSize     int    `json:"chunk_size"` // 每帧的最大字节数
+}
+
+// httpTemplateData 模板数据
+type httpTemplateData struct {
+	Text   string
+	Voice  string
+	Params map[string]interface{}
+}
+
+// httpTemplateFuncs 模板函数
+var httpTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+	"query": url.QueryEscape,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// HTTPEngine 声明式 HTTP/JSON 引擎
+// 请求模板、认证、响应解析和音频格式均由配置描述，简单的服务商无需编写代码即可接入
+type HTTPEngine struct {
+	*realtimetts.BaseEngine
+	client  *http.Client
+	config  HTTPEngineConfig
+	urlTmpl *template.Template
+	body    *template.Template
+	retry   *realtimetts.RetryPolicy
+
+	// 凭据来源，设置后其提供的 token、username、password 优先于 config.Auth
+	credentials realtimetts.CredentialProvider
+	mu          sync.RWMutex
+}
+
+// NewHTTPEngine 创建新的声明式 HTTP 引擎，配置无效时返回错误
+func NewHTTPEngine(config HTTPEngineConfig) (*HTTPEngine, error) {
+	if config.Name == "" {
+		config.Name = "HTTP TTS"
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.Response.Mode == "" {
+		config.Response.Mode = HTTPResponseRaw
+	}
+	if config.Response.DoneValue == "" {
+		config.Response.DoneValue = "[DONE]"
+	}
+	if config.AudioFormat == "" {
+		config.AudioFormat = HTTPAudioPCM
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = 16000
+	}
+	if config.Channels <= 0 {
+		config.Channels = 1
+	}
+	if config.BitsPerSample <= 0 {
+		config.BitsPerSample = 16
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = 4096
+	}
+	if config.Params == nil {
+		config.Params = make(map[string]interface{})
+	}
+
+	if config.URL == "" {
+		return nil, fmt.Errorf("HTTP 引擎 %s 未配置 URL", config.Name)
+	}
+	switch config.Response.Mode {
+	case HTTPResponseRaw, HTTPResponseJSON, HTTPResponseChunked, HTTPResponseSSE:
+	default:
+		return nil, fmt.Errorf("HTTP 引擎 %s 不支持的响应模式: %s", config.Name, config.Response.Mode)
+	}
+	if config.Response.Mode != HTTPResponseRaw && config.Response.Mode != HTTPResponseSSE && config.Response.AudioField == "" {
+		return nil, fmt.Errorf("HTTP 引擎 %s 的 %s 响应模式需要 audio_field", config.Name, config.Response.Mode)
+	}
+	if config.Response.CodeField != "" && len(config.Response.SuccessCodes) == 0 {
+		return nil, fmt.Errorf("HTTP 引擎 %s 设置了 code_field 但未设置 success_codes", config.Name)
+	}
+	switch config.AudioFormat {
+	case HTTPAudioPCM, HTTPAudioWAV:
+	default:
+		return nil, fmt.Errorf("%w: %s", realtimetts.ErrUnsupportedFormat, config.AudioFormat)
+	}
+	switch config.Auth.Scheme {
+	case HTTPAuthNone, HTTPAuthBearer, HTTPAuthBasic:
+	case HTTPAuthHeader, HTTPAuthQuery:
+		if config.Auth.Name == "" {
+			return nil, fmt.Errorf("HTTP 引擎 %s 的 %s 认证需要 name", config.Name, config.Auth.Scheme)
+		}
+	default:
+		return nil, fmt.Errorf("HTTP 引擎 %s 不支持的认证方式: %s", config.Name, config.Auth.Scheme)
+	}
+
+	realtimetts.RegisterSecret(config.Auth.Token)
+	realtimetts.RegisterSecret(config.Auth.Password)
+
+	urlTmpl, err := template.New("url").Funcs(httpTemplateFuncs).Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("解析 URL 模板失败: %w", err)
+	}
+	body, err := template.New("body").Funcs(httpTemplateFuncs).Parse(config.Body)
+	if err != nil {
+		return nil, fmt.Errorf("解析请求体模板失败: %w", err)
+	}
+
+	return &HTTPEngine{
+		BaseEngine: realtimetts.NewBaseEngine(config.Name),
+		client:     &http.Client{},
+		config:     config,
+		urlTmpl:    urlTmpl,
+		body:       body,
+		retry:      realtimetts.DefaultRetryPolicy(),
+	}, nil
+}
+
+// SetHTTPClient 设置 HTTP 客户端
+func (he *HTTPEngine) SetHTTPClient(client *http.Client) {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+	he.client = client
+}
+
+// SetCredentialProvider 设置凭据来源，每次请求前获取 token、username 和 password
+func (he *HTTPEngine) SetCredentialProvider(provider realtimetts.CredentialProvider) {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+	he.credentials = provider
+}
+
+// SetRetryPolicy 设置请求重试策略，传入 nil 表示不重试
+func (he *HTTPEngine) SetRetryPolicy(policy *realtimetts.RetryPolicy) {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+
+	if policy == nil {
+		policy = &realtimetts.RetryPolicy{MaxAttempts: 1}
+	}
+	he.retry = policy
+}
+
+// GetRetryPolicy 获取请求重试策略
+func (he *HTTPEngine) GetRetryPolicy() *realtimetts.RetryPolicy {
+	he.mu.RLock()
+	defer he.mu.RUnlock()
+	return he.retry
+}
+
+// templateData 生成当前语音设置下的模板数据
+func (he *HTTPEngine) templateData(text string) httpTemplateData {
+	he.mu.RLock()
+	defer he.mu.RUnlock()
+
+	params := make(map[string]interface{}, len(he.config.Params))
+	for k, v := range he.config.Params {
+		params[k] = v
+	}
+	return httpTemplateData{Text: text, Voice: he.config.Voice, Params: params}
+}
+
+// resolveAuth 返回本次请求使用的认证配置，设置了凭据来源时以其提供的凭据为准
+func (he *HTTPEngine) resolveAuth(ctx context.Context) (HTTPAuthConfig, error) {
+	he.mu.RLock()
+	auth, provider := he.config.Auth, he.credentials
+	he.mu.RUnlock()
+
+	if provider == nil {
+		return auth, nil
+	}
+	credentials, err := provider.Credentials(ctx)
+	if err != nil {
+		return auth, err
+	}
+	if v := credentials["token"]; v != "" {
+		auth.Token = v
+	}
+	if v := credentials["username"]; v != "" {
+		auth.Username = v
+	}
+	if v := credentials["password"]; v != "" {
+		auth.Password = v
+	}
+	return auth, nil
+}
+
+// newRequest 按模板构建请求
+func (he *HTTPEngine) newRequest(ctx context.Context, auth HTTPAuthConfig, text string) (*http.Request, error) {
+	data := he.templateData(text)
+
+	var target, body bytes.Buffer
+	if err := he.urlTmpl.Execute(&target, data); err != nil {
+		return nil, fmt.Errorf("渲染 URL 模板失败: %w", err)
+	}
+	if err := he.body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("渲染请求体模板失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, he.config.Method, target.String(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if body.Len() > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range he.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	switch auth.Scheme {
+	case HTTPAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case HTTPAuthHeader:
+		req.Header.Set(auth.Name, auth.Prefix+auth.Token)
+	case HTTPAuthQuery:
+		query := req.URL.Query()
+		query.Set(auth.Name, auth.Token)
+		req.URL.RawQuery = query.Encode()
+	case HTTPAuthBasic:
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	return req, nil
+}
+
+// sendRequest 发送合成请求，返回状态码为成功的响应
+func (he *HTTPEngine) sendRequest(ctx context.Context, auth HTTPAuthConfig, text string) (resp *http.Response, err error) {
+	req, err := he.newRequest(ctx, auth, text)
+	if err != nil {
+		return nil, err
+	}
+
+	he.mu.RLock()
+	client := he.client
+	he.mu.RUnlock()
+
+	// Span 覆盖到收到响应头为止，响应体由调用方流式读取
+	req, span := startRequestSpan(req, he.config.Name)
+	defer func() { endRequestSpan(span, resp, err) }()
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, realtimetts.ClassifyTransportError(he.config.Name, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	message := strings.TrimSpace(string(raw))
+	var doc interface{}
+	if json.Unmarshal(raw, &doc) == nil {
+		for _, field := range []string{he.config.Response.MessageField, he.config.Response.ErrorField} {
+			if v, ok := lookupJSONPath(doc, field); ok && field != "" && v != nil {
+				message = fmt.Sprint(v)
+				break
+			}
+		}
+	}
+	return nil, realtimetts.ClassifyHTTPStatus(he.config.Name, resp.StatusCode, message)
+}
+
+// DoSynthesize 执行合成，音频边到达边按帧写入 outputChan
+// 只有在收到成功的响应头之前的失败会按重试策略重试
+func (he *HTTPEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	auth, err := he.resolveAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger := he.Logger()
+	startTime := time.Now()
+	var resp *http.Response
+	err = he.GetRetryPolicy().Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = he.sendRequest(ctx, auth, text)
+		return err
+	})
+	if err != nil {
+		he.mu.RLock()
+		realtimetts.InvalidateOnAuthError(he.credentials, err)
+		he.mu.RUnlock()
+		logger.Warn("HTTP 引擎请求失败", realtimetts.LogKeyError, err, realtimetts.LogKeyLatency, time.Since(startTime))
+		return err
+	}
+	defer resp.Body.Close()
+	logger.Debug("收到 HTTP 引擎响应", "status", resp.StatusCode, "mode", he.config.Response.Mode,
+		realtimetts.LogKeyLatency, time.Since(startTime))
+
+	// 各响应模式统一转换为音频字节流
+	var audio io.Reader = resp.Body
+	if he.config.Response.Mode != HTTPResponseRaw {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		go func() {
+			pw.CloseWithError(he.decodeResponse(resp.Body, pw))
+		}()
+		audio = pr
+	}
+
+	reader := bufio.NewReader(audio)
+	format := he.GetStreamInfo()
+	if he.config.AudioFormat == HTTPAudioWAV {
+		header, _, err := realtimetts.ReadWAVHeader(reader)
+		if err != nil {
+			return he.streamError(err)
+		}
+		format = header
+	}
+
+	chunker := realtimetts.NewFrameChunker(format, he.config.ChunkSize)
+	buf := make([]byte, he.config.ChunkSize)
+	total := 0
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			total += n
+			if sendErr := sendFrames(ctx, outputChan, chunker.Push(buf[:n])); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			logger.Debug("HTTP 引擎合成完成", realtimetts.LogKeyBytes, total, "duration", time.Since(startTime))
+			return nil
+		}
+		if err != nil {
+			return he.streamError(err)
+		}
+	}
+}
+
+// streamError 分类读取响应时的错误，已分类的错误原样返回
+func (he *HTTPEngine) streamError(err error) error {
+	var engineErr *realtimetts.EngineError
+	if errors.As(err, &engineErr) {
+		return err
+	}
+	return realtimetts.ClassifyTransportError(he.config.Name, err)
+}
+
+// decodeResponse 按响应模式从 body 中提取音频写入 w
+func (he *HTTPEngine) decodeResponse(body io.Reader, w io.Writer) error {
+	switch he.config.Response.Mode {
+	case HTTPResponseJSON:
+		decoder := json.NewDecoder(body)
+		decoder.UseNumber()
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			return he.responseError(fmt.Errorf("解析响应失败: %w", err))
+		}
+		return he.writeAudio(doc, w)
+
+	case HTTPResponseChunked:
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			doc, err := decodeJSONNumber(line)
+			if err != nil {
+				return he.responseError(fmt.Errorf("解析响应块失败: %w", err))
+			}
+			if err := he.writeAudio(doc, w); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+
+	case HTTPResponseSSE:
+		return he.decodeSSE(body, w)
+	}
+	return nil
+}
+
+// decodeSSE 解析 Server-Sent Events，每个事件的 data 含一段音频
+func (he *HTTPEngine) decodeSSE(body io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var data []string
+	dispatch := func() (bool, error) {
+		if len(data) == 0 {
+			return false, nil
+		}
+		payload := strings.Join(data, "\n")
+		data = data[:0]
+		if payload == he.config.Response.DoneValue {
+			return true, nil
+		}
+		if he.config.Response.AudioField == "" {
+			return false, he.writeBase64(payload, w)
+		}
+		doc, err := decodeJSONNumber([]byte(payload))
+		if err != nil {
+			return false, he.responseError(fmt.Errorf("解析事件失败: %w", err))
+		}
+		return false, he.writeAudio(doc, w)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+		// event、id、retry 字段和注释行不影响音频
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	_, err := dispatch()
+	return err
+}
+
+// writeAudio 检查 JSON 中的错误字段并写出其中的音频
+func (he *HTTPEngine) writeAudio(doc interface{}, w io.Writer) error {
+	response := he.config.Response
+
+	if response.ErrorField != "" {
+		if v, ok := lookupJSONPath(doc, response.ErrorField); ok && v != nil && v != "" {
+			return he.vendorError("", fmt.Sprint(v))
+		}
+	}
+	if response.CodeField != "" {
+		v, _ := lookupJSONPath(doc, response.CodeField)
+		code := fmt.Sprint(v)
+		success := false
+		for _, c := range response.SuccessCodes {
+			success = success || c == code
+		}
+		if !success {
+			message, _ := lookupJSONPath(doc, response.MessageField)
+			if message == nil {
+				message = ""
+			}
+			return he.vendorError(code, fmt.Sprint(message))
+		}
+	}
+
+	v, ok := lookupJSONPath(doc, response.AudioField)
+	if !ok || v == nil {
+		// 状态或元数据块不含音频
+		return nil
+	}
+	encoded, ok := v.(string)
+	if !ok {
+		return he.responseError(fmt.Errorf("音频字段 %s 不是字符串", response.AudioField))
+	}
+	return he.writeBase64(encoded, w)
+}
+
+// writeBase64 解码 base64 音频并写出
+func (he *HTTPEngine) writeBase64(encoded string, w io.Writer) error {
+	audio, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return he.responseError(fmt.Errorf("解码音频失败: %w", err))
+	}
+	_, err = w.Write(audio)
+	return err
+}
+
+// vendorError 响应体中服务商报告的错误
+func (he *HTTPEngine) vendorError(code, message string) error {
+	engineErr := &realtimetts.EngineError{Engine: he.config.Name, Kind: realtimetts.ErrEngineServer, Message: message}
+	engineErr.Code, _ = strconv.Atoi(code)
+	return engineErr
+}
+
+// responseError 无法解析的响应
+func (he *HTTPEngine) responseError(err error) error {
+	return &realtimetts.EngineError{Engine: he.config.Name, Kind: realtimetts.ErrEngineServer, Err: err}
+}
+
+// decodeJSONNumber 解析 JSON，数字保留原文以便与状态码比较
+func decodeJSONNumber(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	err := decoder.Decode(&doc)
+	return doc, err
+}
+
+// lookupJSONPath 按点分隔的路径查找 JSON 字段
+func lookupJSONPath(doc interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// TTSEngine 接口实现
+
+// GetStreamInfo 返回音频配置信息
+// wav 响应的实际格式以文件头为准
+func (he *HTTPEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	return &realtimetts.AudioConfiguration{
+		Format:        realtimetts.FormatWAV,
+		Channels:      he.config.Channels,
+		SampleRate:    he.config.SampleRate,
+		BitsPerSample: he.config.BitsPerSample,
+		Volume:        1.0,
+	}
+}
+
+// Synthesize 执行文本到音频的合成
+// 合成失败时通过返回流的 Err 报告错误
+func (he *HTTPEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(100)
+
+	go func() {
+		stream.CloseWithError(he.DoSynthesize(ctx, text, stream.Writer()))
+	}()
+
+	return stream, nil
+}
+
+// GetVoices 获取配置中声明的语音列表
+func (he *HTTPEngine) GetVoices() ([]realtimetts.Voice, error) {
+	return append([]realtimetts.Voice(nil), he.config.Voices...), nil
+}
+
+// SetVoice 设置使用的语音，对应模板中的 .Voice
+func (he *HTTPEngine) SetVoice(voice realtimetts.Voice) error {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+	he.config.Voice = voice.ID
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数，合并到模板中的 .Params
+func (he *HTTPEngine) SetVoiceParameters(params map[string]interface{}) error {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+
+	for k, v := range params {
+		he.config.Params[k] = v
+	}
+	return nil
+}
+
+// GetEngineInfo 获取引擎信息
+func (he *HTTPEngine) GetEngineInfo() realtimetts.EngineInfo {
+	return realtimetts.EngineInfo{
+		Name:         he.config.Name,
+		Version:      "1.0.0",
+		Description:  "声明式 HTTP/JSON 语音合成引擎",
+		Capabilities: []string{"pcm-format", "real-time-synthesis", "streaming-response", "text-to-speech"},
+		Config: map[string]string{
+			"method":        he.config.Method,
+			"response_mode": he.config.Response.Mode,
+			"audio_format":  he.config.AudioFormat,
+		},
+	}
+}
+
+// Fingerprint 实现 realtimetts.Fingerprinter，包含请求模板、语音和参数，不含认证信息和敏感请求头
+func (he *HTTPEngine) Fingerprint() string {
+	// Params 与 SetVoiceParameters 共享同一个 map，序列化需在锁内完成
+	he.mu.RLock()
+	defer he.mu.RUnlock()
+
+	config := he.config
+	config.Auth = HTTPAuthConfig{Scheme: config.Auth.Scheme}
+	headers := make(map[string]string, len(config.Headers))
+	for name, value := range config.Headers {
+		if !realtimetts.IsSensitiveKey(name) {
+			headers[name] = value
+		}
+	}
+	config.Headers = headers
+	return realtimetts.FingerprintOf(config)
+}
+
+// Initialize 初始化引擎
+func (he *HTTPEngine) Initialize() error {
+	return nil
+}
+
+// Close 关闭引擎
+func (he *HTTPEngine) Close() error {
+	he.mu.RLock()
+	defer he.mu.RUnlock()
+	he.client.CloseIdleConnections()
+	return nil
+}
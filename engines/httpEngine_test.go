@@ -0,0 +1,170 @@
+package engines_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+func TestHTTPEngineRawStreamWithTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("请求体不是合法的JSON: %v", err)
+		}
+		if r.Header.Get("X-Api-Key") != "Key;secret" || r.URL.Query().Get("lang") != "zh" {
+			t.Errorf("认证或URL不正确: %v %s", r.Header, r.URL)
+		}
+		if body["text"] != `他说"你好"` || body["voice"] != "v1" || body["speed"] != 1.2 || body["pitch"] != 1.0 {
+			t.Errorf("请求体不正确: %v", body)
+		}
+		for i := 0; i < 3; i++ {
+			w.Write(make([]byte, 1001))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	engine, err := engines.NewHTTPEngine(engines.HTTPEngineConfig{
+		URL:    server.URL + "/tts?lang={{query \"zh\"}}",
+		Body:   `{"text": {{json .Text}}, "voice": {{json .Voice}}, "speed": {{default 1.0 .Params.speed}}, "pitch": {{default 1.0 .Params.pitch}}}`,
+		Auth:   engines.HTTPAuthConfig{Scheme: engines.HTTPAuthHeader, Name: "X-Api-Key", Prefix: "Key;", Token: "secret"},
+		Voice:  "v1",
+		Params: map[string]interface{}{"speed": 1.2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := collectFrames(t, engine, `他说"你好"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3003 字节按 16 位采样对齐，末尾的半个采样被丢弃
+	if got := audioBytes(frames); got != 3002 {
+		t.Fatalf("输出 %d 字节, 期望 3002", got)
+	}
+}
+
+func TestHTTPEngineResponseModes(t *testing.T) {
+	pcm := bytes.Repeat([]byte{1, 2}, 800)
+	var wav bytes.Buffer
+	realtimetts.EncodeWAV(&wav, pcm, &realtimetts.AudioConfiguration{SampleRate: 8000, Channels: 1, BitsPerSample: 16})
+	encode := base64.StdEncoding.EncodeToString
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			fmt.Fprintf(w, `{"code": 0, "data": {"audio": %q}}`, encode(pcm))
+		case "/chunked":
+			fmt.Fprintf(w, "{\"code\": 3000, \"data\": %q}\n\n{\"code\": 3000, \"data\": %q}\n{\"code\": 3000, \"sequence\": -1}\n", encode(pcm[:600]), encode(pcm[600:]))
+		case "/sse":
+			raw := wav.Bytes()
+			fmt.Fprintf(w, ": ping\n\nevent: audio\ndata: %s\n\ndata: %s\n\ndata: [DONE]\n\ndata: %s\n\n", encode(raw[:100]), encode(raw[100:]), encode(pcm))
+		case "/fail":
+			fmt.Fprint(w, `{"code": 3011, "message": "invalid text"}`)
+		case "/denied":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"message": "bad key"}}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		config engines.HTTPEngineConfig
+	}{
+		{"json", engines.HTTPEngineConfig{URL: server.URL + "/json", Response: engines.HTTPResponseConfig{
+			Mode: engines.HTTPResponseJSON, AudioField: "data.audio", CodeField: "code", SuccessCodes: []string{"0"}}}},
+		{"chunked", engines.HTTPEngineConfig{URL: server.URL + "/chunked", Response: engines.HTTPResponseConfig{
+			Mode: engines.HTTPResponseChunked, AudioField: "data", CodeField: "code", SuccessCodes: []string{"3000"}}}},
+		{"sse", engines.HTTPEngineConfig{URL: server.URL + "/sse", AudioFormat: engines.HTTPAudioWAV, Response: engines.HTTPResponseConfig{
+			Mode: engines.HTTPResponseSSE}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := engines.NewHTTPEngine(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			frames, err := collectFrames(t, engine, "hi")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []byte
+			for _, frame := range frames {
+				got = append(got, frame.Data...)
+			}
+			if !bytes.Equal(got, pcm) {
+				t.Fatalf("输出 %d 字节, 期望 %d 字节", len(got), len(pcm))
+			}
+		})
+	}
+
+	engine, _ := engines.NewHTTPEngine(engines.HTTPEngineConfig{URL: server.URL + "/fail", Response: engines.HTTPResponseConfig{
+		Mode: engines.HTTPResponseJSON, AudioField: "data", CodeField: "code", SuccessCodes: []string{"3000"}, MessageField: "message"}})
+	_, err := collectFrames(t, engine, "hi")
+	var engineErr *realtimetts.EngineError
+	if !errors.As(err, &engineErr) || engineErr.Code != 3011 || engineErr.Message != "invalid text" {
+		t.Fatalf("响应体中的错误码应映射为引擎错误: %v", err)
+	}
+
+	engine, _ = engines.NewHTTPEngine(engines.HTTPEngineConfig{URL: server.URL + "/denied", Response: engines.HTTPResponseConfig{ErrorField: "error.message"}})
+	_, err = collectFrames(t, engine, "hi")
+	if !errors.Is(err, realtimetts.ErrEngineAuth) || !errors.As(err, &engineErr) || engineErr.Message != "bad key" {
+		t.Fatalf("403 应映射为认证错误: %v", err)
+	}
+}
+
+func TestHTTPEngineRejectsInvalidConfig(t *testing.T) {
+	configs := []engines.HTTPEngineConfig{
+		{},
+		{URL: "http://x", Response: engines.HTTPResponseConfig{Mode: "xml"}},
+		{URL: "http://x", Response: engines.HTTPResponseConfig{Mode: engines.HTTPResponseJSON}},
+		{URL: "http://x", Auth: engines.HTTPAuthConfig{Scheme: engines.HTTPAuthQuery}},
+		{URL: "http://x", Body: "{{json .Text"},
+	}
+	for i, config := range configs {
+		if _, err := engines.NewHTTPEngine(config); err == nil {
+			t.Errorf("配置 %d 应返回错误", i)
+		}
+	}
+}
+
+func TestHTTPEngineFingerprintConcurrentWithSetVoiceParameters(t *testing.T) {
+	engine, err := engines.NewHTTPEngine(engines.HTTPEngineConfig{URL: "http://x", Params: map[string]interface{}{"speed": 1.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			engine.SetVoiceParameters(map[string]interface{}{fmt.Sprintf("p%d", i%8): i})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			engine.Fingerprint()
+		}
+	}()
+	wg.Wait()
+
+	before := engine.Fingerprint()
+	engine.SetVoiceParameters(map[string]interface{}{"speed": 1.5})
+	if engine.Fingerprint() == before {
+		t.Fatal("参数变化后指纹应改变")
+	}
+}
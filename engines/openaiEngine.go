@@ -0,0 +1,392 @@
+package engines
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	realtimetts "realtimetts/pkg"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenAI 兼容接口的响应格式
+const (
+	OpenAIFormatPCM = "pcm" // 24kHz 单声道 16 位小端 PCM
+	OpenAIFormatWAV = "wav"
+)
+
+// OpenAIConfig OpenAI 兼容语音合成配置
+type OpenAIConfig struct {
+	Name           string  `json:"name"`
+	BaseURL        string  `json:"base_url"` // 不含 /audio/speech，例如 https://api.openai.com/v1
+	APIKey         string  `json:"api_key"`
+	Model          string  `json:"model"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"` // pcm / wav
+	Speed          float64 `json:"speed"`
+	Instructions   string  `json:"instructions"` // 语气说明，仅部分模型支持
+	SampleRate     int     `json:"sample_rate"`  // pcm 响应的采样率
+	ChunkSize      int     `json:"chunk_size"`   // 每帧的最大字节数
+}
+
+// openAISpeechRequest /audio/speech 请求体
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float64 `json:"speed,omitempty"`
+	Instructions   string  `json:"instructions,omitempty"`
+}
+
+// openAIErrorResponse 错误响应体
+type openAIErrorResponse struct {
+	Error struct {
+		Message string      `json:"message"`
+		Type    string      `json:"type"`
+		Code    interface{} `json:"code"`
+	} `json:"error"`
+}
+
+// OpenAIEngine OpenAI 兼容的 /v1/audio/speech 引擎
+// 适用于 OpenAI 以及提供相同接口的服务商和自建服务，响应体边到达边按帧输出
+type OpenAIEngine struct {
+	*realtimetts.BaseEngine
+	client *http.Client
+	config OpenAIConfig
+	retry  *realtimetts.RetryPolicy
+
+	// 凭据来源，设置后其提供的 api_key 优先于 config.APIKey
+	credentials realtimetts.CredentialProvider
+	mu          sync.RWMutex
+}
+
+// NewOpenAIEngine 创建新的 OpenAI 兼容引擎
+func NewOpenAIEngine(baseURL, apiKey string) *OpenAIEngine {
+	realtimetts.RegisterSecret(apiKey)
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	return &OpenAIEngine{
+		BaseEngine: realtimetts.NewBaseEngine("OpenAI TTS"),
+		client: &http.Client{
+			// 流式响应的总时长由 ctx 控制，这里只限制建立连接和等待响应头
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: 30 * time.Second,
+			},
+		},
+		config: OpenAIConfig{
+			Name:           "OpenAI TTS",
+			BaseURL:        strings.TrimRight(baseURL, "/"),
+			APIKey:         apiKey,
+			Model:          "tts-1",
+			Voice:          "alloy",
+			ResponseFormat: OpenAIFormatPCM,
+			Speed:          1.0,
+			SampleRate:     24000,
+			ChunkSize:      4800,
+		},
+		retry: realtimetts.DefaultRetryPolicy(),
+	}
+}
+
+// SetOpenAIConfig 设置 OpenAI 兼容引擎配置
+func (oe *OpenAIEngine) SetOpenAIConfig(config OpenAIConfig) error {
+	switch config.ResponseFormat {
+	case OpenAIFormatPCM, OpenAIFormatWAV:
+	default:
+		return fmt.Errorf("%w: %s", realtimetts.ErrUnsupportedFormat, config.ResponseFormat)
+	}
+	if config.Name == "" {
+		config.Name = "OpenAI TTS"
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = 24000
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = 4800
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	realtimetts.RegisterSecret(config.APIKey)
+
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.config = config
+	return nil
+}
+
+// GetOpenAIConfig 获取 OpenAI 兼容引擎配置
+func (oe *OpenAIEngine) GetOpenAIConfig() OpenAIConfig {
+	oe.mu.RLock()
+	defer oe.mu.RUnlock()
+	return oe.config
+}
+
+// SetCredentialProvider 设置凭据来源，每次请求前获取 api_key
+func (oe *OpenAIEngine) SetCredentialProvider(provider realtimetts.CredentialProvider) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.credentials = provider
+}
+
+// SetHTTPClient 设置 HTTP 客户端
+func (oe *OpenAIEngine) SetHTTPClient(client *http.Client) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.client = client
+}
+
+// SetRetryPolicy 设置请求重试策略，传入 nil 表示不重试
+func (oe *OpenAIEngine) SetRetryPolicy(policy *realtimetts.RetryPolicy) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+
+	if policy == nil {
+		policy = &realtimetts.RetryPolicy{MaxAttempts: 1}
+	}
+	oe.retry = policy
+}
+
+// GetRetryPolicy 获取请求重试策略
+func (oe *OpenAIEngine) GetRetryPolicy() *realtimetts.RetryPolicy {
+	oe.mu.RLock()
+	defer oe.mu.RUnlock()
+	return oe.retry
+}
+
+// DoSynthesize 执行合成，响应体边到达边按帧写入 outputChan
+// 只有在收到成功的响应头之前的失败会按重试策略重试
+func (oe *OpenAIEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	config := oe.GetOpenAIConfig()
+	oe.mu.RLock()
+	provider := oe.credentials
+	oe.mu.RUnlock()
+	if provider != nil {
+		credentials, err := provider.Credentials(ctx)
+		if err != nil {
+			return err
+		}
+		if v := credentials["api_key"]; v != "" {
+			config.APIKey = v
+		}
+	}
+
+	logger := oe.Logger()
+	startTime := time.Now()
+	var resp *http.Response
+	err := oe.GetRetryPolicy().Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = oe.sendRequest(ctx, config, text)
+		return err
+	})
+	if err != nil {
+		realtimetts.InvalidateOnAuthError(provider, err)
+		logger.Warn("OpenAI 请求失败", realtimetts.LogKeyError, err, realtimetts.LogKeyLatency, time.Since(startTime))
+		return err
+	}
+	defer resp.Body.Close()
+	logger.Debug("收到 OpenAI 响应", "model", config.Model, realtimetts.LogKeyLatency, time.Since(startTime))
+
+	reader := bufio.NewReader(resp.Body)
+	format := oe.GetStreamInfo()
+	if config.ResponseFormat == OpenAIFormatWAV {
+		header, _, err := realtimetts.ReadWAVHeader(reader)
+		if err != nil {
+			return realtimetts.ClassifyTransportError(config.Name, err)
+		}
+		format = header
+	}
+
+	chunker := realtimetts.NewFrameChunker(format, config.ChunkSize)
+	buf := make([]byte, config.ChunkSize)
+	total := 0
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			total += n
+			if sendErr := sendFrames(ctx, outputChan, chunker.Push(buf[:n])); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			logger.Debug("OpenAI 合成完成", realtimetts.LogKeyBytes, total, "duration", time.Since(startTime))
+			return nil
+		}
+		if err != nil {
+			return realtimetts.ClassifyTransportError(config.Name, err)
+		}
+	}
+}
+
+// sendRequest 发送合成请求，返回状态码为成功的响应
+func (oe *OpenAIEngine) sendRequest(ctx context.Context, config OpenAIConfig, text string) (resp *http.Response, err error) {
+	body, err := json.Marshal(openAISpeechRequest{
+		Model:          config.Model,
+		Input:          text,
+		Voice:          config.Voice,
+		ResponseFormat: config.ResponseFormat,
+		Speed:          config.Speed,
+		Instructions:   config.Instructions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.BaseURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+config.APIKey)
+	}
+
+	oe.mu.RLock()
+	client := oe.client
+	oe.mu.RUnlock()
+
+	// Span 覆盖到收到响应头为止，响应体由调用方流式读取
+	req, span := startRequestSpan(req, config.Name)
+	defer func() { endRequestSpan(span, resp, err) }()
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, realtimetts.ClassifyTransportError(config.Name, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	message := strings.TrimSpace(string(raw))
+	var errResp openAIErrorResponse
+	if json.Unmarshal(raw, &errResp) == nil && errResp.Error.Message != "" {
+		message = errResp.Error.Message
+	}
+
+	engineErr := realtimetts.ClassifyHTTPStatus(config.Name, resp.StatusCode, message)
+	// 余额不足与限流共用 429，但重试无济于事
+	if errResp.Error.Type == "insufficient_quota" || errResp.Error.Code == "insufficient_quota" {
+		engineErr.Retryable = false
+	}
+	return nil, engineErr
+}
+
+// TTSEngine 接口实现
+
+// GetStreamInfo 返回音频配置信息
+// wav 响应的实际格式以文件头为准
+func (oe *OpenAIEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	oe.mu.RLock()
+	defer oe.mu.RUnlock()
+
+	return &realtimetts.AudioConfiguration{
+		Format:        realtimetts.FormatWAV,
+		Channels:      1,
+		SampleRate:    oe.config.SampleRate,
+		BitsPerSample: 16,
+		Volume:        1.0,
+	}
+}
+
+// Synthesize 执行文本到音频的合成
+// 合成失败时通过返回流的 Err 报告错误
+func (oe *OpenAIEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(100)
+
+	go func() {
+		stream.CloseWithError(oe.DoSynthesize(ctx, text, stream.Writer()))
+	}()
+
+	return stream, nil
+}
+
+// GetVoices 获取可用语音列表
+func (oe *OpenAIEngine) GetVoices() ([]realtimetts.Voice, error) {
+	names := []string{"alloy", "ash", "coral", "echo", "fable", "nova", "onyx", "sage", "shimmer"}
+	voices := make([]realtimetts.Voice, len(names))
+	for i, name := range names {
+		voices[i] = realtimetts.Voice{ID: name, Name: name}
+	}
+	return voices, nil
+}
+
+// SetVoice 设置使用的语音
+func (oe *OpenAIEngine) SetVoice(voice realtimetts.Voice) error {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.config.Voice = voice.ID
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数，支持 speed、model 和 instructions
+func (oe *OpenAIEngine) SetVoiceParameters(params map[string]interface{}) error {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+
+	if speed, ok := params["speed"].(float64); ok {
+		if speed < 0.25 || speed > 4.0 {
+			return realtimetts.ErrInvalidPlaybackSpeed
+		}
+		oe.config.Speed = speed
+	}
+	if model, ok := params["model"].(string); ok {
+		oe.config.Model = model
+	}
+	if instructions, ok := params["instructions"].(string); ok {
+		oe.config.Instructions = instructions
+	}
+
+	return nil
+}
+
+// GetEngineInfo 获取引擎信息
+func (oe *OpenAIEngine) GetEngineInfo() realtimetts.EngineInfo {
+	oe.mu.RLock()
+	defer oe.mu.RUnlock()
+
+	return realtimetts.EngineInfo{
+		Name:         oe.config.Name,
+		Version:      "1.0.0",
+		Description:  "OpenAI 兼容语音合成接口",
+		Capabilities: []string{"pcm-format", "real-time-synthesis", "streaming-response", "text-to-speech", "voice-selection"},
+		Config: map[string]string{
+			"base_url": oe.config.BaseURL,
+			"model":    oe.config.Model,
+		},
+	}
+}
+
+// Fingerprint 实现 realtimetts.Fingerprinter，包含模型、语音、语速等合成配置，不含 API Key
+func (oe *OpenAIEngine) Fingerprint() string {
+	config := oe.GetOpenAIConfig()
+	config.APIKey = ""
+	return realtimetts.FingerprintOf(config)
+}
+
+// Initialize 初始化引擎
+func (oe *OpenAIEngine) Initialize() error {
+	config := oe.GetOpenAIConfig()
+	if config.BaseURL == "" {
+		return fmt.Errorf("OpenAI 兼容引擎需要 BaseURL")
+	}
+	if config.Model == "" {
+		return fmt.Errorf("OpenAI 兼容引擎需要 Model")
+	}
+	return nil
+}
+
+// Close 关闭引擎
+func (oe *OpenAIEngine) Close() error {
+	oe.mu.RLock()
+	defer oe.mu.RUnlock()
+	oe.client.CloseIdleConnections()
+	return nil
+}
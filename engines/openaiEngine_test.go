@@ -0,0 +1,116 @@
+package engines_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+func TestOpenAIEngineStreamsChunkedPCM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("请求不正确: %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["input"] != "你好" || body["voice"] != "nova" || body["response_format"] != "pcm" || body["speed"] != 1.5 {
+			t.Errorf("请求体不正确: %v", body)
+		}
+
+		// 分三次写出奇数长度的块，验证按采样帧对齐
+		for _, n := range []int{1001, 999, 2000} {
+			w.Write(make([]byte, n))
+			w.(http.Flusher).Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	engine := engines.NewOpenAIEngine(server.URL+"/v1/", "sk-test")
+	engine.SetVoice(realtimetts.Voice{ID: "nova"})
+	engine.SetVoiceParameters(map[string]interface{}{"speed": 1.5})
+
+	frames, err := collectFrames(t, engine, "你好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := audioBytes(frames); got != 4000 {
+		t.Fatalf("输出 %d 字节, 期望 4000", got)
+	}
+	if len(frames) < 3 {
+		t.Fatalf("响应应边到达边输出, 只有 %d 帧", len(frames))
+	}
+	for _, frame := range frames {
+		if len(frame.Data)%2 != 0 || frame.Format.SampleRate != 24000 {
+			t.Fatalf("帧格式不正确: %d 字节, %+v", len(frame.Data), frame.Format)
+		}
+	}
+}
+
+func TestOpenAIEngineWAVResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		realtimetts.EncodeWAV(w, make([]byte, 3200), &realtimetts.AudioConfiguration{SampleRate: 16000, Channels: 1, BitsPerSample: 16})
+	}))
+	defer server.Close()
+
+	engine := engines.NewOpenAIEngine(server.URL, "")
+	config := engine.GetOpenAIConfig()
+	config.ResponseFormat = engines.OpenAIFormatWAV
+	if err := engine.SetOpenAIConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := collectFrames(t, engine, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audioBytes(frames) != 3200 || frames[0].Format.SampleRate != 16000 {
+		t.Fatalf("应按WAV头解析响应: %d 字节, %+v", audioBytes(frames), frames[0].Format)
+	}
+}
+
+func TestOpenAIEngineErrorMapping(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer bad":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))
+		case "Bearer broke":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"quota exceeded","type":"insufficient_quota"}}`))
+		default:
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(make([]byte, 480))
+		}
+	}))
+	defer server.Close()
+
+	_, err := collectFrames(t, engines.NewOpenAIEngine(server.URL, "bad"), "hi")
+	var engineErr *realtimetts.EngineError
+	if !errors.Is(err, realtimetts.ErrEngineAuth) || !errors.As(err, &engineErr) || engineErr.Message != "Incorrect API key provided" {
+		t.Fatalf("401 应映射为认证错误: %v", err)
+	}
+
+	_, err = collectFrames(t, engines.NewOpenAIEngine(server.URL, "broke"), "hi")
+	if !errors.Is(err, realtimetts.ErrEngineQuota) || realtimetts.IsRetryable(err) {
+		t.Fatalf("余额不足应为不可重试的配额错误: %v", err)
+	}
+
+	engine := engines.NewOpenAIEngine(server.URL, "ok")
+	engine.SetRetryPolicy(&realtimetts.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, Multiplier: 1})
+	frames, err := collectFrames(t, engine, "hi")
+	if err != nil || audioBytes(frames) != 480 || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("503 应重试后成功: err=%v calls=%d", err, calls)
+	}
+}
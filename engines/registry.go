@@ -0,0 +1,159 @@
+package engines
+
+import (
+	"fmt"
+	realtimetts "realtimetts/pkg"
+)
+
+// 注册的引擎类型名
+const (
+	TypeVolcengine = "volcengine"
+	TypeOpenAI     = "openai"
+	TypeHTTP       = "http"
+	TypeCommand    = "command"
+	TypeTone       = "tone"
+	TypeClip       = "clip"
+)
+
+// clipSpecConfig 预录音频引擎的配置
+type clipSpecConfig struct {
+	SampleRate    int    `json:"sample_rate"`
+	Channels      int    `json:"channels"`
+	BitsPerSample int    `json:"bits_per_sample"`
+	ChunkSize     int    `json:"chunk_size"`
+	Directory     string `json:"directory"` // 文件名即文本的 WAV 目录
+	Manifest      string `json:"manifest"`  // JSON 清单文件
+}
+
+// init 注册本包提供的引擎，导入本包后即可通过 realtimetts.BuildEngines 按配置构造
+func init() {
+	realtimetts.RegisterEngine(TypeVolcengine, newVolcengineFromSpec)
+	realtimetts.RegisterEngine(TypeOpenAI, newOpenAIFromSpec)
+	realtimetts.RegisterEngine(TypeHTTP, newHTTPFromSpec)
+	realtimetts.RegisterEngine(TypeCommand, newCommandFromSpec)
+	realtimetts.RegisterEngine(TypeTone, newToneFromSpec)
+	realtimetts.RegisterEngine(TypeClip, newClipFromSpec)
+}
+
+// newVolcengineFromSpec 凭据: app_id、access_token、cluster
+func newVolcengineFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	engine := NewVolcengineEngine("", "", "")
+	config := engine.GetVolcengineConfig()
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+	if spec.Name != "" {
+		config.Name = spec.Name
+	}
+	if err := engine.SetVolcengineConfig(config); err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		engine.SetCredentialProvider(credentials)
+	}
+	return engine, nil
+}
+
+// newOpenAIFromSpec 凭据: api_key
+func newOpenAIFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	engine := NewOpenAIEngine("", "")
+	config := engine.GetOpenAIConfig()
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+	if spec.Name != "" {
+		config.Name = spec.Name
+	}
+	if err := engine.SetOpenAIConfig(config); err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		engine.SetCredentialProvider(credentials)
+	}
+	return engine, nil
+}
+
+// newHTTPFromSpec 凭据: token、username、password
+func newHTTPFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	var config HTTPEngineConfig
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+	if spec.Name != "" {
+		config.Name = spec.Name
+	}
+	engine, err := NewHTTPEngine(config)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		engine.SetCredentialProvider(credentials)
+	}
+	return engine, nil
+}
+
+// newCommandFromSpec 本地程序引擎不使用凭据
+func newCommandFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	config := DefaultCommandConfig()
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+	if spec.Name != "" {
+		config.Name = spec.Name
+	}
+	if config.Command == "" {
+		return nil, fmt.Errorf("%w: command 引擎未配置 command", realtimetts.ErrInvalidEngineConfig)
+	}
+	if config.Persistent && len(config.EndMarker) == 0 {
+		return nil, fmt.Errorf("%w: command 引擎的常驻进程模式需要配置 end_marker", realtimetts.ErrInvalidEngineConfig)
+	}
+	return NewCommandEngine(config), nil
+}
+
+// newToneFromSpec 测试音引擎不使用凭据
+func newToneFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	config := DefaultToneConfig()
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+	if spec.Name != "" {
+		config.Name = spec.Name
+	}
+	return NewToneEngine(config), nil
+}
+
+// newClipFromSpec 预录音频引擎不使用凭据
+func newClipFromSpec(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+	var config clipSpecConfig
+	if err := realtimetts.DecodeEngineConfig(spec, &config); err != nil {
+		return nil, err
+	}
+
+	var format *realtimetts.AudioConfiguration
+	if config.SampleRate > 0 {
+		format = &realtimetts.AudioConfiguration{
+			Format:        realtimetts.FormatWAV,
+			SampleRate:    config.SampleRate,
+			Channels:      max(config.Channels, 1),
+			BitsPerSample: config.BitsPerSample,
+			Volume:        1.0,
+		}
+		if format.BitsPerSample <= 0 {
+			format.BitsPerSample = 16
+		}
+	}
+
+	engine := NewClipEngine(spec.Name, format)
+	engine.SetChunkSize(config.ChunkSize)
+	if config.Directory != "" {
+		if err := engine.LoadDirectory(config.Directory); err != nil {
+			return nil, err
+		}
+	}
+	if config.Manifest != "" {
+		if err := engine.LoadManifest(config.Manifest); err != nil {
+			return nil, err
+		}
+	}
+	return engine, nil
+}
@@ -0,0 +1,97 @@
+package engines_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+func TestBuildEnginesFromConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-from-env" {
+			t.Errorf("应使用凭据来源提供的密钥: %q", r.Header.Get("Authorization"))
+		}
+		w.Write(make([]byte, 480))
+	}))
+	defer server.Close()
+	t.Setenv("TEST_OPENAI_KEY", "sk-from-env")
+
+	var config realtimetts.EngineChainConfig
+	err := json.Unmarshal([]byte(`{
+		"credentials": {"openai-prod": {"api_key": "env:TEST_OPENAI_KEY"}},
+		"engines": [
+			{"type": "openai", "name": "primary", "credentials": "openai-prod", "voice": "nova",
+			 "params": {"speed": 1.25}, "config": {"base_url": "`+server.URL+`/v1", "model": "tts-1-hd"}},
+			{"type": "tone", "name": "backup", "voice": "beep", "config": {"sample_rate": 8000}},
+			{"type": "volcengine", "name": "volc", "config": {"app_id": "app", "access_token": "volc-token", "cluster": "volcano_tts", "voice_type": "BV001_streaming"}}
+		]
+	}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	built, err := realtimetts.BuildEngines(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 3 || built[0].GetEngineInfo().Name != "primary" || built[1].GetEngineInfo().Name != "backup" || built[2].GetEngineInfo().Name != "volc" {
+		t.Fatalf("引擎链顺序或名称不正确: %v", built)
+	}
+
+	openai := built[0].(*engines.OpenAIEngine).GetOpenAIConfig()
+	if openai.Voice != "nova" || openai.Speed != 1.25 || openai.Model != "tts-1-hd" || openai.BaseURL != server.URL+"/v1" {
+		t.Fatalf("OpenAI 引擎配置未生效: %+v", openai)
+	}
+	if _, err := collectFrames(t, built[0], "hi"); err != nil {
+		t.Fatal(err)
+	}
+	tone := built[1].(*engines.ToneEngine).GetToneConfig()
+	if tone.SampleRate != 8000 || tone.Waveform != engines.ToneWaveformBeep {
+		t.Fatalf("测试音引擎配置未生效: %+v", tone)
+	}
+	if volc := built[2].(*engines.VolcengineEngine).GetVolcengineConfig(); volc.VoiceType != "BV001_streaming" || volc.Name != "volc" {
+		t.Fatalf("火山云引擎配置未生效: %+v", volc)
+	}
+}
+
+func TestBuildEnginesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config realtimetts.EngineChainConfig
+		want   error
+	}{
+		{"empty", realtimetts.EngineChainConfig{}, realtimetts.ErrNoEnginesAvailable},
+		{"unknown type", realtimetts.EngineChainConfig{Engines: []realtimetts.EngineSpec{{Type: "nope"}}}, realtimetts.ErrUnknownEngineType},
+		{"unknown credentials", realtimetts.EngineChainConfig{Engines: []realtimetts.EngineSpec{{Type: "openai", Credentials: "missing"}}}, realtimetts.ErrUnknownCredentials},
+		{"unknown field", realtimetts.EngineChainConfig{Engines: []realtimetts.EngineSpec{{Type: "tone", Config: json.RawMessage(`{"sampel_rate": 8000}`)}}}, realtimetts.ErrInvalidEngineConfig},
+		{"persistent without end marker", realtimetts.EngineChainConfig{Engines: []realtimetts.EngineSpec{{Type: "command", Config: json.RawMessage(`{"command": "cat", "persistent": true}`)}}}, realtimetts.ErrInvalidEngineConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := realtimetts.BuildEngines(tt.config); !errors.Is(err, tt.want) {
+				t.Fatalf("期望 %v, 实际 %v", tt.want, err)
+			}
+		})
+	}
+
+	// 缺少凭据时由引擎的 Initialize 报告
+	_, err := realtimetts.BuildEngines(realtimetts.EngineChainConfig{Engines: []realtimetts.EngineSpec{{Type: "volcengine"}}})
+	if err == nil {
+		t.Fatal("缺少凭据的火山云引擎应构造失败")
+	}
+
+	for _, name := range []string{engines.TypeVolcengine, engines.TypeOpenAI, engines.TypeHTTP, engines.TypeCommand, engines.TypeTone, engines.TypeClip} {
+		found := false
+		for _, registered := range realtimetts.RegisteredEngines() {
+			found = found || registered == name
+		}
+		if !found {
+			t.Errorf("引擎类型 %s 未注册", name)
+		}
+	}
+}
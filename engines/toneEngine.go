@@ -0,0 +1,443 @@
+package engines
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	realtimetts "realtimetts/pkg"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// 测试音波形
+const (
+	ToneWaveformSine  = "sine"  // 每个字符一个音高
+	ToneWaveformNoise = "noise" // 确定性白噪声
+	ToneWaveformBeep  = "beep"  // 每个词一声固定音高的提示音
+)
+
+// ToneConfig 测试音引擎配置
+type ToneConfig struct {
+	Name          string        `json:"name"`            // 引擎名称，用于故障切换优先级
+	Waveform      string        `json:"waveform"`        // 波形: sine / noise / beep
+	SampleRate    int           `json:"sample_rate"`     // 采样率
+	Channels      int           `json:"channels"`        // 声道数
+	BitsPerSample int           `json:"bits_per_sample"` // 位深度
+	WordDuration  time.Duration `json:"word_duration"`   // 每个词的时长，平均分配给词内的字符
+	WordGap       time.Duration `json:"word_gap"`        // 词之间的静音
+	BaseFrequency float64       `json:"base_frequency"`  // 基准音高 (Hz)
+	Amplitude     float64       `json:"amplitude"`       // 振幅 (0.0 - 1.0)
+	ChunkSize     int           `json:"chunk_size"`      // 每帧的最大字节数
+	Latency       time.Duration `json:"latency"`         // 输出首帧前的延迟
+	ChunkInterval time.Duration `json:"chunk_interval"`  // 帧之间的间隔，0 表示尽快输出
+	Seed          int64         `json:"seed"`            // 噪声种子，与波形和文本共同决定噪声
+}
+
+// DefaultToneConfig 返回默认测试音引擎配置
+func DefaultToneConfig() ToneConfig {
+	return ToneConfig{
+		Name:          "Tone",
+		Waveform:      ToneWaveformSine,
+		SampleRate:    16000,
+		Channels:      1,
+		BitsPerSample: 16,
+		WordDuration:  200 * time.Millisecond,
+		WordGap:       50 * time.Millisecond,
+		BaseFrequency: 440,
+		Amplitude:     0.3,
+		ChunkSize:     3200,
+		Seed:          1,
+	}
+}
+
+// ToneFault 注入的故障
+type ToneFault struct {
+	Err         error // 合成返回的错误
+	AfterChunks int   // 输出多少帧后失败，0 表示在首帧前失败
+}
+
+// ToneFaultFunc 按合成序号（从 1 开始）和文本决定是否注入故障，返回 nil 表示正常合成
+type ToneFaultFunc func(call int, text string) *ToneFault
+
+// ToneEngine 离线测试音引擎
+// 不依赖网络，按文本生成确定性的类语音音频和逐词时间信息，
+// 可配置延迟、分块和故障注入，用于测试 TextToAudioStream、故障切换和时间回调
+type ToneEngine struct {
+	*realtimetts.BaseEngine
+	config ToneConfig
+	fault  ToneFaultFunc
+	calls  int64
+	mu     sync.RWMutex
+
+	baseWordDuration time.Duration // 构造时的词时长，speed 参数以此为基准
+}
+
+// NewToneEngine 创建新的测试音引擎，配置中的零值使用默认值
+func NewToneEngine(config ToneConfig) *ToneEngine {
+	defaults := DefaultToneConfig()
+	if config.Name == "" {
+		config.Name = defaults.Name
+	}
+	if config.Waveform == "" {
+		config.Waveform = defaults.Waveform
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.Channels <= 0 {
+		config.Channels = defaults.Channels
+	}
+	if config.BitsPerSample <= 0 {
+		config.BitsPerSample = defaults.BitsPerSample
+	}
+	if config.WordDuration <= 0 {
+		config.WordDuration = defaults.WordDuration
+	}
+	if config.BaseFrequency <= 0 {
+		config.BaseFrequency = defaults.BaseFrequency
+	}
+	if config.Amplitude <= 0 {
+		config.Amplitude = defaults.Amplitude
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaults.ChunkSize
+	}
+
+	return &ToneEngine{
+		BaseEngine:       realtimetts.NewBaseEngine(config.Name),
+		config:           config,
+		baseWordDuration: config.WordDuration,
+	}
+}
+
+// SetFault 设置故障注入函数，传入 nil 取消故障注入
+func (te *ToneEngine) SetFault(fault ToneFaultFunc) {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.fault = fault
+}
+
+// GetCallCount 获取 Synthesize 的调用次数
+func (te *ToneEngine) GetCallCount() int {
+	return int(atomic.LoadInt64(&te.calls))
+}
+
+// GetToneConfig 获取测试音引擎配置
+func (te *ToneEngine) GetToneConfig() ToneConfig {
+	te.mu.RLock()
+	defer te.mu.RUnlock()
+	return te.config
+}
+
+// toneWord 文本中的一个词
+type toneWord struct {
+	text  string
+	runes []rune
+}
+
+// splitToneWords 将文本拆分为词：汉字等表意文字每字一词，字母和数字按连续片段成词，
+// 空白和标点只作为分隔符
+func splitToneWords(text string) []toneWord {
+	var words []toneWord
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, toneWord{text: string(current), runes: current})
+			current = nil
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			flush()
+			words = append(words, toneWord{text: string(r), runes: []rune{r}})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'':
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// toneSeed 由配置的种子、波形和文本计算噪声种子，相同输入总是得到相同的音频
+func toneSeed(config ToneConfig, text string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(config.Waveform))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return config.Seed + int64(h.Sum64())
+}
+
+// DoSynthesize 执行合成，按词输出时间信息帧和音频帧
+func (te *ToneEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	call := int(atomic.AddInt64(&te.calls, 1))
+
+	te.mu.RLock()
+	config := te.config
+	fault := te.fault
+	te.mu.RUnlock()
+
+	var injected *ToneFault
+	if fault != nil {
+		injected = fault(call, text)
+	}
+	if injected != nil && injected.AfterChunks <= 0 {
+		return injected.Err
+	}
+
+	if err := sleepContext(ctx, config.Latency); err != nil {
+		return err
+	}
+
+	format := te.GetStreamInfo()
+	generated := &realtimetts.AudioConfiguration{SampleRate: config.SampleRate, Channels: 1, BitsPerSample: 16}
+	rng := rand.New(rand.NewSource(toneSeed(config, text)))
+
+	chunks := 0
+	var pts time.Duration
+	send := func(frame realtimetts.Frame) error {
+		if frame.HasAudio() {
+			if chunks > 0 {
+				if err := sleepContext(ctx, config.ChunkInterval); err != nil {
+					return err
+				}
+			}
+			if injected != nil && chunks >= injected.AfterChunks {
+				return injected.Err
+			}
+			chunks++
+			frame.PTS = pts
+			pts += frame.Duration()
+		}
+		select {
+		case outputChan <- frame:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for i, word := range splitToneWords(text) {
+		if i > 0 && config.WordGap > 0 {
+			gap := realtimetts.NewSilenceFrame(config.WordGap, format)
+			if err := te.sendChunks(gap.Data, format, config.ChunkSize, send); err != nil {
+				return err
+			}
+		}
+
+		samples := te.renderWord(word, config, rng)
+		data, err := realtimetts.ConvertPCM(samples, generated, format)
+		if err != nil {
+			return fmt.Errorf("转换测试音格式失败: %w", err)
+		}
+
+		start := pts
+		duration := realtimetts.NewAudioFrame(data, format).Duration()
+		timing := realtimetts.TimingInfo{
+			Word:      word.text,
+			StartTime: start,
+			EndTime:   start + duration,
+			Duration:  duration,
+		}
+		if err := send(realtimetts.NewTimingFrame(timing)); err != nil {
+			return err
+		}
+		if err := te.sendChunks(data, format, config.ChunkSize, send); err != nil {
+			return err
+		}
+	}
+
+	// 音频帧数不足以触发的中途故障在结束时报告
+	if injected != nil {
+		return injected.Err
+	}
+	return nil
+}
+
+// sendChunks 按帧大小切分音频并发送，帧边界对齐到采样帧
+func (te *ToneEngine) sendChunks(data []byte, format *realtimetts.AudioConfiguration, chunkSize int, send func(realtimetts.Frame) error) error {
+	if bytesPerFrame := format.GetBytesPerFrame(); bytesPerFrame > 0 {
+		chunkSize -= chunkSize % bytesPerFrame
+		if chunkSize <= 0 {
+			chunkSize = bytesPerFrame
+		}
+	}
+
+	for offset := 0; offset < len(data); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := send(realtimetts.NewAudioFrame(data[offset:end], format)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderWord 生成一个词的 16 位单声道 PCM
+// sine 波形每个字符使用由字符决定的音高，beep 波形整个词使用基准音高
+func (te *ToneEngine) renderWord(word toneWord, config ToneConfig, rng *rand.Rand) []byte {
+	total := int(config.WordDuration * time.Duration(config.SampleRate) / time.Second)
+	perChar := total / len(word.runes)
+	fade := config.SampleRate / 200 // 5ms 淡入淡出，避免爆音
+
+	data := make([]byte, 0, perChar*len(word.runes)*2)
+	for i, r := range word.runes {
+		frequency := config.BaseFrequency
+		if config.Waveform == ToneWaveformSine {
+			// 按字符编码映射到基准音高之上的一个八度内
+			frequency *= math.Pow(2, float64(r%12)/12)
+		}
+
+		for n := 0; n < perChar; n++ {
+			var value float64
+			switch config.Waveform {
+			case ToneWaveformNoise:
+				value = rng.Float64()*2 - 1
+			default:
+				// 相位按词内位置累计，beep 跨字符时波形连续
+				value = math.Sin(2 * math.Pi * frequency * float64(i*perChar+n) / float64(config.SampleRate))
+			}
+
+			envelope := 1.0
+			if config.Waveform != ToneWaveformBeep || i == 0 || i == len(word.runes)-1 {
+				if n < fade {
+					envelope = float64(n) / float64(fade)
+				} else if perChar-n < fade {
+					envelope = float64(perChar-n) / float64(fade)
+				}
+			}
+
+			sample := int16(value * envelope * config.Amplitude * 32767)
+			data = append(data, byte(sample), byte(sample>>8))
+		}
+	}
+	return data
+}
+
+// sleepContext 等待指定时长或 ctx 取消
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TTSEngine 接口实现
+
+// GetStreamInfo 返回音频配置信息
+func (te *ToneEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	te.mu.RLock()
+	defer te.mu.RUnlock()
+
+	return &realtimetts.AudioConfiguration{
+		Format:        realtimetts.FormatWAV,
+		Channels:      te.config.Channels,
+		SampleRate:    te.config.SampleRate,
+		BitsPerSample: te.config.BitsPerSample,
+		Volume:        1.0,
+	}
+}
+
+// Synthesize 执行文本到音频的合成
+// 注入的故障通过返回流的 Err 报告
+func (te *ToneEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(100)
+
+	go func() {
+		stream.CloseWithError(te.DoSynthesize(ctx, text, stream.Writer()))
+	}()
+
+	return stream, nil
+}
+
+// GetVoices 获取可用语音列表，每种波形对应一个语音
+func (te *ToneEngine) GetVoices() ([]realtimetts.Voice, error) {
+	return []realtimetts.Voice{
+		{ID: ToneWaveformSine, Name: "Sine", Description: "每个字符一个音高的正弦波"},
+		{ID: ToneWaveformNoise, Name: "Noise", Description: "确定性白噪声"},
+		{ID: ToneWaveformBeep, Name: "Beep", Description: "每个词一声提示音"},
+	}, nil
+}
+
+// SetVoice 设置使用的语音（波形）
+func (te *ToneEngine) SetVoice(voice realtimetts.Voice) error {
+	switch strings.ToLower(voice.ID) {
+	case ToneWaveformSine, ToneWaveformNoise, ToneWaveformBeep:
+	default:
+		return fmt.Errorf("不支持的测试音波形: %s", voice.ID)
+	}
+
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.config.Waveform = strings.ToLower(voice.ID)
+	return nil
+}
+
+// SetVoiceParameters 设置语音参数
+// speed 按比例缩短构造时配置的词时长（重复设置不会累积），pitch 设置基准音高，volume 设置振幅
+func (te *ToneEngine) SetVoiceParameters(params map[string]interface{}) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	if speed, ok := params["speed"].(float64); ok && speed > 0 {
+		te.config.WordDuration = time.Duration(float64(te.baseWordDuration) / speed)
+	}
+	if pitch, ok := params["pitch"].(float64); ok && pitch > 0 {
+		te.config.BaseFrequency = pitch
+	}
+	if volume, ok := params["volume"].(float64); ok && volume >= 0 && volume <= 1 {
+		te.config.Amplitude = volume
+	}
+
+	return nil
+}
+
+// GetEngineInfo 获取引擎信息
+func (te *ToneEngine) GetEngineInfo() realtimetts.EngineInfo {
+	te.mu.RLock()
+	defer te.mu.RUnlock()
+
+	return realtimetts.EngineInfo{
+		Name:         te.config.Name,
+		Version:      "1.0.0",
+		Description:  "离线测试音引擎",
+		Capabilities: []string{"pcm-format", "real-time-synthesis", "timestamp-support", "offline", "fault-injection"},
+		Config: map[string]string{
+			"waveform": te.config.Waveform,
+		},
+	}
+}
+
+// Fingerprint 实现 realtimetts.Fingerprinter，包含波形、语音参数和输出格式
+func (te *ToneEngine) Fingerprint() string {
+	te.mu.RLock()
+	defer te.mu.RUnlock()
+	return realtimetts.FingerprintOf(te.config)
+}
+
+// Initialize 初始化引擎
+func (te *ToneEngine) Initialize() error {
+	return nil
+}
+
+// Close 关闭引擎
+func (te *ToneEngine) Close() error {
+	return nil
+}
@@ -0,0 +1,149 @@
+package engines_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// collectFrames 读取合成流中的全部帧
+func collectFrames(t *testing.T, engine realtimetts.TTSEngine, text string) ([]realtimetts.Frame, error) {
+	t.Helper()
+	stream, err := engine.Synthesize(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []realtimetts.Frame
+	for frame := range stream.Frames() {
+		frames = append(frames, frame)
+	}
+	return frames, stream.Err()
+}
+
+func TestToneEngineTimingMatchesAudio(t *testing.T) {
+	engine := engines.NewToneEngine(engines.ToneConfig{
+		WordDuration: 100 * time.Millisecond,
+		WordGap:      20 * time.Millisecond,
+		ChunkSize:    640,
+	})
+
+	frames, err := collectFrames(t, engine, "你好, world!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var words []realtimetts.TimingInfo
+	var audio []byte
+	var elapsed time.Duration
+	for _, frame := range frames {
+		if frame.Timing != nil {
+			if frame.Timing.StartTime != elapsed {
+				t.Fatalf("词 %q 开始时间 %v, 实际音频位置 %v", frame.Timing.Word, frame.Timing.StartTime, elapsed)
+			}
+			words = append(words, *frame.Timing)
+		}
+		if len(frame.Data) > 640 {
+			t.Fatalf("帧大小 %d 超过 ChunkSize", len(frame.Data))
+		}
+		audio = append(audio, frame.Data...)
+		elapsed += frame.Duration()
+	}
+
+	if len(words) != 3 || words[0].Word != "你" || words[2].Word != "world" {
+		t.Fatalf("时间信息不正确: %+v", words)
+	}
+	if want := 340 * time.Millisecond; elapsed != want {
+		t.Fatalf("音频总时长 %v, 期望 %v", elapsed, want)
+	}
+
+	// 相同输入的输出完全一致
+	again, _ := collectFrames(t, engine, "你好, world!")
+	var audioAgain []byte
+	for _, frame := range again {
+		audioAgain = append(audioAgain, frame.Data...)
+	}
+	if !bytes.Equal(audio, audioAgain) {
+		t.Fatal("相同文本的输出应保持一致")
+	}
+}
+
+func TestToneEngineFaultInjection(t *testing.T) {
+	engine := engines.NewToneEngine(engines.ToneConfig{ChunkSize: 640})
+	boom := errors.New("boom")
+	engine.SetFault(func(call int, text string) *engines.ToneFault {
+		if call == 1 {
+			return &engines.ToneFault{Err: boom, AfterChunks: 2}
+		}
+		return nil
+	})
+
+	frames, err := collectFrames(t, engine, "测试故障")
+	if !errors.Is(err, boom) {
+		t.Fatalf("应报告注入的错误, 实际: %v", err)
+	}
+	audioFrames := 0
+	for _, frame := range frames {
+		if frame.HasAudio() {
+			audioFrames++
+		}
+	}
+	if audioFrames != 2 {
+		t.Fatalf("应在 2 帧后失败, 实际输出 %d 帧", audioFrames)
+	}
+
+	if _, err := collectFrames(t, engine, "测试故障"); err != nil {
+		t.Fatalf("第二次合成应成功: %v", err)
+	}
+}
+
+func TestToneEngineSpeedDoesNotCompound(t *testing.T) {
+	engine := engines.NewToneEngine(engines.ToneConfig{WordDuration: 200 * time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		if err := engine.SetVoiceParameters(map[string]interface{}{"speed": 2.0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := engine.GetToneConfig().WordDuration; got != 100*time.Millisecond {
+		t.Fatalf("重复设置 speed=2 后词时长应为 100ms, 实际 %v", got)
+	}
+
+	engine.SetVoiceParameters(map[string]interface{}{"speed": 1.0})
+	if got := engine.GetToneConfig().WordDuration; got != 200*time.Millisecond {
+		t.Fatalf("speed=1 应恢复配置的词时长, 实际 %v", got)
+	}
+}
+
+func TestToneEngineNoiseIsDeterministic(t *testing.T) {
+	synthesize := func(engine *engines.ToneEngine, text string) []byte {
+		frames, err := collectFrames(t, engine, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var audio []byte
+		for _, frame := range frames {
+			audio = append(audio, frame.Data...)
+		}
+		return audio
+	}
+
+	engine := engines.NewToneEngine(engines.ToneConfig{Waveform: engines.ToneWaveformNoise, WordDuration: 20 * time.Millisecond})
+	first := synthesize(engine, "hello world")
+	synthesize(engine, "other text")
+	if !bytes.Equal(first, synthesize(engine, "hello world")) {
+		t.Fatal("相同文本的噪声应与调用次数无关")
+	}
+
+	fresh := engines.NewToneEngine(engines.ToneConfig{Waveform: engines.ToneWaveformNoise, WordDuration: 20 * time.Millisecond})
+	if !bytes.Equal(first, synthesize(fresh, "hello world")) {
+		t.Fatal("相同配置的引擎应输出相同的噪声")
+	}
+	if bytes.Equal(first, synthesize(fresh, "hello there")) {
+		t.Fatal("不同文本的噪声应不同")
+	}
+}
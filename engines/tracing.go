@@ -0,0 +1,49 @@
+package engines
+
+import (
+	"errors"
+	"net/http"
+	realtimetts "realtimetts/pkg"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startRequestSpan 为引擎的 HTTP 请求创建客户端 Span，并注入追踪请求头
+// Span 使用请求上下文中父 Span 的 TracerProvider，未启用追踪时不记录
+func startRequestSpan(req *http.Request, engine string) (*http.Request, trace.Span) {
+	ctx := req.Context()
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(realtimetts.TracerName)
+	ctx, span := tracer.Start(ctx, engine+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			realtimetts.AttrEngine.String(engine),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.path", req.URL.Path),
+		))
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, span
+}
+
+// endRequestSpan 记录响应状态或错误并结束 Span
+func endRequestSpan(span trace.Span, resp *http.Response, err error) {
+	// 非 2xx 响应由调用方关闭并返回 nil，状态码取自分类后的错误
+	var engineErr *realtimetts.EngineError
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	} else if errors.As(err, &engineErr) && engineErr.HTTPStatus != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", engineErr.HTTPStatus))
+	}
+	if err != nil {
+		// 错误信息可能含有机密，记录脱敏后的文本
+		message := realtimetts.Redact(err.Error())
+		span.SetStatus(codes.Error, message)
+		span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", message)))
+	}
+	span.End()
+}
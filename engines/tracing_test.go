@@ -0,0 +1,110 @@
+package engines_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+func TestTextToAudioStreamTracing(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	traceparent := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent <- r.Header.Get("Traceparent")
+		w.Write(make([]byte, 4800))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	config := realtimetts.DefaultStreamConfig()
+	config.Output = realtimetts.NewNullOutput(config.AudioConfig, false)
+	stream := realtimetts.NewTextToAudioStream([]realtimetts.TTSEngine{engines.NewOpenAIEngine(server.URL, "sk-test")}, config)
+	defer stream.Close()
+	stream.SetTracerProvider(provider)
+
+	done := make(chan struct{})
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStop = func() { close(done) }
+	stream.SetCallbacks(callbacks)
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "handle request")
+	if err := stream.FeedContext(ctx, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	request.End()
+	stream.PlayAsync()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待播放结束超时")
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	parents := map[string]string{
+		"tts.utterance":   "handle request",
+		"tts.sentence":    "tts.utterance",
+		"tts.synthesize":  "tts.sentence",
+		"OpenAI TTS POST": "tts.synthesize",
+		"tts.buffering":   "tts.utterance",
+		"tts.playback":    "tts.utterance",
+	}
+	for name, parent := range parents {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("缺少 Span %s, 已记录: %v", name, spanNames(recorder.Ended()))
+		}
+		if span.Parent().SpanID() != spans[parent].SpanContext().SpanID() {
+			t.Fatalf("%s 的父 Span 应为 %s", name, parent)
+		}
+	}
+
+	var attempt int64
+	for _, attr := range spans["tts.synthesize"].Attributes() {
+		if attr.Key == realtimetts.AttrAttempt {
+			attempt = attr.Value.AsInt64()
+		}
+	}
+	if attempt != 1 {
+		t.Fatalf("tts.synthesize 应记录第 1 次尝试, 实际 %d", attempt)
+	}
+
+	client := spans["OpenAI TTS POST"]
+	header := <-traceparent
+	if header == "" || header[36:52] != client.SpanContext().SpanID().String() {
+		t.Fatalf("请求头应携带客户端 Span: %q", header)
+	}
+	for _, attr := range client.Attributes() {
+		if attr.Key == "http.response.status_code" && attr.Value.AsInt64() != 200 {
+			t.Fatalf("状态码属性不正确: %v", attr.Value)
+		}
+	}
+}
+
+// spanNames 返回 Span 名称，用于失败信息
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	return names
+}
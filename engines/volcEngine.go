@@ -5,11 +5,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
 	realtimetts "realtimetts/pkg"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,8 +19,12 @@ type VolcengineEngine struct {
 	*realtimetts.BaseEngine
 	client *http.Client
 	config VolcengineConfig
+	retry  *realtimetts.RetryPolicy
 
-	// 统计信息
+	// 凭据来源，设置后优先于 config 中的 AppID、AccessToken 和 Cluster
+	credentials realtimetts.CredentialProvider
+
+	// 统计信息，Synthesize 可并发调用，通过 atomic 更新
 	totalBytesSent  int64 // 总发送字节数
 	totalChunksSent int64 // 总发送块数
 	chunkSequence   int64 // 音频块序列号
@@ -31,6 +36,7 @@ type VolcengineEngine struct {
 
 // VolcengineConfig 火山云配置
 type VolcengineConfig struct {
+	Name          string  `json:"name"`
 	AppID         string  `json:"app_id"`
 	AccessToken   string  `json:"access_token"`
 	Cluster       string  `json:"cluster"`
@@ -60,6 +66,46 @@ type VolcengineResponse struct {
 	Addition  VolcAddition `json:"addition"`
 }
 
+// 火山云响应码
+const (
+	VolcCodeSuccess            = 3000 // 成功
+	VolcCodeInvalidRequest     = 3001 // 无效的请求参数
+	VolcCodeConcurrencyLimit   = 3003 // 并发超限
+	VolcCodeServerBusy         = 3005 // 后端服务忙
+	VolcCodeServiceInterrupted = 3006 // 服务中断
+	VolcCodeTextTooLong        = 3010 // 文本长度超限
+	VolcCodeInvalidText        = 3011 // 无效文本
+	VolcCodeProcessTimeout     = 3030 // 处理超时
+	VolcCodeProcessError       = 3031 // 处理错误
+	VolcCodeWaitTimeout        = 3032 // 等待获取音频超时
+	VolcCodeBackendConnError   = 3040 // 后端链路连接错误
+	VolcCodeVoiceNotExist      = 3050 // 音色不存在
+)
+
+// classifyVolcCode 将火山云响应码映射为分类错误，成功返回 nil
+func classifyVolcCode(engine string, code int, message string) *realtimetts.EngineError {
+	if code == VolcCodeSuccess {
+		return nil
+	}
+
+	engineErr := &realtimetts.EngineError{Engine: engine, Code: code, Message: message}
+	switch code {
+	case VolcCodeInvalidRequest, VolcCodeTextTooLong, VolcCodeInvalidText, VolcCodeVoiceNotExist:
+		engineErr.Kind = realtimetts.ErrEngineInvalidInput
+	case VolcCodeConcurrencyLimit:
+		engineErr.Kind, engineErr.Retryable = realtimetts.ErrEngineQuota, true
+	case VolcCodeServerBusy, VolcCodeServiceInterrupted:
+		engineErr.Kind, engineErr.Retryable = realtimetts.ErrEngineBusy, true
+	case VolcCodeProcessTimeout, VolcCodeWaitTimeout:
+		engineErr.Kind, engineErr.Retryable = realtimetts.ErrEngineTimeout, true
+	case VolcCodeProcessError, VolcCodeBackendConnError:
+		engineErr.Kind, engineErr.Retryable = realtimetts.ErrEngineServer, true
+	default:
+		engineErr.Kind = realtimetts.ErrEngineServer
+	}
+	return engineErr
+}
+
 // VolcAddition 火山云附加信息
 type VolcAddition struct {
 	Frontend string `json:"frontend"`
@@ -81,6 +127,7 @@ func NewVolcengineEngine(appID, accessToken, cluster string) *VolcengineEngine {
 			Timeout: 30 * time.Second,
 		},
 		config: VolcengineConfig{
+			Name:          "Volcengine TTS",
 			AppID:         appID,
 			AccessToken:   accessToken,
 			Cluster:       cluster,
@@ -98,6 +145,7 @@ func NewVolcengineEngine(appID, accessToken, cluster string) *VolcengineEngine {
 			TextType:      "plain",
 			Ssml:          false,
 		},
+		retry:    realtimetts.DefaultRetryPolicy(),
 		stopChan: make(chan struct{}),
 	}
 
@@ -112,12 +160,21 @@ func NewVolcengineEngine(appID, accessToken, cluster string) *VolcengineEngine {
 	config.Channels = 1
 
 	engine.SetConfig(*config)
+	realtimetts.RegisterSecret(accessToken)
 
 	return engine
 }
 
 // doInitialize 初始化火山云引擎
 func (ve *VolcengineEngine) doInitialize() error {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+
+	// 凭据来源在每次请求时提供凭据
+	if ve.credentials != nil {
+		return nil
+	}
+
 	// 验证配置
 	if ve.config.AppID == "" {
 		return fmt.Errorf("火山云引擎需要AppID")
@@ -133,51 +190,39 @@ func (ve *VolcengineEngine) doInitialize() error {
 }
 
 // DoSynthesize 执行火山云文本合成
-func (ve *VolcengineEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- []byte) error {
-	fmt.Printf("   开始火山云合成: %s\n", text)
+func (ve *VolcengineEngine) DoSynthesize(ctx context.Context, text string, outputChan chan<- realtimetts.Frame) error {
+	logger := ve.Logger()
+	config, err := ve.resolveConfig(ctx)
+	if err != nil {
+		return err
+	}
 
 	// 构建请求参数
-	params := ve.buildRequestParams(text)
-	fmt.Printf("   请求参数构建完成\n")
-
-	// 发送请求
-	fmt.Printf("   发送HTTP请求到: %s\n", ve.config.Endpoint)
-	resp, err := ve.sendRequest(ctx, params)
+	params := ve.buildRequestParams(config, text)
+
+	// 发送请求，可重试的错误按退避策略重试，总耗时受 ctx 截止时间限制
+	startTime := time.Now()
+	var resp *VolcengineResponse
+	err = ve.GetRetryPolicy().Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = ve.sendRequest(ctx, config, params)
+		return err
+	})
 	if err != nil {
-		fmt.Printf("   HTTP请求失败: %v\n", err)
+		ve.mu.RLock()
+		realtimetts.InvalidateOnAuthError(ve.credentials, err)
+		ve.mu.RUnlock()
+		logger.Warn("火山云请求失败", realtimetts.LogKeyError, err, realtimetts.LogKeyLatency, time.Since(startTime))
 		return fmt.Errorf("火山云请求失败: %w", err)
 	}
 
-	fmt.Printf("   收到响应: Code=%d, Message=%s\n", resp.Code, resp.Message)
-
-	// 处理响应
-	if resp.Code != 3000 {
-		fmt.Printf("   火山云合成失败: %s\n", resp.Message)
-		return fmt.Errorf("火山云合成失败: %s", resp.Message)
-	}
-
 	// 解码音频数据
-	fmt.Printf("   开始解码音频数据, 长度: %d\n", len(resp.Data))
 	audioData, err := base64.StdEncoding.DecodeString(resp.Data)
 	if err != nil {
-		fmt.Printf("   音频数据解码失败: %v\n", err)
 		return fmt.Errorf("音频数据解码失败: %w", err)
 	}
-
-	fmt.Printf("   音频数据解码成功, 解码后长度: %d 字节\n", len(audioData))
-
-	// 检查音频数据的前几个字节
-	if len(audioData) >= 8 {
-		fmt.Printf("   音频数据前8字节: %v\n", audioData[:8])
-	}
-
-	// 保存音频数据到本地文件
-	filename := fmt.Sprintf("volcengine_audio_%d.pcm", time.Now().Unix())
-	if err := os.WriteFile(filename, audioData, 0644); err != nil {
-		fmt.Printf("   保存音频文件失败: %v\n", err)
-	} else {
-		fmt.Printf("   音频已保存到文件: %s\n", filename)
-	}
+	logger.Debug("收到火山云响应", "code", resp.Code, "reqid", resp.ReqID,
+		realtimetts.LogKeyBytes, len(audioData), realtimetts.LogKeyLatency, time.Since(startTime))
 
 	// 检查是否需要字节序转换（PCM数据通常是小端序）
 	// 这里可以根据需要添加字节序转换逻辑
@@ -186,15 +231,40 @@ func (ve *VolcengineEngine) DoSynthesize(ctx context.Context, text string, outpu
 	return ve.sendAudioInChunks(audioData, outputChan, ctx)
 }
 
+// resolveConfig 返回本次请求使用的配置，设置了凭据来源时以其提供的凭据为准
+func (ve *VolcengineEngine) resolveConfig(ctx context.Context) (VolcengineConfig, error) {
+	ve.mu.RLock()
+	config, provider := ve.config, ve.credentials
+	ve.mu.RUnlock()
+
+	if provider == nil {
+		return config, nil
+	}
+	credentials, err := provider.Credentials(ctx)
+	if err != nil {
+		return config, err
+	}
+	if v := credentials["app_id"]; v != "" {
+		config.AppID = v
+	}
+	if v := credentials["access_token"]; v != "" {
+		config.AccessToken = v
+	}
+	if v := credentials["cluster"]; v != "" {
+		config.Cluster = v
+	}
+	return config, nil
+}
+
 // buildRequestParams 构建请求参数
-func (ve *VolcengineEngine) buildRequestParams(text string) map[string]interface{} {
+func (ve *VolcengineEngine) buildRequestParams(config VolcengineConfig, text string) map[string]interface{} {
 	params := make(map[string]interface{})
 
 	// app参数
 	params["app"] = map[string]interface{}{
-		"appid":   ve.config.AppID,
-		"token":   ve.config.AccessToken,
-		"cluster": ve.config.Cluster,
+		"appid":   config.AppID,
+		"token":   config.AccessToken,
+		"cluster": config.Cluster,
 	}
 
 	// user参数
@@ -204,13 +274,13 @@ func (ve *VolcengineEngine) buildRequestParams(text string) map[string]interface
 
 	// audio参数
 	params["audio"] = map[string]interface{}{
-		"voice_type":       ve.config.VoiceType,
+		"voice_type":       config.VoiceType,
 		"encoding":         "pcm", // 使用pcm格式，支持流式
 		"compression_rate": 1,
-		"rate":             ve.config.Rate,
-		"speed_ratio":      ve.config.SpeedRatio,
-		"volume_ratio":     ve.config.VolumeRatio,
-		"pitch_ratio":      ve.config.PitchRatio,
+		"rate":             config.Rate,
+		"speed_ratio":      config.SpeedRatio,
+		"volume_ratio":     config.VolumeRatio,
+		"pitch_ratio":      config.PitchRatio,
 		"emotion":          "happy",
 		"language":         "cn",
 	}
@@ -236,55 +306,74 @@ func (ve *VolcengineEngine) buildRequestParams(text string) map[string]interface
 }
 
 // sendRequest 发送HTTP请求
-func (ve *VolcengineEngine) sendRequest(ctx context.Context, params map[string]interface{}) (*VolcengineResponse, error) {
+// 传输错误、非 2xx 状态码和非成功响应码均返回 *realtimetts.EngineError
+// 每次请求（含重试）创建一个 HTTP 客户端 Span
+func (ve *VolcengineEngine) sendRequest(ctx context.Context, config VolcengineConfig, params map[string]interface{}) (_ *VolcengineResponse, err error) {
 	// 序列化请求参数
 	requestBody, err := json.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求参数失败: %w", err)
 	}
 
-	// 格式化打印请求体
-	var formatted map[string]interface{}
-	if json.Unmarshal(requestBody, &formatted) == nil {
-		prettyBytes, _ := json.MarshalIndent(formatted, "", "  ")
-		fmt.Printf("   请求体:\n%s\n", string(prettyBytes))
-	} else {
-		fmt.Printf("   请求体: %s\n", string(requestBody))
+	// 请求体脱敏后记录，仅在 Debug 级别生成
+	if logger := ve.Logger(); logger.Enabled(ctx, slog.LevelDebug) {
+		logger.Debug("发送火山云请求", "endpoint", config.Endpoint, "body", realtimetts.RedactJSON(requestBody))
 	}
 
 	// 创建HTTP请求
-	req, err := http.NewRequestWithContext(ctx, "POST", ve.config.Endpoint, strings.NewReader(string(requestBody)))
+	req, err := http.NewRequestWithContext(ctx, "POST", config.Endpoint, strings.NewReader(string(requestBody)))
 	if err != nil {
 		return nil, err
 	}
 
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer;%s", ve.config.AccessToken))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer;%s", config.AccessToken))
 
 	// 发送请求
-	resp, err := ve.client.Do(req)
+	req, span := startRequestSpan(req, "Volcengine TTS")
+	var resp *http.Response
+	defer func() { endRequestSpan(span, resp, err) }()
+	resp, err = ve.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, realtimetts.ClassifyTransportError(config.Name, err)
 	}
 	defer resp.Body.Close()
 
-	// 解析响应
+	// 解析响应，错误状态码的响应体也可能带有火山云响应码
 	var volcResp VolcengineResponse
-	if err := json.NewDecoder(resp.Body).Decode(&volcResp); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+	decodeErr := json.NewDecoder(resp.Body).Decode(&volcResp)
+
+	if httpErr := realtimetts.ClassifyHTTPStatus(config.Name, resp.StatusCode, volcResp.Message); httpErr != nil {
+		// 认证失败以 HTTP 状态码为准，其余优先使用响应码分类
+		if volcResp.Code != 0 && httpErr.Kind != realtimetts.ErrEngineAuth {
+			if codeErr := classifyVolcCode(config.Name, volcResp.Code, volcResp.Message); codeErr != nil {
+				codeErr.HTTPStatus = resp.StatusCode
+				return nil, codeErr
+			}
+		}
+		return nil, httpErr
+	}
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", decodeErr)
+	}
+	if codeErr := classifyVolcCode(config.Name, volcResp.Code, volcResp.Message); codeErr != nil {
+		return nil, codeErr
 	}
 
 	return &volcResp, nil
 }
 
 // sendAudioInChunks 分块发送音频数据
-func (ve *VolcengineEngine) sendAudioInChunks(audioData []byte, outputChan chan<- []byte, ctx context.Context) error {
+func (ve *VolcengineEngine) sendAudioInChunks(audioData []byte, outputChan chan<- realtimetts.Frame, ctx context.Context) error {
 	// 调整为更小的块大小，更适合音频播放
 	// 对于16kHz、16位、单声道，每个样本2字节
 	// 1024个样本 = 2048字节，约0.064秒的音频
 	chunkSize := 2048 // 2KB chunks
 	totalSize := len(audioData)
+	format := ve.GetStreamInfo()
+	var pts time.Duration
 
 	for i := 0; i < totalSize; i += chunkSize {
 		end := i + chunkSize
@@ -293,24 +382,21 @@ func (ve *VolcengineEngine) sendAudioInChunks(audioData []byte, outputChan chan<
 		}
 
 		chunk := audioData[i:end]
-		// 移除未使用的变量 timestamp
+		frame := realtimetts.NewAudioFrame(chunk, format)
+		frame.PTS = pts
 
-		// 计算持续时间
-		bytesPerSecond := ve.config.Rate * ve.config.Channels * (ve.config.BitDepth / 8)
-		duration := time.Duration(len(chunk)) * time.Second / time.Duration(bytesPerSecond)
+		pts += frame.Duration()
 
 		// 直接发送音频数据
-		ve.chunkSequence++
+		atomic.AddInt64(&ve.chunkSequence, 1)
 
 		// 流式发送：等待通道有空间再发送
 		for {
 			select {
-			case outputChan <- chunk:
+			case outputChan <- frame:
 				// 发送成功，更新统计信息
-				ve.totalBytesSent += int64(len(chunk))
-				ve.totalChunksSent++
-				fmt.Printf("   发送音频块: %d 字节, 持续时间: %v (总发送: %d 字节, %d 块)\n",
-					len(chunk), duration, ve.totalBytesSent, ve.totalChunksSent)
+				atomic.AddInt64(&ve.totalBytesSent, int64(len(chunk)))
+				atomic.AddInt64(&ve.totalChunksSent, 1)
 				goto nextChunk
 			case <-ctx.Done():
 				return ctx.Err()
@@ -318,7 +404,6 @@ func (ve *VolcengineEngine) sendAudioInChunks(audioData []byte, outputChan chan<
 				return fmt.Errorf("引擎已停止")
 			default:
 				// 通道已满，等待一段时间再尝试
-				fmt.Printf("   ⏳ 输出通道已满，等待空间...\n")
 				select {
 				case <-time.After(50 * time.Millisecond):
 					// 等待50ms后重试
@@ -390,6 +475,7 @@ func (ve *VolcengineEngine) SetConfig(config realtimetts.EngineConfig) error {
 	// 更新火山云特定配置
 	if config.APIKey != "" {
 		ve.config.AccessToken = config.APIKey
+		realtimetts.RegisterSecret(config.APIKey)
 	}
 	if config.Endpoint != "" {
 		ve.config.Endpoint = config.Endpoint
@@ -407,15 +493,16 @@ func (ve *VolcengineEngine) SetConfig(config realtimetts.EngineConfig) error {
 		ve.config.Channels = config.Channels
 	}
 
-	ve.config = ve.config
-
 	return nil
 }
 
 // GetEngineInfo 获取引擎信息
 func (ve *VolcengineEngine) GetEngineInfo() realtimetts.EngineInfo {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+
 	return realtimetts.EngineInfo{
-		Name:         "Volcengine TTS",
+		Name:         ve.config.Name,
 		Version:      "1.0.0",
 		Description:  "火山云语音合成服务",
 		Capabilities: []string{"pcm-format", "real-time-synthesis", "timestamp-support", "text-to-speech", "voice-selection"},
@@ -423,8 +510,19 @@ func (ve *VolcengineEngine) GetEngineInfo() realtimetts.EngineInfo {
 	}
 }
 
+// Fingerprint 实现 realtimetts.Fingerprinter，包含语音类型、语速等合成配置，不含凭据
+func (ve *VolcengineEngine) Fingerprint() string {
+	config := ve.GetVolcengineConfig()
+	config.AppID, config.AccessToken = "", ""
+	return realtimetts.FingerprintOf(config)
+}
+
 // SetVolcengineConfig 设置火山云特定配置
 func (ve *VolcengineEngine) SetVolcengineConfig(config VolcengineConfig) error {
+	if config.Name == "" {
+		config.Name = "Volcengine TTS"
+	}
+
 	ve.mu.Lock()
 	defer ve.mu.Unlock()
 
@@ -433,9 +531,36 @@ func (ve *VolcengineEngine) SetVolcengineConfig(config VolcengineConfig) error {
 		ve.config.Rate = config.Rate
 	}
 	ve.config = config
+	realtimetts.RegisterSecret(config.AccessToken)
 	return nil
 }
 
+// SetCredentialProvider 设置凭据来源，每次请求前获取 app_id、access_token 和 cluster
+// 认证失败时会让来源丢弃缓存的凭据
+func (ve *VolcengineEngine) SetCredentialProvider(provider realtimetts.CredentialProvider) {
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+	ve.credentials = provider
+}
+
+// SetRetryPolicy 设置请求重试策略，传入 nil 表示不重试
+func (ve *VolcengineEngine) SetRetryPolicy(policy *realtimetts.RetryPolicy) {
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+
+	if policy == nil {
+		policy = &realtimetts.RetryPolicy{MaxAttempts: 1}
+	}
+	ve.retry = policy
+}
+
+// GetRetryPolicy 获取请求重试策略
+func (ve *VolcengineEngine) GetRetryPolicy() *realtimetts.RetryPolicy {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+	return ve.retry
+}
+
 // GetVolcengineConfig 获取火山云配置
 func (ve *VolcengineEngine) GetVolcengineConfig() VolcengineConfig {
 	ve.mu.RLock()
@@ -445,9 +570,7 @@ func (ve *VolcengineEngine) GetVolcengineConfig() VolcengineConfig {
 
 // GetVolcengineStats 获取火山云引擎统计信息
 func (ve *VolcengineEngine) GetVolcengineStats() (int64, int64) {
-	ve.mu.RLock()
-	defer ve.mu.RUnlock()
-	return ve.totalBytesSent, ve.totalChunksSent
+	return atomic.LoadInt64(&ve.totalBytesSent), atomic.LoadInt64(&ve.totalChunksSent)
 }
 
 // TTSEngine 接口实现
@@ -464,17 +587,15 @@ func (ve *VolcengineEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
 }
 
 // Synthesize 执行文本到音频的合成
-func (ve *VolcengineEngine) Synthesize(ctx context.Context, text string) (<-chan []byte, error) {
-	outputChan := make(chan []byte, 100)
+// 合成失败时通过返回流的 Err 报告错误
+func (ve *VolcengineEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(100)
 
 	go func() {
-		defer close(outputChan)
-		if err := ve.DoSynthesize(ctx, text, outputChan); err != nil {
-			fmt.Printf("火山云合成失败: %v\n", err)
-		}
+		stream.CloseWithError(ve.DoSynthesize(ctx, text, stream.Writer()))
 	}()
 
-	return outputChan, nil
+	return stream, nil
 }
 
 // GetVoices 获取可用语音列表
@@ -518,11 +639,15 @@ func (ve *VolcengineEngine) Initialize() error {
 	return ve.doInitialize()
 }
 
-// Close 关闭引擎
+// Close 关闭引擎，可重复调用（TextToAudioStream.Close 也会关闭其引擎）
 func (ve *VolcengineEngine) Close() error {
 	ve.mu.Lock()
 	defer ve.mu.Unlock()
 
-	close(ve.stopChan)
+	select {
+	case <-ve.stopChan:
+	default:
+		close(ve.stopChan)
+	}
 	return nil
 }
@@ -2,7 +2,14 @@ package engines_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,17 +36,21 @@ func TestVolcengineEngine(t *testing.T) {
 func createTestVolcengineEngine(t *testing.T) *engines.VolcengineEngine {
 	t.Helper()
 	
+	// 真实服务的凭据只从环境变量读取，未设置时跳过
+	credentials := realtimetts.EnvCredentials(map[string]string{
+		"app_id":       "VOLC_APP_ID",
+		"access_token": "VOLC_ACCESS_TOKEN",
+	})
+	if _, err := credentials.Credentials(context.Background()); err != nil {
+		t.Skipf("跳过火山云集成测试: %v", err)
+	}
+
 	fmt.Println("\n1. 创建火山云TTS引擎...")
-	volcEngine := engines.NewVolcengineEngine(
-		"1882614830",                       // AppID
-		"VnqZAkrU_-Ywt3j4zz8D3b5lVeh0U6j5", // AccessToken
-		"volcano_tts",                      // Cluster
-	)
+	volcEngine := engines.NewVolcengineEngine("", "", "volcano_tts")
+	volcEngine.SetCredentialProvider(credentials)
 
 	// 设置火山云特定配置
 	volcConfig := engines.VolcengineConfig{
-		AppID:         "1882614830",
-		AccessToken:   "VnqZAkrU_-Ywt3j4zz8D3b5lVeh0U6j5",
 		Cluster:       "volcano_tts",
 		Endpoint:      "https://openspeech.bytedance.com/api/v1/tts",
 		VoiceType:     "BV700_streaming",
@@ -169,8 +180,8 @@ func testVolcengineEngineBasic(t *testing.T, volcEngine *engines.VolcengineEngin
 		fmt.Printf("   ❌ 引擎错误: %s - %v\n", engineName, err)
 	}
 
-	callbacks.OnEngineSwitch = func(oldEngine, newEngine string) {
-		fmt.Printf("   🔄 引擎切换: %s -> %s\n", oldEngine, newEngine)
+	callbacks.OnEngineSwitch = func(oldEngine, newEngine, reason string) {
+		fmt.Printf("   🔄 引擎切换: %s -> %s (%s)\n", oldEngine, newEngine, reason)
 	}
 
 	// 系统状态回调
@@ -245,10 +256,9 @@ func testVolcengineEngineBasic(t *testing.T, volcEngine *engines.VolcengineEngin
 	// 10. 获取状态信息
 	fmt.Println("\n10. 获取状态信息...")
 	status := stream.GetStatus()
-	fmt.Printf("   播放状态: %v\n", status["is_playing"])
-	fmt.Printf("   暂停状态: %v\n", status["is_paused"])
-	fmt.Printf("   当前引擎: %v\n", status["current_engine"])
-	fmt.Printf("   引擎数量: %v\n", status["engine_count"])
+	fmt.Printf("   播放状态: %v\n", status.State)
+	fmt.Printf("   当前引擎: %v (%v)\n", status.CurrentEngine, status.EngineStatus)
+	fmt.Printf("   引擎数量: %v\n", status.EngineCount)
 
 	// 10.1 获取音频统计信息
 	fmt.Println("\n10.1 音频统计信息对比...")
@@ -299,16 +309,16 @@ func testVolcengineEngineSynthesis(t *testing.T, volcEngine *engines.VolcengineE
 	
 	// 测试简单的文本合成
 	ctx := context.Background()
-	outputChan, err := volcEngine.Synthesize(ctx, "测试文本合成功能")
+	stream, err := volcEngine.Synthesize(ctx, "测试文本合成功能")
 	if err != nil {
 		t.Fatalf("文本合成失败: %v", err)
 	}
 	
 	// 读取一些音频数据
 	count := 0
-	for audioData := range outputChan {
+	for frame := range stream.Frames() {
 		if count < 5 { // 只读取前5个音频块
-			fmt.Printf("收到音频数据: %d 字节\n", len(audioData))
+			fmt.Printf("收到音频数据: %d 字节\n", len(frame.Data))
 			count++
 		} else {
 			break
@@ -316,4 +326,138 @@ func testVolcengineEngineSynthesis(t *testing.T, volcEngine *engines.VolcengineE
 	}
 	
 	fmt.Printf("成功接收 %d 个音频块\n", count)
+	if count < 5 {
+		if err := stream.Err(); err != nil {
+			t.Logf("合成失败: %v", err)
+		}
+	}
+}
+
+// newStubVolcengineEngine 创建指向本地测试服务的火山云引擎
+func newStubVolcengineEngine(t *testing.T, handler http.HandlerFunc) *engines.VolcengineEngine {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	volcEngine := engines.NewVolcengineEngine("test-app", "test-token", "volcano_tts")
+	config := volcEngine.GetVolcengineConfig()
+	config.Endpoint = server.URL
+	if err := volcEngine.SetVolcengineConfig(config); err != nil {
+		t.Fatalf("设置火山云配置失败: %v", err)
+	}
+	volcEngine.SetRetryPolicy(&realtimetts.RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Multiplier:   2,
+	})
+	return volcEngine
+}
+
+func TestVolcengineRetriesServerBusy(t *testing.T) {
+	var requests int32
+	volcEngine := newStubVolcengineEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			json.NewEncoder(w).Encode(engines.VolcengineResponse{Code: engines.VolcCodeServerBusy, Message: "busy"})
+			return
+		}
+		json.NewEncoder(w).Encode(engines.VolcengineResponse{
+			Code: engines.VolcCodeSuccess,
+			Data: base64.StdEncoding.EncodeToString(make([]byte, 4096)),
+		})
+	})
+
+	stream, err := volcEngine.Synthesize(context.Background(), "你好")
+	if err != nil {
+		t.Fatalf("文本合成失败: %v", err)
+	}
+	var received int
+	for frame := range stream.Frames() {
+		received += len(frame.Data)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("重试后应合成成功: %v", err)
+	}
+	if received != 4096 || atomic.LoadInt32(&requests) != 3 {
+		t.Fatalf("收到 %d 字节, 请求 %d 次", received, requests)
+	}
+}
+
+func TestVolcengineConcurrentSynthesize(t *testing.T) {
+	volcEngine := newStubVolcengineEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(engines.VolcengineResponse{
+			Code: engines.VolcCodeSuccess,
+			Data: base64.StdEncoding.EncodeToString(make([]byte, 8192)),
+		})
+	})
+
+	// 缓存预热等场景会并发调用 Synthesize，统计信息不能有数据竞争
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream, err := volcEngine.Synthesize(context.Background(), "你好")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for range stream.Frames() {
+			}
+			if err := stream.Err(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if bytes, chunks := volcEngine.GetVolcengineStats(); bytes != 4*8192 || chunks != 4*4 {
+		t.Fatalf("统计信息不正确: %d 字节, %d 块", bytes, chunks)
+	}
+}
+
+func TestVolcengineErrorClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		code      int
+		kind      error
+		retryable bool
+		requests  int32
+	}{
+		{"无效文本", http.StatusOK, engines.VolcCodeInvalidText, realtimetts.ErrEngineInvalidInput, false, 1},
+		{"并发超限", http.StatusOK, engines.VolcCodeConcurrencyLimit, realtimetts.ErrEngineQuota, true, 3},
+		{"认证失败", http.StatusUnauthorized, 0, realtimetts.ErrEngineAuth, false, 1},
+		{"服务端错误", http.StatusInternalServerError, 0, realtimetts.ErrEngineServer, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			volcEngine := newStubVolcengineEngine(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(engines.VolcengineResponse{Code: tt.code, Message: tt.name})
+			})
+
+			stream, err := volcEngine.Synthesize(context.Background(), "你好")
+			if err != nil {
+				t.Fatalf("文本合成失败: %v", err)
+			}
+			for range stream.Frames() {
+			}
+
+			err = stream.Err()
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("错误分类不符: %v", err)
+			}
+			if realtimetts.IsRetryable(err) != tt.retryable {
+				t.Fatalf("可重试判断不符: %v", err)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.requests {
+				t.Fatalf("请求 %d 次, 期望 %d 次", got, tt.requests)
+			}
+		})
+	}
 }
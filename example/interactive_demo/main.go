@@ -2,14 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
 
-	"realtimetts"
 	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
 )
 
 func main() {
@@ -36,18 +38,21 @@ func main() {
 	// 步骤1: 创建和配置TTS引擎
 	// ========================================
 	// 使用火山云TTS引擎作为语音合成服务
-	// 需要提供AppID、AccessToken和Cluster信息
+	// AppID和AccessToken从环境变量 VOLC_APP_ID、VOLC_ACCESS_TOKEN 读取，不写入代码
+	credentials := realtimetts.EnvCredentials(map[string]string{
+		"app_id":       "VOLC_APP_ID",
+		"access_token": "VOLC_ACCESS_TOKEN",
+	})
+	if _, err := credentials.Credentials(context.Background()); err != nil {
+		log.Fatalf("请先设置火山云凭据: %v", err)
+	}
+
 	fmt.Println("\n1. 创建火山云TTS引擎...")
-	volcEngine := engines.NewVolcengineEngine(
-		"1882614830",                       // AppID: 火山云应用ID
-		"VnqZAkrU_-Ywt3j4zz8D3b5lVeh0U6j5", // AccessToken: 访问令牌
-		"volcano_tts",                      // Cluster: 集群名称
-	)
+	volcEngine := engines.NewVolcengineEngine("", "", "volcano_tts")
+	volcEngine.SetCredentialProvider(credentials)
 
 	// 配置火山云TTS引擎的详细参数
 	volcConfig := engines.VolcengineConfig{
-		AppID:         "1882614830",                                  // 应用ID
-		AccessToken:   "VnqZAkrU_-Ywt3j4zz8D3b5lVeh0U6j5",            // 访问令牌
 		Cluster:       "volcano_tts",                                 // 集群名称
 		Endpoint:      "https://openspeech.bytedance.com/api/v1/tts", // API端点
 		VoiceType:     "BV700_streaming",                             // 语音类型：流式语音
@@ -136,6 +141,13 @@ func main() {
 	stream := realtimetts.NewTextToAudioStream([]realtimetts.TTSEngine{volcEngine}, streamConfig)
 	stream.SetCallbacks(callbacks)
 
+	// 诊断日志输出到标准错误，设置 TTS_DEBUG 时输出 Debug 级别
+	logLevel := slog.LevelInfo
+	if os.Getenv("TTS_DEBUG") != "" {
+		logLevel = slog.LevelDebug
+	}
+	stream.SetLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+
 	// ========================================
 	// 初始化完成，显示使用说明
 	// ========================================
@@ -222,9 +234,7 @@ func handleTextInput(text string, stream *realtimetts.TextToAudioStream, presetT
 	fmt.Printf("📝 输入文本: %s\n", text)
 
 	// 检查并重置异常状态
-	status := stream.GetStatus()
-	isPlaying := status["is_playing"].(bool)
-	if isPlaying {
+	if stream.GetStatus().IsPlaying() {
 		fmt.Println("⚠️  检测到异常播放状态，正在重置...")
 		if err := stream.Stop(); err != nil {
 			fmt.Printf("⚠️  重置状态失败: %v\n", err)
@@ -265,9 +275,7 @@ func handleSpecialCommand(command string, stream *realtimetts.TextToAudioStream)
 	switch command {
 	case "/play":
 		// 检查状态并重置异常状态
-		status := stream.GetStatus()
-		isPlaying := status["is_playing"].(bool)
-		if isPlaying {
+		if stream.GetStatus().IsPlaying() {
 			fmt.Println("⚠️  检测到异常播放状态，正在重置...")
 			if err := stream.Stop(); err != nil {
 				fmt.Printf("⚠️  重置状态失败: %v\n", err)
@@ -305,10 +313,20 @@ func handleSpecialCommand(command string, stream *realtimetts.TextToAudioStream)
 	case "/status":
 		status := stream.GetStatus()
 		fmt.Println("📊 当前状态:")
-		fmt.Printf("   播放状态: %v\n", status["is_playing"])
-		fmt.Printf("   暂停状态: %v\n", status["is_paused"])
-		fmt.Printf("   当前引擎: %v\n", status["current_engine"])
-		fmt.Printf("   引擎数量: %v\n", status["engine_count"])
+		fmt.Printf("   播放状态: %v\n", status.State)
+		if status.CurrentUtterance != nil {
+			fmt.Printf("   当前话语: #%d %s\n", status.CurrentUtterance.ID, status.CurrentUtterance.Text)
+		}
+		fmt.Printf("   待合成文本: %d\n", status.QueueLength)
+		fmt.Printf("   当前引擎: %s (%v)\n", status.CurrentEngine, status.EngineStatus)
+		fmt.Printf("   引擎数量: %d\n", status.EngineCount)
+		fmt.Printf("   缓冲音频: %.2fs\n", status.Buffer.BufferedSeconds)
+		if status.Device != nil {
+			fmt.Printf("   输出设备: %s\n", status.Device.Name)
+		}
+		if status.Latency.Completed > 0 {
+			fmt.Printf("   首音延迟: 最近 %v, P95 %v\n", status.Latency.TimeToFirstAudio.Last, status.Latency.TimeToFirstAudio.P95)
+		}
 
 	case "/reset":
 		fmt.Println("🔄 正在重置TTS状态...")
@@ -0,0 +1,175 @@
+// Package metrics 将 TTS 流水线的指标导出为 Prometheus 指标
+// 通过 TextToAudioStream.SetMetrics 注入，指标注册到调用方提供的 prometheus.Registerer
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// Config 指标配置
+type Config struct {
+	Namespace      string            // 指标名前缀，默认 realtimetts
+	ConstLabels    prometheus.Labels // 附加到所有指标的固定标签
+	LatencyBuckets []float64         // 延迟直方图的分桶（秒）
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{
+		Namespace:      "realtimetts",
+		LatencyBuckets: []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10},
+	}
+}
+
+// Metrics 实现 realtimetts.MetricsRecorder 的 Prometheus 指标集
+type Metrics struct {
+	timeToFirstAudio prometheus.Histogram
+	synthesisLatency *prometheus.HistogramVec
+	characters       *prometheus.CounterVec
+	bytesPlayed      prometheus.Counter
+	underruns        prometheus.Counter
+	droppedChunks    prometheus.Counter
+	failovers        *prometheus.CounterVec
+	cacheLookups     *prometheus.CounterVec
+	queueDepth       *prometheus.GaugeVec
+}
+
+var _ realtimetts.MetricsRecorder = (*Metrics)(nil)
+
+// New 创建指标集并注册到 registerer，registerer 为 nil 时使用 prometheus.DefaultRegisterer
+// 配置中未设置的字段使用 DefaultConfig 的值；任一指标注册失败时不保留已注册的指标
+func New(registerer prometheus.Registerer, config Config) (*Metrics, error) {
+	defaults := DefaultConfig()
+	if config.Namespace == "" {
+		config.Namespace = defaults.Namespace
+	}
+	if len(config.LatencyBuckets) == 0 {
+		config.LatencyBuckets = defaults.LatencyBuckets
+	}
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	ns, labels := config.Namespace, config.ConstLabels
+	m := &Metrics{
+		timeToFirstAudio: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns, Name: "time_to_first_audio_seconds", ConstLabels: labels,
+			Help:    "Time from receiving utterance text to playing its first audio frame.",
+			Buckets: config.LatencyBuckets,
+		}),
+		synthesisLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns, Name: "synthesis_latency_seconds", ConstLabels: labels,
+			Help:    "Time from sending a synthesis request to the first audio frame, or to the failure.",
+			Buckets: config.LatencyBuckets,
+		}, []string{"engine", "outcome"}),
+		characters: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns, Name: "characters_synthesized_total", ConstLabels: labels,
+			Help: "Characters successfully synthesized.",
+		}, []string{"engine"}),
+		bytesPlayed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: ns, Name: "bytes_played_total", ConstLabels: labels,
+			Help: "Audio bytes written to the output device.",
+		}),
+		underruns: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: ns, Name: "underruns_total", ConstLabels: labels,
+			Help: "Times the frame queue ran dry in the middle of an utterance.",
+		}),
+		droppedChunks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: ns, Name: "dropped_chunks_total", ConstLabels: labels,
+			Help: "Audio chunks dropped because the output buffer was full.",
+		}),
+		failovers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns, Name: "failovers_total", ConstLabels: labels,
+			Help: "Engine switches during synthesis.",
+		}, []string{"from", "to"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns, Name: "cache_lookups_total", ConstLabels: labels,
+			Help: "Synthesis cache lookups by result.",
+		}, []string{"result"}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns, Name: "queue_depth", ConstLabels: labels,
+			Help: "Current number of items in a pipeline queue.",
+		}, []string{"queue"}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.timeToFirstAudio, m.synthesisLatency, m.characters, m.bytesPlayed,
+		m.underruns, m.droppedChunks, m.failovers, m.cacheLookups, m.queueDepth,
+	}
+	var errs []error
+	var registered []prometheus.Collector
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		registered = append(registered, collector)
+	}
+	if err := errors.Join(errs...); err != nil {
+		// 撤销本次已注册的指标，调用方处理冲突后可以重试
+		for _, collector := range registered {
+			registerer.Unregister(collector)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// ObserveTimeToFirstAudio 实现 realtimetts.MetricsRecorder
+func (m *Metrics) ObserveTimeToFirstAudio(d time.Duration) {
+	m.timeToFirstAudio.Observe(d.Seconds())
+}
+
+// ObserveSynthesis 实现 realtimetts.MetricsRecorder
+func (m *Metrics) ObserveSynthesis(engine string, latency time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	m.synthesisLatency.WithLabelValues(engine, outcome).Observe(latency.Seconds())
+}
+
+// AddCharacters 实现 realtimetts.MetricsRecorder
+func (m *Metrics) AddCharacters(engine string, n int) {
+	m.characters.WithLabelValues(engine).Add(float64(n))
+}
+
+// AddBytesPlayed 实现 realtimetts.MetricsRecorder
+func (m *Metrics) AddBytesPlayed(n int) {
+	m.bytesPlayed.Add(float64(n))
+}
+
+// IncUnderrun 实现 realtimetts.MetricsRecorder
+func (m *Metrics) IncUnderrun() {
+	m.underruns.Inc()
+}
+
+// IncDroppedChunk 实现 realtimetts.MetricsRecorder
+func (m *Metrics) IncDroppedChunk() {
+	m.droppedChunks.Inc()
+}
+
+// IncFailover 实现 realtimetts.MetricsRecorder
+// 切换原因是自由文本，不作为标签以免标签基数过高
+func (m *Metrics) IncFailover(from, to, reason string) {
+	m.failovers.WithLabelValues(from, to).Inc()
+}
+
+// IncCacheLookup 实现 realtimetts.MetricsRecorder
+func (m *Metrics) IncCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// SetQueueDepth 实现 realtimetts.MetricsRecorder
+func (m *Metrics) SetQueueDepth(queue string, depth int) {
+	m.queueDepth.WithLabelValues(queue).Set(float64(depth))
+}
@@ -0,0 +1,140 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"realtimetts/cache"
+	"realtimetts/engines"
+	"realtimetts/metrics"
+	realtimetts "realtimetts/pkg"
+)
+
+func TestMetricsFromStream(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := metrics.New(registry, metrics.Config{Namespace: "tts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := engines.NewToneEngine(engines.ToneConfig{Name: "primary", WordDuration: 20 * time.Millisecond})
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		return &engines.ToneFault{Err: realtimetts.ErrEngineBusy}
+	})
+	backup := cache.NewCachedEngine(
+		engines.NewToneEngine(engines.ToneConfig{Name: "backup", WordDuration: 20 * time.Millisecond}),
+		cache.NewMemoryStore(1<<20, 16),
+	)
+
+	config := realtimetts.DefaultStreamConfig()
+	config.SentenceSilenceDuration = 0
+	output := realtimetts.NewNullOutput(config.AudioConfig, false)
+	config.Output = output
+
+	stream := realtimetts.NewTextToAudioStream([]realtimetts.TTSEngine{primary, backup}, config)
+	defer stream.Close()
+	stream.SetMetrics(m)
+
+	done := make(chan struct{}, 2)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStop = func() { done <- struct{}{} }
+	stream.SetCallbacks(callbacks)
+
+	// 同一文本两次：第二次命中 backup 的缓存
+	for i := 0; i < 2; i++ {
+		if err := stream.Feed("你好世界。"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.PlayAsync()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("等待播放结束超时")
+		}
+	}
+
+	if got := metricValue(t, registry, "tts_failovers_total", "to", "backup"); got != 1 {
+		t.Fatalf("failovers = %v, 期望 1", got)
+	}
+	if got := metricValue(t, registry, "tts_cache_lookups_total", "result", "hit"); got != 1 {
+		t.Fatalf("cache hits = %v, 期望 1", got)
+	}
+	if got := metricValue(t, registry, "tts_characters_synthesized_total", "engine", "backup"); got != 10 {
+		t.Fatalf("characters = %v, 期望 10", got)
+	}
+	if got := metricValue(t, registry, "tts_bytes_played_total", "", ""); got != float64(output.BytesWritten()) || got == 0 {
+		t.Fatalf("bytes played = %v, 输出 %d", got, output.BytesWritten())
+	}
+	if got := metricValue(t, registry, "tts_time_to_first_audio_seconds", "", ""); got != 2 {
+		t.Fatalf("time_to_first_audio 样本数 = %v, 期望 2", got)
+	}
+	if got := metricValue(t, registry, "tts_synthesis_latency_seconds", "outcome", "error"); got != 1 {
+		t.Fatalf("失败的合成样本数 = %v, 期望 1", got)
+	}
+}
+
+// metricValue 返回标签匹配的指标值，直方图返回样本数；labelName 为空时取第一个
+func metricValue(t *testing.T, registry *prometheus.Registry, name, labelName, labelValue string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := labelName == ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == labelName && label.GetValue() == labelValue {
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	t.Fatalf("未找到指标 %s{%s=%q}", name, labelName, labelValue)
+	return 0
+}
+
+func TestMetricsDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := metrics.New(registry, metrics.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := metrics.New(registry, metrics.DefaultConfig()); err == nil {
+		t.Fatal("重复注册应返回错误")
+	}
+}
+
+func TestMetricsFailedRegistrationCanBeRetried(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	conflict := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tts", Name: "queue_depth",
+		Help: "Current number of items in a pipeline queue.",
+	}, []string{"queue"})
+	registry.MustRegister(conflict)
+
+	if _, err := metrics.New(registry, metrics.Config{Namespace: "tts"}); err == nil {
+		t.Fatal("与已有指标冲突时应返回错误")
+	}
+	registry.Unregister(conflict)
+	if _, err := metrics.New(registry, metrics.Config{Namespace: "tts"}); err != nil {
+		t.Fatalf("失败的注册不应留下已注册的指标: %v", err)
+	}
+}
@@ -1,24 +1,29 @@
 package realtimetts
 
 import (
+	"context"
 	"sync"
 	"time"
 )
 
 // AudioBuffer 音频缓冲管理器
-// 直接使用 ttsAudioChan 接收音频流，提供时间信息缓冲
-// 提供get_from_buffer/get_timing_info/get_buffered_seconds
+// 持有唯一的帧队列：引擎音频、静音、时间信息和话语结束标记按写入顺序排队，
+// StreamPlayer 从同一队列按顺序取出，保证时间信息与音频对齐
+// 提供get_from_buffer/get_buffered_seconds
 type AudioBuffer struct {
-	ttsAudioChan chan [][]byte   // TTS音频输入通道
-	timings      chan TimingInfo // 时间信息缓冲区
-	config       *AudioConfiguration
+	frames chan Frame    // 统一帧队列
+	closed chan struct{} // 关闭信号
+	space  chan struct{} // 取出帧后通知等待空间的写入方
+	config *AudioConfiguration
 
 	// 状态管理
-	mu           sync.RWMutex
-	totalSamples int64 //total_samples是一个关键的计数器，用于跟踪缓冲区内音频样本的数量，是实现智能缓冲控制和时间计算的核心组件。
-	bufferSize   int   // 缓冲区大小
-	isClosed     bool  // 是否已关闭
-
+	mu              sync.RWMutex
+	totalSamples    int64 //total_samples是一个关键的计数器，用于跟踪缓冲区内音频样本的数量，是实现智能缓冲控制和时间计算的核心组件。
+	queuedTimings   int   // 队列中的时间信息数量
+	bytesProcessed  int64 // 已取出的音频字节数
+	chunksProcessed int64 // 已取出的音频块数
+	bufferSize      int   // 缓冲区大小
+	isClosed        bool  // 是否已关闭
 }
 
 // TimingInfo 时间信息结构体
@@ -32,8 +37,9 @@ type TimingInfo struct {
 // NewAudioBuffer 创建新的音频缓冲管理器
 func NewAudioBuffer(config *AudioConfiguration, bufferSize int) *AudioBuffer {
 	return &AudioBuffer{
-		ttsAudioChan: make(chan [][]byte, bufferSize),
-		timings:      make(chan TimingInfo, bufferSize),
+		frames:       make(chan Frame, bufferSize),
+		closed:       make(chan struct{}),
+		space:        make(chan struct{}, 1),
 		config:       config,
 		bufferSize:   bufferSize,
 		totalSamples: 0,
@@ -41,86 +47,174 @@ func NewAudioBuffer(config *AudioConfiguration, bufferSize int) *AudioBuffer {
 	}
 }
 
-// AddToBuffer 添加音频数据到缓冲区（已废弃，直接使用ttsAudioChan）
-func (ab *AudioBuffer) AddToBuffer(audioData []byte) error {
-	// 此方法已废弃，音频数据直接通过ttsAudioChan传递
-	return nil
+// AddToBuffer 添加音频数据到帧队列
+// 队列已满时阻塞，直到有空间、ctx 取消或缓冲区关闭
+func (abm *AudioBuffer) AddToBuffer(ctx context.Context, audioData []byte) error {
+	if len(audioData) == 0 {
+		return nil
+	}
+	return abm.PutFrame(ctx, NewAudioFrame(audioData, abm.config))
 }
 
-// AddTimingInfo 添加时间信息到缓冲区
-func (abm *AudioBuffer) AddTimingInfo(timing TimingInfo) error {
-	abm.mu.RLock()
-	if abm.isClosed {
-		abm.mu.RUnlock()
-		return ErrBufferFull
+// AddSilence 添加指定时长的静音到帧队列
+func (abm *AudioBuffer) AddSilence(ctx context.Context, duration time.Duration) error {
+	frame := NewSilenceFrame(duration, abm.config)
+	if !frame.HasAudio() {
+		return nil
+	}
+	return abm.PutFrame(ctx, frame)
+}
+
+// AddTimingInfo 添加时间信息标记到帧队列
+// 标记在其之前的音频播放完后才会被取出
+func (abm *AudioBuffer) AddTimingInfo(ctx context.Context, timing TimingInfo) error {
+	return abm.PutFrame(ctx, NewTimingFrame(timing))
+}
+
+// MarkEndOfUtterance 添加指定话语的结束标记到帧队列
+func (abm *AudioBuffer) MarkEndOfUtterance(ctx context.Context, utteranceID uint64) error {
+	return abm.PutFrame(ctx, Frame{UtteranceID: utteranceID, Flags: FrameEndOfUtterance})
+}
+
+// PutFrame 将一帧写入队列并更新计数
+// 队列已满时阻塞，直到有空间、ctx 取消或缓冲区关闭
+func (abm *AudioBuffer) PutFrame(ctx context.Context, frame Frame) error {
+	for {
+		// 入队和计数在同一次加锁中完成，取出方的计数需要同一把锁，因此总在入队计数之后
+		abm.mu.Lock()
+		if abm.isClosed {
+			abm.mu.Unlock()
+			return ErrBufferClosed
+		}
+		select {
+		case abm.frames <- frame:
+			abm.accountLocked(frame, 1)
+			abm.mu.Unlock()
+			return nil
+		default:
+		}
+		abm.mu.Unlock()
+
+		select {
+		case <-abm.space:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-abm.closed:
+			return ErrBufferClosed
+		}
 	}
-	abm.mu.RUnlock()
+}
+
+// dequeued 更新取出帧的计数并通知等待空间的写入方
+func (abm *AudioBuffer) dequeued(frame Frame) {
+	abm.mu.Lock()
+	abm.accountLocked(frame, -1)
+	abm.mu.Unlock()
 
 	select {
-	case abm.timings <- timing:
-		return nil
+	case abm.space <- struct{}{}:
 	default:
-		return ErrBufferFull
 	}
 }
 
-// GetFromBuffer 从TTS通道获取音频数据
-func (abm *AudioBuffer) GetFromBuffer(timeout time.Duration) ([]byte, error) {
-	abm.mu.RLock()
-	if abm.isClosed {
-		abm.mu.RUnlock()
-		return nil, ErrBufferEmpty
+// accountLocked 按方向更新缓冲计数，调用方需持有 abm.mu
+func (abm *AudioBuffer) accountLocked(frame Frame, sign int) {
+	if frame.Has(FrameTiming) {
+		abm.queuedTimings += sign
+	}
+	if frame.HasAudio() {
+		abm.totalSamples += int64(sign) * abm.samplesOf(frame)
 	}
-	abm.mu.RUnlock()
+}
+
+// samplesOf 计算帧折算到缓冲区采样率下的样本数
+func (abm *AudioBuffer) samplesOf(frame Frame) int64 {
+	format := frame.Format
+	if format == nil {
+		format = abm.config
+	}
+	bytesPerFrame := format.GetBytesPerFrame()
+	if bytesPerFrame <= 0 || format.SampleRate <= 0 {
+		return 0
+	}
+	samples := int64(len(frame.Data) / bytesPerFrame)
+	return samples * int64(abm.config.SampleRate) / int64(format.SampleRate)
+}
 
-	// 直接从ttsAudioChan读取音频数据
+// GetFrame 从帧队列按顺序取出下一帧
+func (abm *AudioBuffer) GetFrame(timeout time.Duration) (Frame, error) {
 	select {
-	case audioChunks, ok := <-abm.ttsAudioChan:
-		if !ok {
-			return nil, ErrBufferEmpty
-		}
-		// 返回第一个音频块
-		if len(audioChunks) > 0 {
-			audioData := audioChunks[0]
+	case frame := <-abm.frames:
+		abm.dequeued(frame)
+		if frame.HasAudio() {
 			abm.mu.Lock()
-			abm.totalSamples += int64(len(audioData) / abm.config.GetBytesPerFrame())
+			abm.bytesProcessed += int64(len(frame.Data))
+			abm.chunksProcessed++
 			abm.mu.Unlock()
-			return audioData, nil
 		}
-		return nil, ErrBufferEmpty
+		return frame, nil
+	case <-abm.closed:
+		return Frame{}, ErrBufferClosed
 	case <-time.After(timeout):
-		return nil, ErrBufferTimeout
+		return Frame{}, ErrBufferTimeout
 	}
 }
 
-// GetTimingInfo 从缓冲区获取时间信息
-func (abm *AudioBuffer) GetTimingInfo(timeout time.Duration) (TimingInfo, error) {
-	abm.mu.RLock()
-	if abm.isClosed {
-		abm.mu.RUnlock()
-		return TimingInfo{}, ErrBufferEmpty
-	}
-	abm.mu.RUnlock()
-
-	select {
-	case timing := <-abm.timings:
-		return timing, nil
-	case <-time.After(timeout):
-		return TimingInfo{}, ErrBufferTimeout
+// GetFromBuffer 从帧队列获取下一段音频数据
+// 途经的时间信息和结束标记会被丢弃，播放器应通过 GetFrame 按帧消费队列
+func (abm *AudioBuffer) GetFromBuffer(timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		frame, err := abm.GetFrame(time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+		if frame.HasAudio() {
+			return frame.Data, nil
+		}
 	}
 }
 
 // ClearBuffer 清空缓冲区
+// 计数随每个取出的帧更新，与并发写入的帧保持一致
 func (abm *AudioBuffer) ClearBuffer() {
-	abm.mu.Lock()
-	defer abm.mu.Unlock()
+	for {
+		select {
+		case frame := <-abm.frames:
+			abm.dequeued(frame)
+		default:
+			return
+		}
+	}
+}
 
-	// 清空时间信息缓冲区
-	for len(abm.timings) > 0 {
-		<-abm.timings
+// RemoveFrames 从帧队列中移除满足 match 的帧，返回移除的帧数
+// 只能由唯一的生产者在两次写入之间调用：其余帧按原顺序放回，期间被播放器取走的帧不受影响
+func (abm *AudioBuffer) RemoveFrames(match func(Frame) bool) int {
+	var kept []Frame
+	removed := 0
+	for drained := false; !drained; {
+		select {
+		case frame := <-abm.frames:
+			abm.dequeued(frame)
+			if match(frame) {
+				removed++
+			} else {
+				kept = append(kept, frame)
+			}
+		default:
+			drained = true
+		}
 	}
 
-	abm.totalSamples = 0
+	// 队列只有调用方写入，放回的帧不超过取出的帧，不会阻塞
+	abm.mu.Lock()
+	defer abm.mu.Unlock()
+	for _, frame := range kept {
+		abm.frames <- frame
+		abm.accountLocked(frame, 1)
+	}
+	return removed
 }
 
 // GetBufferedSeconds 获取缓冲的音频时长（秒）
@@ -132,11 +226,6 @@ func (abm *AudioBuffer) GetBufferedSeconds() float64 {
 		return 0.0
 	}
 
-	bytesPerSecond := abm.config.GetBytesPerSecond()
-	if bytesPerSecond <= 0 {
-		return 0.0
-	}
-
 	return float64(abm.totalSamples) / float64(abm.config.SampleRate)
 }
 
@@ -150,28 +239,25 @@ func (abm *AudioBuffer) GetBufferedBytes() int64 {
 
 // GetBufferUsage 获取缓冲区使用率
 func (abm *AudioBuffer) GetBufferUsage() float64 {
-	abm.mu.RLock()
-	defer abm.mu.RUnlock()
-
-	// 由于直接使用ttsAudioChan，无法准确计算使用率
-	return 0.0
+	if abm.bufferSize <= 0 {
+		return 0.0
+	}
+	return float64(len(abm.frames)) / float64(abm.bufferSize)
 }
 
 // IsEmpty 检查缓冲区是否为空
 func (abm *AudioBuffer) IsEmpty() bool {
-	abm.mu.RLock()
-	defer abm.mu.RUnlock()
-
-	return len(abm.timings) == 0
+	return len(abm.frames) == 0
 }
 
 // IsFull 检查缓冲区是否已满
 func (abm *AudioBuffer) IsFull() bool {
-	abm.mu.RLock()
-	defer abm.mu.RUnlock()
+	return len(abm.frames) >= abm.bufferSize
+}
 
-	// 由于直接使用ttsAudioChan，无法准确判断是否已满
-	return false
+// Len 返回帧队列中的帧数
+func (abm *AudioBuffer) Len() int {
+	return len(abm.frames)
 }
 
 // GetStats 获取缓冲区统计信息
@@ -179,30 +265,35 @@ func (abm *AudioBuffer) GetStats() BufferStats {
 	abm.mu.RLock()
 	defer abm.mu.RUnlock()
 
+	bufferedSeconds := 0.0
+	if abm.config.SampleRate > 0 {
+		bufferedSeconds = float64(abm.totalSamples) / float64(abm.config.SampleRate)
+	}
+
 	return BufferStats{
 		TotalSamples:    abm.totalSamples,
-		BytesProcessed:  0,   // 已移除统计字段
-		ChunksProcessed: 0,   // 已移除统计字段
-		BufferUsage:     0.0, // 直接使用ttsAudioChan，无法准确计算
-		BufferedSeconds: float64(abm.totalSamples) / float64(abm.config.SampleRate),
-		AudioQueueSize:  0, // 直接使用ttsAudioChan，无中间队列
-		TimingQueueSize: len(abm.timings),
+		BytesProcessed:  abm.bytesProcessed,
+		ChunksProcessed: abm.chunksProcessed,
+		BufferUsage:     abm.GetBufferUsage(),
+		BufferedSeconds: bufferedSeconds,
+		AudioQueueSize:  len(abm.frames),
+		TimingQueueSize: abm.queuedTimings,
 	}
 }
 
-// Start 启动音频缓冲管理器（已简化，不再需要单独的协程）
+// Start 启动音频缓冲管理器（队列由生产者和播放器直接驱动，无需单独的协程）
 func (abm *AudioBuffer) Start() {
-	// 直接使用ttsAudioChan，无需额外的处理协程
 }
 
 // Close 关闭缓冲区管理器
+// 关闭后写入返回 ErrBufferClosed，阻塞中的读写会立即返回
 func (abm *AudioBuffer) Close() {
 	abm.mu.Lock()
 	defer abm.mu.Unlock()
 
 	if !abm.isClosed {
 		abm.isClosed = true
-		close(abm.timings)
+		close(abm.closed)
 	}
 }
 
@@ -0,0 +1,102 @@
+package realtimetts_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	realtimetts "realtimetts/pkg"
+)
+
+func TestAudioBufferCountsStayConsistentWithConcurrentClear(t *testing.T) {
+	buffer := realtimetts.NewAudioBuffer(realtimetts.DefaultAudioConfig(), 4)
+	defer buffer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			if err := buffer.AddToBuffer(ctx, make([]byte, 64)); err != nil {
+				t.Errorf("写入失败: %v", err)
+				return
+			}
+			if err := buffer.AddTimingInfo(ctx, realtimetts.TimingInfo{Word: "词"}); err != nil {
+				t.Errorf("写入失败: %v", err)
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		buffer.ClearBuffer()
+		if stats := buffer.GetStats(); stats.TotalSamples < 0 || stats.TimingQueueSize < 0 {
+			t.Fatalf("计数不应为负: %+v", stats)
+		}
+	}
+
+	buffer.ClearBuffer()
+	if stats := buffer.GetStats(); stats.TotalSamples != 0 || stats.TimingQueueSize != 0 {
+		t.Fatalf("清空后计数应为零: %+v", stats)
+	}
+}
+
+func TestAudioBufferMarkEndOfUtteranceCarriesID(t *testing.T) {
+	buffer := realtimetts.NewAudioBuffer(realtimetts.DefaultAudioConfig(), 4)
+	defer buffer.Close()
+
+	if err := buffer.MarkEndOfUtterance(context.Background(), 42); err != nil {
+		t.Fatal(err)
+	}
+	frame, err := buffer.GetFrame(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !frame.Has(realtimetts.FrameEndOfUtterance) || frame.UtteranceID != 42 {
+		t.Fatalf("结束标记应携带话语 ID: %+v", frame)
+	}
+}
+
+func TestAudioBufferRemoveFramesKeepsOrderOfOthers(t *testing.T) {
+	buffer := realtimetts.NewAudioBuffer(realtimetts.DefaultAudioConfig(), 8)
+	defer buffer.Close()
+
+	ctx := context.Background()
+	for i, sentenceID := range []int{1, 2, 1, 3} {
+		frame := realtimetts.Frame{Data: make([]byte, 64), SentenceID: sentenceID, PTS: time.Duration(i)}
+		if err := buffer.PutFrame(ctx, frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed := buffer.RemoveFrames(func(frame realtimetts.Frame) bool { return frame.SentenceID == 1 })
+	if removed != 2 {
+		t.Fatalf("应移除 2 帧，实际 %d", removed)
+	}
+	for _, want := range []int{2, 3} {
+		frame, err := buffer.GetFrame(time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if frame.SentenceID != want {
+			t.Fatalf("剩余帧顺序错误: 期望句子 %d，实际 %d", want, frame.SentenceID)
+		}
+	}
+	if stats := buffer.GetStats(); stats.TotalSamples != 0 {
+		t.Fatalf("取空后计数应为零: %+v", stats)
+	}
+}
@@ -2,6 +2,7 @@ package realtimetts
 
 import (
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -25,7 +26,10 @@ type AudioStream struct {
 
 	// 音频数据缓冲区
 	audioBuffer chan []float32
+	pending     []float32 // 上次回调未输出完的数据，仅在回调中访问
 	bufferSize  int
+
+	logger *slog.Logger // 诊断日志，默认不输出
 }
 
 // DeviceInfo 设备信息结构体
@@ -50,9 +54,17 @@ func NewAudioStream(config *AudioConfiguration) *AudioStream {
 		lastError:        nil,
 		audioBuffer:      make(chan []float32, 100), // 100个音频块的缓冲区
 		bufferSize:       100,
+		logger:           nopLogger,
 	}
 }
 
+// SetLogger 设置诊断日志记录器，传入 nil 表示不输出
+func (as *AudioStream) SetLogger(logger *slog.Logger) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.logger = RedactingLogger(logger)
+}
+
 // OpenStream 打开音频流
 func (as *AudioStream) OpenStream() error {
 	as.mu.Lock()
@@ -91,7 +103,7 @@ func (as *AudioStream) OpenStream() error {
 
 	// 选择最佳采样率
 	as.actualSampleRate = as.selectBestSampleRate(defaultDevice, as.config.SampleRate)
-	fmt.Printf("   实际使用采样率: %d Hz\n", as.actualSampleRate)
+	as.logger.Info("打开音频设备", "device", defaultDevice.Name, "sample_rate", as.actualSampleRate, "requested_sample_rate", as.config.SampleRate)
 
 	// 创建音频流参数
 	streamParams := portaudio.StreamParameters{
@@ -120,15 +132,12 @@ func (as *AudioStream) OpenStream() error {
 // selectBestSampleRate 选择最佳采样率
 func (as *AudioStream) selectBestSampleRate(device *portaudio.DeviceInfo, desiredRate int) int {
 	// 优先使用指定的采样率
-	fmt.Printf("   设备默认采样率: %.0f Hz\n", device.DefaultSampleRate)
-	fmt.Printf("   期望采样率: %d Hz\n", desiredRate)
 
 	// 检查设备是否支持指定采样率
 	// 这里我们假设设备支持常见的采样率
 	supportedRates := []int{8000, 11025, 16000, 22050, 44100, 48000}
 	for _, rate := range supportedRates {
 		if rate == desiredRate {
-			fmt.Printf("   使用指定采样率: %d Hz\n", desiredRate)
 			return desiredRate
 		}
 	}
@@ -145,7 +154,8 @@ func (as *AudioStream) selectBestSampleRate(device *portaudio.DeviceInfo, desire
 		}
 	}
 
-	fmt.Printf("   指定采样率不支持，使用最接近的采样率: %d Hz\n", closestRate)
+	as.logger.Warn("设备不支持指定采样率，使用最接近的采样率",
+		"requested_sample_rate", desiredRate, "device_sample_rate", device.DefaultSampleRate, "sample_rate", closestRate)
 	return closestRate
 }
 
@@ -158,26 +168,29 @@ func abs(x int) int {
 }
 
 // audioCallback PortAudio 音频回调函数
+// 写入的音频块与回调缓冲区大小无关，未输出完的部分留到下一次回调
 func (as *AudioStream) audioCallback(out []float32, info portaudio.StreamCallbackTimeInfo, flags portaudio.StreamCallbackFlags) {
-	// 从音频缓冲区获取数据
-	select {
-	case audioData := <-as.audioBuffer:
-		// 复制音频数据到输出缓冲区
-		copyLen := len(audioData)
-		if copyLen > len(out) {
-			copyLen = len(out)
+	filled := 0
+	for filled < len(out) {
+		if len(as.pending) == 0 {
+			select {
+			case audioData := <-as.audioBuffer:
+				as.pending = audioData
+				continue
+			default:
+			}
+			break
 		}
-		copy(out[:copyLen], audioData[:copyLen])
 
-		// 如果输出缓冲区还有剩余空间，填充静音
-		for i := copyLen; i < len(out); i++ {
-			out[i] = 0.0
-		}
-	default:
-		// 如果没有音频数据，输出静音
-		for i := range out {
-			out[i] = 0.0
-		}
+		// 复制音频数据到输出缓冲区
+		n := copy(out[filled:], as.pending)
+		as.pending = as.pending[n:]
+		filled += n
+	}
+
+	// 如果没有足够的音频数据，剩余部分输出静音
+	for i := filled; i < len(out); i++ {
+		out[i] = 0.0
 	}
 }
 
@@ -274,6 +287,7 @@ func (as *AudioStream) WriteAudioData(data []byte) error {
 		as.mu.RUnlock()
 		return ErrStreamNotActive
 	}
+	logger := as.logger
 	as.mu.RUnlock()
 
 	// 将字节数据转换为float32格式
@@ -289,7 +303,7 @@ func (as *AudioStream) WriteAudioData(data []byte) error {
 		case as.audioBuffer <- audioData:
 			return nil
 		case <-time.After(100 * time.Millisecond): // 等待100ms
-			fmt.Printf("   ⚠️  音频缓冲区等待超时，丢弃数据\n")
+			logger.Warn("音频缓冲区等待超时，丢弃数据", LogKeyBytes, len(data))
 			return ErrBufferFull
 		}
 	}
@@ -2,6 +2,7 @@ package realtimetts
 
 import (
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -105,6 +106,9 @@ type BaseEngine struct {
 
 	// 音频时长
 	audioDuration time.Duration
+
+	// 诊断日志，默认不输出
+	logger *slog.Logger
 }
 
 // NewBaseEngine 创建新的基础引擎
@@ -190,6 +194,21 @@ func (be *BaseEngine) SetAudioBuffer(audioBuffer *AudioBuffer) {
 	be.audioBuffer = audioBuffer
 }
 
+// SetLogger 设置诊断日志记录器，传入 nil 表示不输出
+// 由 TextToAudioStream 注入时已带有 engine 字段
+func (be *BaseEngine) SetLogger(logger *slog.Logger) {
+	be.mu.Lock()
+	defer be.mu.Unlock()
+	be.logger = RedactingLogger(logger)
+}
+
+// Logger 返回诊断日志记录器，未设置时返回不输出的记录器
+func (be *BaseEngine) Logger() *slog.Logger {
+	be.mu.RLock()
+	defer be.mu.RUnlock()
+	return RedactingLogger(be.logger)
+}
+
 // StopSynthesis 停止合成
 func (be *BaseEngine) StopSynthesis() {
 	close(be.stopSynthesisChan)
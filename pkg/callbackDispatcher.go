@@ -0,0 +1,207 @@
+package realtimetts
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// callbackDispatcher 在专用协程中按事件发布的顺序调用回调
+// 回调执行缓慢时事件在队列中积压而不阻塞合成和播放协程，队列满时按溢出策略丢弃；
+// 设置了时间预算时，每个回调开始前重置同一个计时器，超时由计时器报告
+type callbackDispatcher struct {
+	mu       sync.Mutex
+	queue    []Event
+	size     int
+	overflow OverflowPolicy
+	dropped  uint64 // 因队列满丢弃的事件数，原子访问
+	closed   bool
+	wake     chan struct{}
+	handle   func(Event)
+
+	// 时间预算检查
+	budget     time.Duration
+	watchdog   *time.Timer
+	inflight   Event     // 正在执行的回调对应的事件，由 mu 保护
+	started    time.Time // inflight 开始执行的时间
+	overBudget func(event Event, elapsed time.Duration, finished bool)
+}
+
+// newCallbackDispatcher 创建回调分发器并启动分发协程
+// budget 大于 0 时，回调执行超过 budget 调用 overBudget(finished=false)，超时的回调结束时再调用一次
+func newCallbackDispatcher(config *EventConfig, budget time.Duration, handle func(Event), overBudget func(Event, time.Duration, bool)) *callbackDispatcher {
+	if config == nil {
+		config = DefaultCallbackQueueConfig()
+	}
+	size := config.BufferSize
+	if size <= 0 {
+		size = DefaultCallbackQueueConfig().BufferSize
+	}
+	d := &callbackDispatcher{
+		size:       size,
+		overflow:   config.Overflow,
+		wake:       make(chan struct{}, 1),
+		handle:     handle,
+		budget:     budget,
+		overBudget: overBudget,
+	}
+	if budget > 0 {
+		d.watchdog = time.AfterFunc(budget, d.expire)
+		d.watchdog.Stop()
+	}
+	go d.run()
+	return d
+}
+
+// DefaultCallbackQueueConfig 返回默认回调队列配置
+func DefaultCallbackQueueConfig() *EventConfig {
+	return &EventConfig{
+		BufferSize: 1024,
+		Overflow:   DropOldest,
+	}
+}
+
+// enqueue 把事件加入队列，队列满时按溢出策略丢弃，关闭后的事件被忽略
+func (d *callbackDispatcher) enqueue(event Event) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	switch {
+	case len(d.queue) < d.size:
+		d.queue = append(d.queue, event)
+	case d.overflow == DropOldest:
+		atomic.AddUint64(&d.dropped, 1)
+		d.queue = append(d.queue[1:], event)
+	default:
+		atomic.AddUint64(&d.dropped, 1)
+	}
+	d.mu.Unlock()
+	d.signal()
+}
+
+// signal 唤醒分发协程
+func (d *callbackDispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 按顺序处理队列中的事件，关闭后处理完剩余事件再退出
+func (d *callbackDispatcher) run() {
+	for {
+		d.mu.Lock()
+		for len(d.queue) == 0 && !d.closed {
+			d.mu.Unlock()
+			<-d.wake
+			d.mu.Lock()
+		}
+		if len(d.queue) == 0 {
+			d.mu.Unlock()
+			return
+		}
+		batch := d.queue
+		d.queue = nil
+		d.mu.Unlock()
+
+		for _, event := range batch {
+			d.invoke(event)
+		}
+	}
+}
+
+// invoke 调用回调，设置了时间预算时由计时器监视执行时长
+func (d *callbackDispatcher) invoke(event Event) {
+	if d.watchdog == nil {
+		d.handle(event)
+		return
+	}
+
+	start := time.Now()
+	d.mu.Lock()
+	d.inflight, d.started = event, start
+	d.mu.Unlock()
+	d.watchdog.Reset(d.budget)
+
+	d.handle(event)
+
+	expired := !d.watchdog.Stop()
+	d.mu.Lock()
+	d.inflight = nil
+	d.mu.Unlock()
+	if expired {
+		d.overBudget(event, time.Since(start), true)
+	}
+}
+
+// expire 在计时器协程中报告超过时间预算的回调
+func (d *callbackDispatcher) expire() {
+	d.mu.Lock()
+	event, elapsed := d.inflight, time.Since(d.started)
+	d.mu.Unlock()
+	// 计时器触发时回调可能刚好结束，未超时的回调不报告
+	if event == nil || elapsed < d.budget {
+		return
+	}
+	d.overBudget(event, elapsed, false)
+}
+
+// droppedEvents 返回因队列满丢弃的事件数
+func (d *callbackDispatcher) droppedEvents() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// close 停止接收事件，已入队的事件仍会被处理
+// 回调中可能调用 Close，因此不等待分发协程退出
+func (d *callbackDispatcher) close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	d.signal()
+}
+
+// invokeCallbacks 在分发协程中调用事件对应的回调
+// 回调 panic 时记录日志并通过 OnError 报告（OnError 自身 panic 时只记录日志）
+func (tts *TextToAudioStream) invokeCallbacks(event Event) {
+	tts.mu.RLock()
+	callbacks := tts.callbacks
+	logger := tts.logger
+	tts.mu.RUnlock()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err := fmt.Errorf("%w: %T: %v", ErrCallbackPanic, event, r)
+		logger.Error("回调函数发生 panic", LogKeyError, err, "stack", string(debug.Stack()))
+		if _, isError := event.(ErrorEvent); !isError {
+			tts.emit(ErrorEvent{Err: err})
+		}
+	}()
+
+	callbacks.Handle(event)
+}
+
+// callbackOverBudget 报告执行超过 StreamConfig.CallbackTimeBudget 的回调
+// 超时时记录警告并通过 OnError 报告（OnError 自身超时只记录日志），超时的回调结束时记录实际耗时
+func (tts *TextToAudioStream) callbackOverBudget(event Event, elapsed time.Duration, finished bool) {
+	tts.mu.RLock()
+	logger := tts.logger
+	tts.mu.RUnlock()
+
+	eventType := fmt.Sprintf("%T", event)
+	if finished {
+		logger.Warn("超过时间预算的回调已完成", "event", eventType, "duration", elapsed)
+		return
+	}
+	budget := tts.config.CallbackTimeBudget
+	logger.Warn("回调执行超过时间预算", "event", eventType, "budget", budget)
+	if _, isError := event.(ErrorEvent); !isError {
+		tts.emit(ErrorEvent{Err: fmt.Errorf("%w: %s 超过 %v", ErrCallbackOverBudget, eventType, budget)})
+	}
+}
@@ -19,6 +19,7 @@ type Callbacks struct {
 	OnAudioStreamStart    func()                      // 音频流开始
 	OnAudioStreamStop     func()                      // 音频流结束
 	OnSentenceSynthesized func(string, time.Duration) // 句子合成完成
+	OnUtteranceComplete   func(UtteranceResult)       // 话语播放结束或失败，附各阶段时间点
 
 	// 播放控制回调
 	OnPlaybackStart    func()                             // 播放开始
@@ -28,11 +29,11 @@ type Callbacks struct {
 	OnPlaybackProgress func(time.Duration, time.Duration) // 播放进度
 
 	// 引擎状态回调
-	OnEngineReady          func(string)         // 引擎就绪
-	OnEngineError          func(string, error)  // 引擎错误
-	OnEngineSwitch         func(string, string) // 引擎切换
-	OnEngineSynthesisStart func(string)         // 引擎合成开始
-	OnEngineSynthesisStop  func(string)         // 引擎合成结束
+	OnEngineReady          func(string)                 // 引擎就绪
+	OnEngineError          func(string, error)          // 引擎错误
+	OnEngineSwitch         func(string, string, string) // 引擎切换（原引擎, 新引擎, 原因）
+	OnEngineSynthesisStart func(string)                 // 引擎合成开始
+	OnEngineSynthesisStop  func(string)                 // 引擎合成结束
 
 	// 系统状态回调
 	OnBufferFull     func()              // 缓冲区满
@@ -53,6 +54,7 @@ func NewCallbacks() *Callbacks {
 		OnAudioStreamStart:     nil,
 		OnAudioStreamStop:      nil,
 		OnSentenceSynthesized:  nil,
+		OnUtteranceComplete:    nil,
 		OnPlaybackStart:        nil,
 		OnPlaybackStop:         nil,
 		OnPlaybackPause:        nil,
@@ -117,7 +119,7 @@ func (c *Callbacks) SetPlaybackCallbacks(
 func (c *Callbacks) SetEngineCallbacks(
 	onEngineReady func(string),
 	onEngineError func(string, error),
-	onEngineSwitch func(string, string),
+	onEngineSwitch func(string, string, string),
 	onEngineSynthesisStart func(string),
 	onEngineSynthesisStop func(string),
 ) {
@@ -220,5 +222,79 @@ func (c *Callbacks) SafeCallWithArgs(callback interface{}, args ...interface{})
 				}
 			}
 		}
+	case func(UtteranceResult):
+		if cb != nil && len(args) > 0 {
+			if result, ok := args[0].(UtteranceResult); ok {
+				cb(result)
+			}
+		}
+	case func(string, string, string):
+		if cb != nil && len(args) > 2 {
+			if str1, ok := args[0].(string); ok {
+				if str2, ok := args[1].(string); ok {
+					if str3, ok := args[2].(string); ok {
+						cb(str1, str2, str3)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Handle 调用与事件对应的回调函数，未设置的回调被忽略
+// 可用于把 Events 的事件流转接到 Callbacks
+func (c *Callbacks) Handle(event Event) {
+	if c == nil {
+		return
+	}
+	switch e := event.(type) {
+	case CharacterEvent:
+		c.SafeCallWithArgs(c.OnCharacter, e.Char)
+	case WordEvent:
+		c.SafeCallWithArgs(c.OnWord, e.Word)
+	case SentenceEvent:
+		c.SafeCallWithArgs(c.OnSentence, e.Sentence)
+	case TextStreamStartEvent:
+		c.SafeCall(c.OnTextStreamStart)
+	case TextStreamStopEvent:
+		c.SafeCall(c.OnTextStreamStop)
+	case AudioChunkEvent:
+		c.SafeCallWithArgs(c.OnAudioChunk, e.Data)
+	case AudioStreamStartEvent:
+		c.SafeCall(c.OnAudioStreamStart)
+	case AudioStreamStopEvent:
+		c.SafeCall(c.OnAudioStreamStop)
+	case SentenceSynthesizedEvent:
+		c.SafeCallWithArgs(c.OnSentenceSynthesized, e.Sentence, e.Duration)
+	case UtteranceCompleteEvent:
+		c.SafeCallWithArgs(c.OnUtteranceComplete, e.Result)
+	case PlaybackStartEvent:
+		c.SafeCall(c.OnPlaybackStart)
+	case PlaybackStopEvent:
+		c.SafeCall(c.OnPlaybackStop)
+	case PlaybackPauseEvent:
+		c.SafeCall(c.OnPlaybackPause)
+	case PlaybackResumeEvent:
+		c.SafeCall(c.OnPlaybackResume)
+	case PlaybackProgressEvent:
+		c.SafeCallWithArgs(c.OnPlaybackProgress, e.Position, e.Total)
+	case EngineReadyEvent:
+		c.SafeCallWithArgs(c.OnEngineReady, e.Engine)
+	case EngineErrorEvent:
+		c.SafeCallWithArgs(c.OnEngineError, e.Engine, e.Err)
+	case EngineSwitchEvent:
+		c.SafeCallWithArgs(c.OnEngineSwitch, e.From, e.To, e.Reason)
+	case EngineSynthesisStartEvent:
+		c.SafeCallWithArgs(c.OnEngineSynthesisStart, e.Engine)
+	case EngineSynthesisStopEvent:
+		c.SafeCallWithArgs(c.OnEngineSynthesisStop, e.Engine)
+	case BufferFullEvent:
+		c.SafeCall(c.OnBufferFull)
+	case BufferEmptyEvent:
+		c.SafeCall(c.OnBufferEmpty)
+	case LatencyWarningEvent:
+		c.SafeCallWithArgs(c.OnLatencyWarning, e.Latency)
+	case ErrorEvent:
+		c.SafeCallWithArgs(c.OnError, e.Err)
 	}
 }
@@ -0,0 +1,53 @@
+package realtimetts
+
+import "time"
+
+// FrameChunker 将任意长度的 PCM 字节流切分为音频帧
+// 帧边界对齐到采样帧，每帧不超过 chunkSize 字节，PTS 按已输出音频的时长累加；
+// 适用于从子进程或 HTTP 响应体读取的、边界不确定的音频流
+type FrameChunker struct {
+	format    *AudioConfiguration
+	chunkSize int
+	pending   []byte
+	pts       time.Duration
+}
+
+// NewFrameChunker 创建帧切分器
+func NewFrameChunker(format *AudioConfiguration, chunkSize int) *FrameChunker {
+	if bytesPerFrame := format.GetBytesPerFrame(); bytesPerFrame > 0 {
+		chunkSize -= chunkSize % bytesPerFrame
+		if chunkSize <= 0 {
+			chunkSize = bytesPerFrame
+		}
+	}
+	return &FrameChunker{format: format, chunkSize: chunkSize}
+}
+
+// Push 追加数据并返回已对齐的完整帧，不足一个采样帧的尾部留到下次
+func (c *FrameChunker) Push(data []byte) []Frame {
+	c.pending = append(c.pending, data...)
+
+	aligned := len(c.pending)
+	if bytesPerFrame := c.format.GetBytesPerFrame(); bytesPerFrame > 0 {
+		aligned -= aligned % bytesPerFrame
+	}
+
+	var frames []Frame
+	for offset := 0; offset < aligned; offset += c.chunkSize {
+		end := offset + c.chunkSize
+		if end > aligned {
+			end = aligned
+		}
+		frame := NewAudioFrame(append([]byte(nil), c.pending[offset:end]...), c.format)
+		frame.PTS = c.pts
+		c.pts += frame.Duration()
+		frames = append(frames, frame)
+	}
+	c.pending = append(c.pending[:0], c.pending[aligned:]...)
+	return frames
+}
+
+// Pending 返回尚未输出的字节数
+func (c *FrameChunker) Pending() int {
+	return len(c.pending)
+}
@@ -0,0 +1,658 @@
+package realtimetts
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config 配置文件的完整内容
+//
+//	audio:    音频输出配置 (AudioConfiguration)
+//	stream:   流配置 (StreamConfig)，stream.audio_config 与 audio 指向同一配置
+//	engines:  引擎链，见 EngineSpec
+//	credentials: 凭据，键为 EngineSpec.Credentials 引用的名称
+//
+// 字段名为 json 标签或 Go 字段名的蛇形形式，例如 SampleRate 对应 sample_rate；
+// 时长可写作 "300ms"、"2s" 等字符串，数字表示秒
+type Config struct {
+	Audio       *AudioConfiguration          `json:"audio"`
+	Stream      *StreamConfig                `json:"stream"`
+	Engines     []EngineSpec                 `json:"engines"`
+	Credentials map[string]map[string]string `json:"credentials"`
+}
+
+// DefaultConfig 返回默认配置，各部分取自 DefaultAudioConfig 和 DefaultStreamConfig
+func DefaultConfig() *Config {
+	stream := DefaultStreamConfig()
+	return &Config{
+		Audio:       stream.AudioConfig,
+		Stream:      stream,
+		Credentials: make(map[string]map[string]string),
+	}
+}
+
+// EngineChain 返回配置中的引擎链，可传给 BuildEngines
+func (c *Config) EngineChain() EngineChainConfig {
+	return EngineChainConfig{Engines: c.Engines, Credentials: c.Credentials}
+}
+
+// FieldError 指明出错字段的配置错误
+type FieldError struct {
+	Field string // 字段路径，例如 engines[0].config.sample_rate
+	Err   error
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap 返回底层错误
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError 汇总的配置错误，包含所有出错的字段
+type ConfigError struct {
+	Errors []*FieldError
+}
+
+// Error 实现 error 接口
+func (e *ConfigError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		lines[i] = err.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidConfig, strings.Join(lines, "; "))
+}
+
+// Unwrap 返回 ErrInvalidConfig 和各字段错误，供 errors.Is/As 使用
+func (e *ConfigError) Unwrap() []error {
+	errs := []error{ErrInvalidConfig}
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// add 记录字段错误
+func (e *ConfigError) add(field string, err error) {
+	e.Errors = append(e.Errors, &FieldError{Field: field, Err: err})
+}
+
+// errOrNil 没有错误时返回 nil
+func (e *ConfigError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ConfigLoader 配置加载器
+// 读取 YAML 或 JSON 文件后应用环境变量覆盖，环境变量名为前缀加上大写的字段路径，
+// 以下划线连接，例如 REALTIMETTS_AUDIO_SAMPLE_RATE、REALTIMETTS_ENGINES_0_VOICE、
+// REALTIMETTS_CREDENTIALS_VOLC_ACCESS_TOKEN。列表元素和表项只能覆盖文件中已有的项
+type ConfigLoader struct {
+	EnvPrefix string                          // 环境变量前缀，为空时不读取环境变量
+	LookupEnv func(key string) (string, bool) // 为空时使用 os.LookupEnv
+}
+
+// NewConfigLoader 创建使用 REALTIMETTS 前缀的配置加载器
+func NewConfigLoader() *ConfigLoader {
+	return &ConfigLoader{EnvPrefix: "REALTIMETTS", LookupEnv: os.LookupEnv}
+}
+
+// LoadConfig 使用默认加载器读取配置文件，path 为空时只使用默认值和环境变量
+func LoadConfig(path string) (*Config, error) {
+	return NewConfigLoader().Load(path)
+}
+
+// Load 读取配置文件，应用环境变量覆盖并校验
+func (l *ConfigLoader) Load(path string) (*Config, error) {
+	var data []byte
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+		data = raw
+	}
+	return l.LoadBytes(data)
+}
+
+// LoadBytes 解析 YAML 或 JSON 格式的配置内容，应用环境变量覆盖并校验
+func (l *ConfigLoader) LoadBytes(data []byte) (*Config, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	config := DefaultConfig()
+	errs := &ConfigError{}
+	if doc != nil {
+		decodeConfigValue("", doc, reflect.ValueOf(config).Elem(), errs)
+	}
+	// 默认配置中 audio 与 stream.audio_config 指向同一结构体，两处的设置都会生效
+	if config.Stream.AudioConfig == nil {
+		config.Stream.AudioConfig = config.Audio
+	}
+
+	if l.EnvPrefix != "" {
+		lookup := l.LookupEnv
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		applyEnvOverrides(strings.ToUpper(l.EnvPrefix), "", reflect.ValueOf(config).Elem(), lookup, errs)
+	}
+	if len(errs.Errors) > 0 {
+		return nil, errs
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate 校验配置，返回包含所有出错字段的 ConfigError
+func (c *Config) Validate() error {
+	errs := &ConfigError{}
+
+	if c.Audio == nil {
+		errs.add("audio", fmt.Errorf("不能为空"))
+	} else {
+		validateAudioConfig("audio", c.Audio, errs)
+	}
+
+	if s := c.Stream; s == nil {
+		errs.add("stream", fmt.Errorf("不能为空"))
+	} else {
+		if s.BufferThresholdSeconds < 0 {
+			errs.add("stream.buffer_threshold_seconds", fmt.Errorf("不能为负数"))
+		}
+		if s.MinimumSentenceLength < 0 {
+			errs.add("stream.minimum_sentence_length", fmt.Errorf("不能为负数"))
+		}
+		if s.CommaSilenceDuration < 0 {
+			errs.add("stream.comma_silence_duration", fmt.Errorf("时长不能为负数"))
+		}
+		if s.SentenceSilenceDuration < 0 {
+			errs.add("stream.sentence_silence_duration", fmt.Errorf("时长不能为负数"))
+		}
+		if s.SentenceTimeout < 0 {
+			errs.add("stream.sentence_timeout", fmt.Errorf("时长不能为负数"))
+		}
+		if s.CallbackTimeBudget < 0 {
+			errs.add("stream.callback_time_budget", fmt.Errorf("时长不能为负数"))
+		}
+		if s.LatencyThresholds.TimeToFirstAudio < 0 {
+			errs.add("stream.latency_thresholds.time_to_first_audio", fmt.Errorf("时长不能为负数"))
+		}
+		if s.LatencyThresholds.FirstChunk < 0 {
+			errs.add("stream.latency_thresholds.first_chunk", fmt.Errorf("时长不能为负数"))
+		}
+		if s.LatencyThresholds.Sentence < 0 {
+			errs.add("stream.latency_thresholds.sentence", fmt.Errorf("时长不能为负数"))
+		}
+		if f := s.Failover; f != nil {
+			if f.HealthWindow <= 0 {
+				errs.add("stream.failover.health_window", fmt.Errorf("必须大于0"))
+			}
+			if f.MinHealthScore < 0 || f.MinHealthScore > 1 {
+				errs.add("stream.failover.min_health_score", fmt.Errorf("必须在0到1之间"))
+			}
+			if f.MaxAttemptsPerSentence <= 0 {
+				errs.add("stream.failover.max_attempts_per_sentence", fmt.Errorf("必须大于0"))
+			}
+			if f.Cooldown < 0 {
+				errs.add("stream.failover.cooldown", fmt.Errorf("时长不能为负数"))
+			}
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, name := range RegisteredEngines() {
+		registered[name] = true
+	}
+	for i, spec := range c.Engines {
+		field := fmt.Sprintf("engines[%d]", i)
+		switch {
+		case spec.Type == "":
+			errs.add(field+".type", fmt.Errorf("不能为空"))
+		case !registered[spec.Type]:
+			errs.add(field+".type", fmt.Errorf("%w: %q", ErrUnknownEngineType, spec.Type))
+		}
+		if spec.Credentials != "" {
+			if _, ok := c.Credentials[spec.Credentials]; !ok {
+				errs.add(field+".credentials", fmt.Errorf("%w: %q", ErrUnknownCredentials, spec.Credentials))
+			}
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// validateAudioConfig 按字段校验音频配置，规则与 AudioConfiguration.Validate 一致
+func validateAudioConfig(prefix string, c *AudioConfiguration, errs *ConfigError) {
+	if c.Channels <= 0 || c.Channels > 8 {
+		errs.add(prefix+".channels", ErrInvalidChannels)
+	}
+	if c.SampleRate <= 0 {
+		errs.add(prefix+".sample_rate", ErrInvalidSampleRate)
+	}
+	if c.BitsPerSample != 8 && c.BitsPerSample != 16 && c.BitsPerSample != 24 && c.BitsPerSample != 32 {
+		errs.add(prefix+".bits_per_sample", ErrInvalidBitsPerSample)
+	}
+	if c.Volume < 0.0 || c.Volume > 1.0 {
+		errs.add(prefix+".volume", ErrInvalidVolume)
+	}
+	if c.PlaybackSpeed <= 0.0 {
+		errs.add(prefix+".playback_speed", ErrInvalidPlaybackSpeed)
+	}
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// configFieldName 返回结构体字段在配置中的名称：json 标签，或字段名的蛇形形式
+func configFieldName(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+
+	runes := []rune(field.Name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// configurable 判断字段类型能否从配置设置
+func configurable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Ptr, reflect.Slice:
+		return configurable(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && configurable(t.Elem())
+	}
+	return true
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// decodeConfigValue 递归解码，错误记录到 errs
+func decodeConfigValue(path string, src interface{}, dst reflect.Value, errs *ConfigError) {
+	if src == nil {
+		return
+	}
+	fieldPath := path
+	if fieldPath == "" {
+		fieldPath = "."
+	}
+
+	switch dst.Type() {
+	case durationType:
+		d, err := parseConfigDuration(src)
+		if err != nil {
+			errs.add(fieldPath, err)
+			return
+		}
+		dst.SetInt(int64(d))
+		return
+	case rawMessageType:
+		raw, err := json.Marshal(src)
+		if err != nil {
+			errs.add(fieldPath, err)
+			return
+		}
+		dst.SetBytes(raw)
+		return
+	}
+	if dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType) && dst.Kind() != reflect.Struct {
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(fmt.Sprint(src))); err != nil {
+			errs.add(fieldPath, err)
+		}
+		return
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		decodeConfigValue(path, src, dst.Elem(), errs)
+
+	case reflect.Struct:
+		fields, ok := src.(map[string]interface{})
+		if !ok {
+			errs.add(fieldPath, fmt.Errorf("应为对象，实际为 %T", src))
+			return
+		}
+		index := make(map[string]int)
+		for i := 0; i < dst.NumField(); i++ {
+			field := dst.Type().Field(i)
+			if field.IsExported() && configurable(field.Type) {
+				index[configFieldName(field)] = i
+			}
+		}
+		for _, key := range sortedKeys(fields) {
+			i, ok := index[key]
+			if !ok {
+				errs.add(joinPath(path, key), ErrUnknownConfigField)
+				continue
+			}
+			decodeConfigValue(joinPath(path, key), fields[key], dst.Field(i), errs)
+		}
+
+	case reflect.Map:
+		entries, ok := src.(map[string]interface{})
+		if !ok {
+			errs.add(fieldPath, fmt.Errorf("应为对象，实际为 %T", src))
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		for _, key := range sortedKeys(entries) {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if existing := dst.MapIndex(reflect.ValueOf(key)); existing.IsValid() {
+				elem.Set(existing)
+			}
+			decodeConfigValue(joinPath(path, key), entries[key], elem, errs)
+			dst.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+
+	case reflect.Slice:
+		if s, ok := src.(string); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes([]byte(s))
+			return
+		}
+		items, ok := src.([]interface{})
+		if !ok {
+			errs.add(fieldPath, fmt.Errorf("应为列表，实际为 %T", src))
+			return
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			decodeConfigValue(fmt.Sprintf("%s[%d]", path, i), item, slice.Index(i), errs)
+		}
+		dst.Set(slice)
+
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+
+	default:
+		if err := setConfigScalar(dst, src); err != nil {
+			errs.add(fieldPath, err)
+		}
+	}
+}
+
+// setConfigScalar 设置字符串、布尔和数值字段，字符串形式的数值和布尔值会被解析
+func setConfigScalar(dst reflect.Value, src interface{}) error {
+	switch dst.Kind() {
+	case reflect.String:
+		switch src.(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("应为字符串，实际为 %T", src)
+		}
+		dst.SetString(fmt.Sprint(src))
+
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			dst.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("无效的布尔值 %q", v)
+			}
+			dst.SetBool(b)
+		default:
+			return fmt.Errorf("应为布尔值，实际为 %T", src)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := configNumber(src)
+		if err != nil {
+			return err
+		}
+		if f != float64(int64(f)) || dst.OverflowInt(int64(f)) {
+			return fmt.Errorf("应为整数，实际为 %v", src)
+		}
+		dst.SetInt(int64(f))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, err := configNumber(src)
+		if err != nil {
+			return err
+		}
+		if f < 0 || f != float64(uint64(f)) || dst.OverflowUint(uint64(f)) {
+			return fmt.Errorf("应为非负整数，实际为 %v", src)
+		}
+		dst.SetUint(uint64(f))
+
+	case reflect.Float32, reflect.Float64:
+		f, err := configNumber(src)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", dst.Type())
+	}
+	return nil
+}
+
+// configNumber 将通用值转换为数值
+func configNumber(src interface{}) (float64, error) {
+	switch v := src.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的数值 %q", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("应为数值，实际为 %T", src)
+}
+
+// parseConfigDuration 解析时长，字符串按 time.ParseDuration 解析，数字表示秒
+func parseConfigDuration(src interface{}) (time.Duration, error) {
+	if s, ok := src.(string); ok {
+		if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+			return d, nil
+		}
+	}
+	seconds, err := configNumber(src)
+	if err != nil {
+		return 0, fmt.Errorf("无效的时长 %v，应为 \"300ms\"、\"2s\" 等形式或秒数", src)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// sortedKeys 返回排序后的键，使错误顺序稳定
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// envName 将字段路径片段转换为环境变量名片段
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// applyEnvOverrides 遍历配置，对每个可设置的字段查找对应的环境变量
+func applyEnvOverrides(prefix, path string, v reflect.Value, lookup func(string) (string, bool), errs *ConfigError) {
+	if value, ok := lookup(prefix); ok {
+		if isConfigLeaf(v.Type()) {
+			decodeConfigValue(path+" ("+prefix+")", value, v, errs)
+			return
+		}
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			if _, isTree := v.Interface().(map[string]interface{}); !isTree {
+				v.Set(reflect.ValueOf(envValue(v.Interface(), value)))
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			applyEnvOverrides(prefix, path, v.Elem(), lookup, errs)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || !configurable(field.Type) {
+				continue
+			}
+			name := configFieldName(field)
+			applyEnvOverrides(prefix+"_"+envName(name), joinPath(path, name), v.Field(i), lookup, errs)
+		}
+
+	case reflect.Slice:
+		if v.Type() == rawMessageType {
+			applyEnvToRaw(prefix, path, v, lookup, errs)
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			applyEnvOverrides(fmt.Sprintf("%s_%d", prefix, i), fmt.Sprintf("%s[%d]", path, i), v.Index(i), lookup, errs)
+		}
+
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			applyEnvOverrides(prefix+"_"+envName(key.String()), joinPath(path, key.String()), elem, lookup, errs)
+			v.SetMapIndex(key, elem)
+		}
+
+	case reflect.Interface:
+		if !v.IsNil() {
+			if tree, ok := v.Interface().(map[string]interface{}); ok {
+				applyEnvToTree(prefix, tree, lookup)
+			}
+		}
+	}
+}
+
+// isConfigLeaf 判断类型是否为可直接由单个环境变量设置的字段
+func isConfigLeaf(t reflect.Type) bool {
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Ptr, reflect.Interface:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 && t != rawMessageType
+	}
+	return true
+}
+
+// applyEnvToRaw 对引擎特定配置中已有的键应用环境变量覆盖
+func applyEnvToRaw(prefix, path string, v reflect.Value, lookup func(string) (string, bool), errs *ConfigError) {
+	if v.Len() == 0 {
+		return
+	}
+	var tree interface{}
+	if err := json.Unmarshal(v.Bytes(), &tree); err != nil {
+		return
+	}
+	m, ok := tree.(map[string]interface{})
+	if !ok || !applyEnvToTree(prefix, m, lookup) {
+		return
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		errs.add(path, err)
+		return
+	}
+	v.SetBytes(raw)
+}
+
+// applyEnvToTree 对通用对象中已有的键应用环境变量覆盖
+// 返回是否有键被覆盖
+func applyEnvToTree(prefix string, tree map[string]interface{}, lookup func(string) (string, bool)) bool {
+	changed := false
+	for key, value := range tree {
+		name := prefix + "_" + envName(key)
+		if child, ok := value.(map[string]interface{}); ok {
+			changed = applyEnvToTree(name, child, lookup) || changed
+			continue
+		}
+		if override, ok := lookup(name); ok {
+			tree[key] = envValue(value, override)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// envValue 按原值的类型解释环境变量：原值为字符串时保持字符串，否则按 YAML 标量解析
+func envValue(original interface{}, value string) interface{} {
+	if _, isString := original.(string); isString {
+		return value
+	}
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+		return value
+	}
+	return parsed
+}
@@ -0,0 +1,161 @@
+package realtimetts_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// configTestEngine 记录收到的引擎特定配置
+type configTestEngine struct {
+	namedEngine
+	Latency time.Duration `json:"latency"`
+	Rate    int           `json:"rate"`
+}
+
+func init() {
+	realtimetts.RegisterEngine("config-test", func(spec realtimetts.EngineSpec, credentials realtimetts.CredentialProvider) (realtimetts.TTSEngine, error) {
+		engine := &configTestEngine{}
+		if credentials != nil {
+			values, err := credentials.Credentials(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			engine.name = values["token"]
+		}
+		return engine, realtimetts.DecodeEngineConfig(spec, engine)
+	})
+}
+
+func TestConfigLoaderYAMLWithEnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"TTS_AUDIO_SAMPLE_RATE":            "24000",
+		"TTS_STREAM_SENTENCE_TIMEOUT":      "5s",
+		"TTS_CREDENTIALS_MAIN_TOKEN":       "from-env",
+		"TTS_ENGINES_0_PARAMS_SPEED":       "1.5",
+		"TTS_ENGINES_0_CONFIG_LATENCY":     "250ms",
+		"TTS_ENGINES_0_VOICE":              "env-voice",
+		"TTS_STREAM_FAILOVER_PRIORITIES_A": "7",
+	}
+	loader := &realtimetts.ConfigLoader{EnvPrefix: "TTS", LookupEnv: func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}}
+
+	config, err := loader.LoadBytes([]byte(`
+audio:
+  format: wav
+  channels: 2
+  volume: 0.5
+stream:
+  buffer_threshold_seconds: 1
+  comma_silence_duration: 50ms
+  sentence_silence_duration: 0.5
+  failover:
+    cooldown: 1m
+    priorities: {a: 1}
+credentials:
+  main: {token: from-file}
+engines:
+  - type: config-test
+    credentials: main
+    voice: file-voice
+    params: {speed: 1.0, style: calm}
+    config: {latency: 100ms, rate: 8000}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio := config.Audio
+	if audio != config.Stream.AudioConfig || audio.SampleRate != 24000 || audio.Channels != 2 || audio.Volume != 0.5 || audio.BitsPerSample != 16 {
+		t.Fatalf("音频配置不正确: %+v", audio)
+	}
+	stream := config.Stream
+	if stream.BufferThresholdSeconds != 1 || stream.CommaSilenceDuration != 50*time.Millisecond ||
+		stream.SentenceSilenceDuration != 500*time.Millisecond || stream.SentenceTimeout != 5*time.Second ||
+		stream.MinimumSentenceLength != 10 {
+		t.Fatalf("流配置不正确: %+v", stream)
+	}
+	if stream.Failover.Cooldown != time.Minute || stream.Failover.Priorities["a"] != 7 || stream.Failover.HealthWindow != 20 {
+		t.Fatalf("故障切换配置不正确: %+v", stream.Failover)
+	}
+
+	spec := config.Engines[0]
+	if spec.Voice != "env-voice" || spec.Params["speed"] != 1.5 || spec.Params["style"] != "calm" {
+		t.Fatalf("引擎链配置不正确: %+v", spec)
+	}
+
+	engines, err := realtimetts.BuildEngines(config.EngineChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := engines[0].(*configTestEngine)
+	if engine.name != "from-env" || engine.Latency != 250*time.Millisecond || engine.Rate != 8000 {
+		t.Fatalf("引擎特定配置不正确: %+v", engine)
+	}
+}
+
+func TestConfigLoaderJSONAndDefaults(t *testing.T) {
+	loader := &realtimetts.ConfigLoader{}
+	config, err := loader.LoadBytes([]byte(`{"stream": {"language": "zh", "audio_config": {"sample_rate": 22050}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Stream.Language != "zh" || config.Audio.SampleRate != 22050 || config.Stream.SentenceTimeout != 20*time.Second {
+		t.Fatalf("JSON 配置或默认值不正确: %+v %+v", config.Stream, config.Audio)
+	}
+
+	config, err = loader.LoadBytes(nil)
+	if err != nil || config.Audio.SampleRate != realtimetts.DefaultAudioConfig().SampleRate {
+		t.Fatalf("空配置应使用默认值: %v", err)
+	}
+}
+
+func TestConfigLoaderAggregatesFieldErrors(t *testing.T) {
+	_, err := (&realtimetts.ConfigLoader{}).LoadBytes([]byte(`
+audio: {channels: 0, smaple_rate: 8000}
+stream: {sentence_timeout: soon}
+engines:
+  - type: config-test
+    credentials: missing
+  - type: nope
+`))
+	var configErr *realtimetts.ConfigError
+	if !errors.As(err, &configErr) || !errors.Is(err, realtimetts.ErrInvalidConfig) {
+		t.Fatalf("应返回 ConfigError: %v", err)
+	}
+	fields := map[string]bool{}
+	for _, fieldErr := range configErr.Errors {
+		fields[fieldErr.Field] = true
+	}
+	// 解码错误先于校验报告
+	if !fields["audio.smaple_rate"] || !fields["stream.sentence_timeout"] || !errors.Is(err, realtimetts.ErrUnknownConfigField) {
+		t.Fatalf("应指出解码出错的字段: %v", err)
+	}
+
+	_, err = (&realtimetts.ConfigLoader{}).LoadBytes([]byte(`
+audio: {channels: 0, volume: 2}
+engines:
+  - type: config-test
+    credentials: missing
+  - type: nope
+`))
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 4 {
+		t.Fatalf("应汇总所有校验错误: %v", err)
+	}
+	for _, want := range []error{realtimetts.ErrInvalidChannels, realtimetts.ErrInvalidVolume, realtimetts.ErrUnknownCredentials, realtimetts.ErrUnknownEngineType} {
+		if !errors.Is(err, want) {
+			t.Errorf("缺少错误 %v: %v", want, err)
+		}
+	}
+
+	spec := realtimetts.EngineSpec{Type: "config-test", Config: json.RawMessage(`{"latency": "later"}`)}
+	if _, err := realtimetts.NewEngine(spec, nil); !errors.Is(err, realtimetts.ErrInvalidEngineConfig) {
+		t.Fatalf("引擎特定配置的错误应指明: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package realtimetts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CredentialProvider 凭据来源
+// 引擎在每次请求前获取凭据，以便凭据轮换后无需重建引擎；返回的值会自动登记为需要脱敏的机密
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (map[string]string, error)
+}
+
+// CredentialInvalidator 可在认证失败后丢弃缓存凭据的来源
+type CredentialInvalidator interface {
+	Invalidate()
+}
+
+// InvalidateOnAuthError 在 err 为认证失败时让 provider 丢弃缓存的凭据，下次请求重新获取
+func InvalidateOnAuthError(provider CredentialProvider, err error) {
+	if invalidator, ok := provider.(CredentialInvalidator); ok && errors.Is(err, ErrEngineAuth) {
+		invalidator.Invalidate()
+	}
+}
+
+// staticCredentials 固定凭据
+type staticCredentials map[string]string
+
+// StaticCredentials 返回固定凭据的来源
+func StaticCredentials(values map[string]string) CredentialProvider {
+	copied := make(staticCredentials, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	RegisterSecrets(values)
+	return copied
+}
+
+// Credentials 实现 CredentialProvider
+func (s staticCredentials) Credentials(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string, len(s))
+	for k, v := range s {
+		values[k] = v
+	}
+	return values, nil
+}
+
+// envCredentials 从环境变量读取的凭据
+type envCredentials map[string]string
+
+// EnvCredentials 返回从环境变量读取凭据的来源，vars 的键为凭据名，值为环境变量名
+// 每次获取时重新读取，缺少任一环境变量时返回错误
+func EnvCredentials(vars map[string]string) CredentialProvider {
+	copied := make(envCredentials, len(vars))
+	for k, v := range vars {
+		copied[k] = v
+	}
+	return copied
+}
+
+// Credentials 实现 CredentialProvider
+func (e envCredentials) Credentials(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string, len(e))
+	var missing []string
+	for key, name := range e {
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			missing = append(missing, name)
+			continue
+		}
+		values[key] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: 缺少环境变量 %s", ErrCredentialsUnavailable, strings.Join(missing, ", "))
+	}
+	RegisterSecrets(values)
+	return values, nil
+}
+
+// FileCredentials 从 YAML 或 JSON 文件读取的凭据，文件修改后自动重新读取
+type FileCredentials struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	values  map[string]string
+}
+
+// NewFileCredentials 创建从文件读取凭据的来源，文件内容为凭据名到值的映射
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Credentials 实现 CredentialProvider
+func (f *FileCredentials) Credentials(ctx context.Context) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCredentialsUnavailable, err)
+	}
+	if f.values == nil || !info.ModTime().Equal(f.modTime) {
+		raw, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCredentialsUnavailable, err)
+		}
+		var values map[string]string
+		if err := yaml.Unmarshal(raw, &values); err != nil {
+			// 错误信息可能引用文件内容，不向上传递原始错误
+			return nil, fmt.Errorf("%w: 凭据文件 %s 格式无效", ErrCredentialsUnavailable, f.path)
+		}
+		RegisterSecrets(values)
+		f.values, f.modTime = values, info.ModTime()
+	}
+
+	values := make(map[string]string, len(f.values))
+	for k, v := range f.values {
+		values[k] = v
+	}
+	return values, nil
+}
+
+// Invalidate 实现 CredentialInvalidator，下次获取时重新读取文件
+func (f *FileCredentials) Invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values = nil
+}
+
+// CredentialFetchFunc 获取凭据的回调，返回凭据及其过期时间，零值表示不过期
+type CredentialFetchFunc func(ctx context.Context) (map[string]string, time.Time, error)
+
+// RefreshingCredentials 通过回调获取并缓存凭据，在过期前 RefreshBefore 重新获取
+// 适用于 STS 临时凭据、OAuth 令牌等有有效期的凭据
+type RefreshingCredentials struct {
+	fetch         CredentialFetchFunc
+	refreshBefore time.Duration
+
+	mu      sync.Mutex
+	values  map[string]string
+	expires time.Time
+}
+
+// NewRefreshingCredentials 创建带刷新的回调凭据来源
+func NewRefreshingCredentials(fetch CredentialFetchFunc, refreshBefore time.Duration) *RefreshingCredentials {
+	return &RefreshingCredentials{fetch: fetch, refreshBefore: refreshBefore}
+}
+
+// Credentials 实现 CredentialProvider，并发请求共用同一次刷新
+func (r *RefreshingCredentials) Credentials(ctx context.Context) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stale := r.values == nil || (!r.expires.IsZero() && time.Now().Add(r.refreshBefore).After(r.expires))
+	if stale {
+		values, expires, err := r.fetch(ctx)
+		if err != nil {
+			// 刷新失败时继续使用尚未过期的旧凭据
+			if r.values != nil && (r.expires.IsZero() || time.Now().Before(r.expires)) {
+				return copyCredentials(r.values), nil
+			}
+			return nil, fmt.Errorf("%w: %w", ErrCredentialsUnavailable, err)
+		}
+		RegisterSecrets(values)
+		r.values, r.expires = copyCredentials(values), expires
+	}
+	return copyCredentials(r.values), nil
+}
+
+// Invalidate 实现 CredentialInvalidator，下次获取时调用回调
+func (r *RefreshingCredentials) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = nil
+}
+
+// copyCredentials 复制凭据，避免调用方修改缓存
+func copyCredentials(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
+
+// referenceCredentials 值可引用环境变量或文件的凭据
+type referenceCredentials map[string]string
+
+// ReferenceCredentials 返回解析引用的凭据来源，用于配置文件中的凭据：
+// "env:NAME" 读取环境变量，"file:/path" 读取文件内容（去除首尾空白），其余按字面值使用。
+// 引用在每次获取时解析，因此轮换后的环境变量或挂载的机密文件无需重启即可生效
+func ReferenceCredentials(values map[string]string) CredentialProvider {
+	copied := make(referenceCredentials, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
+
+// Credentials 实现 CredentialProvider
+func (r referenceCredentials) Credentials(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string, len(r))
+	for key, ref := range r {
+		switch {
+		case strings.HasPrefix(ref, "env:"):
+			name := strings.TrimPrefix(ref, "env:")
+			value, ok := os.LookupEnv(name)
+			if !ok || value == "" {
+				return nil, fmt.Errorf("%w: %s: 缺少环境变量 %s", ErrCredentialsUnavailable, key, name)
+			}
+			values[key] = value
+		case strings.HasPrefix(ref, "file:"):
+			raw, err := os.ReadFile(strings.TrimPrefix(ref, "file:"))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrCredentialsUnavailable, key, err)
+			}
+			values[key] = strings.TrimSpace(string(raw))
+		default:
+			values[key] = ref
+		}
+	}
+	RegisterSecrets(values)
+	return values, nil
+}
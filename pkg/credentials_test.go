@@ -0,0 +1,155 @@
+package realtimetts_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	realtimetts "realtimetts/pkg"
+)
+
+func TestRefreshingCredentials(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fail := false
+	expires := time.Now().Add(time.Hour)
+	provider := realtimetts.NewRefreshingCredentials(func(ctx context.Context) (map[string]string, time.Time, error) {
+		calls++
+		if fail {
+			return nil, time.Time{}, errors.New("sts down")
+		}
+		return map[string]string{"token": "sts-token-" + string(rune('0'+calls))}, expires, nil
+	}, time.Minute)
+
+	values, err := provider.Credentials(ctx)
+	if err != nil || values["token"] != "sts-token-1" {
+		t.Fatalf("首次获取: %v %v", values, err)
+	}
+	// 未到刷新时间时使用缓存
+	if values, _ = provider.Credentials(ctx); values["token"] != "sts-token-1" || calls != 1 {
+		t.Fatalf("应使用缓存, calls=%d values=%v", calls, values)
+	}
+
+	// 进入刷新窗口后重新获取
+	expires = time.Now().Add(30 * time.Second)
+	provider.Invalidate()
+	if values, _ = provider.Credentials(ctx); values["token"] != "sts-token-2" {
+		t.Fatalf("Invalidate 后应重新获取: %v", values)
+	}
+	if values, _ = provider.Credentials(ctx); values["token"] != "sts-token-3" {
+		t.Fatalf("刷新窗口内应重新获取: %v", values)
+	}
+
+	// 刷新失败时继续使用尚未过期的旧凭据
+	fail = true
+	if values, err = provider.Credentials(ctx); err != nil || values["token"] != "sts-token-3" {
+		t.Fatalf("刷新失败应回退旧凭据: %v %v", values, err)
+	}
+
+	// 认证失败让来源丢弃缓存，此后刷新失败即无可用凭据
+	realtimetts.InvalidateOnAuthError(provider, &realtimetts.EngineError{Kind: realtimetts.ErrEngineAuth})
+	if _, err = provider.Credentials(ctx); !errors.Is(err, realtimetts.ErrCredentialsUnavailable) {
+		t.Fatalf("期望 ErrCredentialsUnavailable, 实际 %v", err)
+	}
+}
+
+func TestFileCredentialsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	if err := os.WriteFile(path, []byte("token: file-token-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := realtimetts.NewFileCredentials(path)
+
+	values, err := provider.Credentials(context.Background())
+	if err != nil || values["token"] != "file-token-1" {
+		t.Fatalf("读取失败: %v %v", values, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"token": "file-token-2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// 保证修改时间变化
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ = provider.Credentials(context.Background()); values["token"] != "file-token-2" {
+		t.Fatalf("文件修改后应重新读取: %v", values)
+	}
+
+	if err := os.WriteFile(path, []byte("token: [file-token-3"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider.Invalidate()
+	_, err = provider.Credentials(context.Background())
+	if !errors.Is(err, realtimetts.ErrCredentialsUnavailable) || strings.Contains(err.Error(), "file-token-3") {
+		t.Fatalf("格式错误应返回不含内容的错误: %v", err)
+	}
+}
+
+func TestReferenceCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("mounted-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_REF_TOKEN", "env-secret")
+
+	provider := realtimetts.ReferenceCredentials(map[string]string{
+		"token":   "env:TEST_REF_TOKEN",
+		"secret":  "file:" + path,
+		"cluster": "volcano_tts",
+	})
+	values, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["token"] != "env-secret" || values["secret"] != "mounted-secret" || values["cluster"] != "volcano_tts" {
+		t.Fatalf("引用解析错误: %v", values)
+	}
+
+	missing := realtimetts.ReferenceCredentials(map[string]string{"token": "env:TEST_REF_MISSING"})
+	if _, err := missing.Credentials(context.Background()); !errors.Is(err, realtimetts.ErrCredentialsUnavailable) {
+		t.Fatalf("期望 ErrCredentialsUnavailable, 实际 %v", err)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	realtimetts.RegisterSecret("sk-redact-me-123")
+
+	if got := realtimetts.Redact("Bearer sk-redact-me-123 rejected"); got != "Bearer "+realtimetts.RedactedMask+" rejected" {
+		t.Fatalf("Redact: %q", got)
+	}
+
+	got := realtimetts.RedactJSON([]byte(`{"app":{"appid":"12345","token":"abc"},"request":{"text":"key sk-redact-me-123"}}`))
+	if strings.Contains(got, "12345") || strings.Contains(got, `"abc"`) || strings.Contains(got, "sk-redact-me-123") {
+		t.Fatalf("RedactJSON 未脱敏: %s", got)
+	}
+	if !strings.Contains(got, `"text":"key `+realtimetts.RedactedMask+`"`) {
+		t.Fatalf("RedactJSON 应保留非敏感字段: %s", got)
+	}
+
+	err := &realtimetts.EngineError{
+		Engine:  "openai",
+		Kind:    realtimetts.ErrEngineAuth,
+		Message: "invalid api key sk-redact-me-123",
+	}
+	if strings.Contains(err.Error(), "sk-redact-me-123") {
+		t.Fatalf("EngineError 未脱敏: %s", err)
+	}
+}
+
+func TestRegisterSecretsOnlySensitiveKeys(t *testing.T) {
+	realtimetts.RegisterSecrets(map[string]string{
+		"access_token": "volc-token-value",
+		"cluster":      "volcano_tts",
+	})
+
+	got := realtimetts.Redact("cluster volcano_tts rejected volc-token-value")
+	if got != "cluster volcano_tts rejected "+realtimetts.RedactedMask {
+		t.Fatalf("只应替换敏感字段的值: %q", got)
+	}
+}
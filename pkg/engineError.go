@@ -0,0 +1,104 @@
+package realtimetts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// EngineError 引擎返回的分类错误
+// Kind 为 ErrEngineAuth 等分类哨兵错误，可通过 errors.Is 判断；
+// Retryable 表示相同请求稍后重试是否可能成功
+type EngineError struct {
+	Engine     string // 引擎名称
+	Kind       error  // 错误分类
+	Code       int    // 服务商错误码
+	HTTPStatus int    // HTTP 状态码，非 HTTP 错误为 0
+	Message    string // 服务商返回的错误信息
+	Retryable  bool   // 是否可重试
+	Err        error  // 底层错误
+}
+
+// Error 实现 error 接口
+// 服务商返回的信息和底层错误可能回显凭据，输出前按已登记的机密脱敏
+func (e *EngineError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Engine, e.Kind)
+	if e.Code != 0 {
+		msg += fmt.Sprintf(" (code=%d)", e.Code)
+	}
+	if e.HTTPStatus != 0 {
+		msg += fmt.Sprintf(" (http=%d)", e.HTTPStatus)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return Redact(msg)
+}
+
+// Unwrap 返回分类和底层错误，供 errors.Is/As 使用
+func (e *EngineError) Unwrap() []error {
+	errs := []error{e.Kind}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// IsRetryable 判断错误是否值得重试
+// 上下文取消不重试；EngineError 以其 Retryable 为准；网络超时视为可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) {
+		return engineErr.Retryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// ClassifyHTTPStatus 将 HTTP 状态码映射为分类错误
+// 返回 nil 表示状态码为成功
+func ClassifyHTTPStatus(engine string, status int, message string) *EngineError {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+
+	engineErr := &EngineError{Engine: engine, HTTPStatus: status, Message: message}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		engineErr.Kind = ErrEngineAuth
+	case status == http.StatusTooManyRequests:
+		engineErr.Kind, engineErr.Retryable = ErrEngineQuota, true
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		engineErr.Kind, engineErr.Retryable = ErrEngineTimeout, true
+	case status == http.StatusServiceUnavailable || status == http.StatusBadGateway:
+		engineErr.Kind, engineErr.Retryable = ErrEngineBusy, true
+	case status >= 500:
+		engineErr.Kind, engineErr.Retryable = ErrEngineServer, true
+	default:
+		engineErr.Kind = ErrEngineInvalidInput
+	}
+	return engineErr
+}
+
+// ClassifyTransportError 将 HTTP 传输层错误包装为分类错误
+// 上下文取消原样返回，其余视为可重试的网络错误
+func ClassifyTransportError(engine string, err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return &EngineError{Engine: engine, Kind: ErrEngineNetwork, Retryable: true, Err: err}
+}
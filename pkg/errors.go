@@ -26,6 +26,7 @@ var (
 	ErrBufferEmpty   = errors.New("缓冲区为空")
 	ErrBufferFull    = errors.New("缓冲区已满")
 	ErrBufferTimeout = errors.New("缓冲区操作超时")
+	ErrBufferClosed  = errors.New("缓冲区已关闭")
 )
 
 // 播放器相关错误
@@ -34,6 +35,7 @@ var (
 	ErrPlayerAlreadyPlaying = errors.New("播放器正在播放")
 	ErrPlayerNotPlaying     = errors.New("播放器未在播放")
 	ErrPlayerPaused         = errors.New("播放器已暂停")
+	ErrStreamClosed         = errors.New("文本转音频流已关闭")
 )
 
 // 引擎相关错误
@@ -41,5 +43,41 @@ var (
 	ErrEngineNotInitialized  = errors.New("TTS引擎未初始化")
 	ErrEngineSynthesisFailed = errors.New("TTS引擎合成失败")
 	ErrNoEnginesAvailable    = errors.New("没有可用的TTS引擎")
+	ErrTextNotSupported      = errors.New("TTS引擎不支持该文本")
 	ErrInvalidPitch          = errors.New("无效的音调值")
 )
+
+// 分类错误相关错误
+var (
+	ErrEngineAuth         = errors.New("TTS引擎认证失败")
+	ErrEngineQuota        = errors.New("TTS引擎配额或并发超限")
+	ErrEngineInvalidInput = errors.New("TTS引擎拒绝了输入")
+	ErrEngineBusy         = errors.New("TTS引擎服务繁忙")
+	ErrEngineTimeout      = errors.New("TTS引擎处理超时")
+	ErrEngineNetwork      = errors.New("TTS引擎网络错误")
+	ErrEngineServer       = errors.New("TTS引擎服务端错误")
+)
+
+// 引擎注册相关错误
+var (
+	ErrUnknownEngineType   = errors.New("未注册的TTS引擎类型")
+	ErrUnknownCredentials  = errors.New("未定义的引擎凭据")
+	ErrInvalidEngineConfig = errors.New("无效的引擎配置")
+)
+
+// 凭据相关错误
+var (
+	ErrCredentialsUnavailable = errors.New("无法获取引擎凭据")
+)
+
+// 配置加载相关错误
+var (
+	ErrInvalidConfig      = errors.New("无效的配置")
+	ErrUnknownConfigField = errors.New("未知的配置字段")
+)
+
+// 回调相关错误
+var (
+	ErrCallbackPanic      = errors.New("回调函数发生 panic")
+	ErrCallbackOverBudget = errors.New("回调执行超过时间预算")
+)
@@ -0,0 +1,274 @@
+package realtimetts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event 事件流中的事件，具体类型为本包中名称以 Event 结尾的结构体之一
+// 与 Callbacks 的回调一一对应，可用类型分支处理：
+//
+//	switch e := event.(type) {
+//	case realtimetts.WordEvent:
+//	case realtimetts.EngineErrorEvent:
+//	}
+type Event interface {
+	isEvent()
+}
+
+// 文本处理事件
+type (
+	CharacterEvent struct{ Char rune }
+	// WordEvent 单词开始播放，时间相对所属话语音频的开始
+	WordEvent struct {
+		Word               string
+		StartTime, EndTime time.Duration
+	}
+	SentenceEvent        struct{ Sentence string } // 句子开始合成
+	TextStreamStartEvent struct{}
+	TextStreamStopEvent  struct{}
+)
+
+// 音频处理事件
+type (
+	// AudioChunkEvent 音频块已写入输出，Data 与其他订阅者共享，不能修改
+	AudioChunkEvent          struct{ Data []byte }
+	AudioStreamStartEvent    struct{ UtteranceID uint64 } // 话语的第一帧音频已合成
+	AudioStreamStopEvent     struct{ UtteranceID uint64 } // 话语播放结束
+	SentenceSynthesizedEvent struct {
+		Sentence string
+		Duration time.Duration // 合成耗时
+	}
+	UtteranceCompleteEvent struct{ Result UtteranceResult }
+)
+
+// 播放控制事件
+type (
+	PlaybackStartEvent    struct{}
+	PlaybackStopEvent     struct{}
+	PlaybackPauseEvent    struct{}
+	PlaybackResumeEvent   struct{}
+	PlaybackProgressEvent struct{ Position, Total time.Duration }
+)
+
+// 引擎状态事件
+type (
+	EngineReadyEvent struct{ Engine string }
+	EngineErrorEvent struct {
+		Engine string
+		Err    error
+	}
+	EngineSwitchEvent         struct{ From, To, Reason string }
+	EngineSynthesisStartEvent struct{ Engine string }
+	EngineSynthesisStopEvent  struct{ Engine string }
+)
+
+// 系统状态事件
+type (
+	BufferFullEvent     struct{}
+	BufferEmptyEvent    struct{}
+	LatencyWarningEvent struct {
+		UtteranceID uint64
+		Stage       string // LatencyStage* 之一
+		Latency     time.Duration
+		Threshold   time.Duration
+	}
+	ErrorEvent struct{ Err error }
+)
+
+func (CharacterEvent) isEvent()            {}
+func (WordEvent) isEvent()                 {}
+func (SentenceEvent) isEvent()             {}
+func (TextStreamStartEvent) isEvent()      {}
+func (TextStreamStopEvent) isEvent()       {}
+func (AudioChunkEvent) isEvent()           {}
+func (AudioStreamStartEvent) isEvent()     {}
+func (AudioStreamStopEvent) isEvent()      {}
+func (SentenceSynthesizedEvent) isEvent()  {}
+func (UtteranceCompleteEvent) isEvent()    {}
+func (PlaybackStartEvent) isEvent()        {}
+func (PlaybackStopEvent) isEvent()         {}
+func (PlaybackPauseEvent) isEvent()        {}
+func (PlaybackResumeEvent) isEvent()       {}
+func (PlaybackProgressEvent) isEvent()     {}
+func (EngineReadyEvent) isEvent()          {}
+func (EngineErrorEvent) isEvent()          {}
+func (EngineSwitchEvent) isEvent()         {}
+func (EngineSynthesisStartEvent) isEvent() {}
+func (EngineSynthesisStopEvent) isEvent()  {}
+func (BufferFullEvent) isEvent()           {}
+func (BufferEmptyEvent) isEvent()          {}
+func (LatencyWarningEvent) isEvent()       {}
+func (ErrorEvent) isEvent()                {}
+
+// OverflowPolicy 订阅者的通道缓冲满时的处理方式
+// 发送事件的是合成和播放协程，任何策略都不会阻塞发送方
+type OverflowPolicy int
+
+const (
+	DropOldest OverflowPolicy = iota // 丢弃最早未读取的事件，保留新事件
+	DropNewest                       // 丢弃新事件
+)
+
+// String 返回策略名，与 UnmarshalText 接受的值一致
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalText 从配置文件中的 drop_oldest / drop_newest 解析策略
+func (p *OverflowPolicy) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "drop_oldest":
+		*p = DropOldest
+	case "drop_newest":
+		*p = DropNewest
+	default:
+		return fmt.Errorf("未知的溢出策略 %q，可选 drop_oldest、drop_newest", text)
+	}
+	return nil
+}
+
+// EventConfig 事件订阅配置
+type EventConfig struct {
+	BufferSize int            // 通道缓冲的事件数
+	Overflow   OverflowPolicy // 缓冲满时的处理方式
+}
+
+// DefaultEventConfig 返回默认事件订阅配置
+func DefaultEventConfig() *EventConfig {
+	return &EventConfig{
+		BufferSize: 256,
+		Overflow:   DropOldest,
+	}
+}
+
+// eventSubscriber 一个事件订阅者
+type eventSubscriber struct {
+	ch       chan Event
+	overflow OverflowPolicy
+}
+
+// eventBus 向订阅者分发事件
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[*eventSubscriber]struct{}
+	dropped     uint64        // 因缓冲满丢弃的事件数，原子访问
+	closed      chan struct{} // 流关闭时关闭
+}
+
+// newEventBus 创建事件分发器
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[*eventSubscriber]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// subscribe 添加订阅者，ctx 结束或分发器关闭时移除并关闭通道
+func (b *eventBus) subscribe(ctx context.Context, config *EventConfig) <-chan Event {
+	size := config.BufferSize
+	if size <= 0 {
+		size = DefaultEventConfig().BufferSize
+	}
+	sub := &eventSubscriber{ch: make(chan Event, size), overflow: config.Overflow}
+
+	b.mu.Lock()
+	select {
+	case <-b.closed:
+		close(sub.ch)
+		b.mu.Unlock()
+		return sub.ch
+	default:
+	}
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-b.closed:
+		}
+		b.mu.Lock()
+		delete(b.subscribers, sub)
+		close(sub.ch)
+		b.mu.Unlock()
+	}()
+	return sub.ch
+}
+
+// publish 不阻塞地把事件发送给所有订阅者，缓冲满时按订阅者的策略丢弃
+func (b *eventBus) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+			continue
+		default:
+		}
+		atomic.AddUint64(&b.dropped, 1)
+		if sub.overflow != DropOldest {
+			continue
+		}
+		// 持有 b.mu 时只有本协程发送，取出一个后必然能放入
+		select {
+		case <-sub.ch:
+		default:
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+// close 关闭分发器，所有订阅通道随后被关闭
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+}
+
+// Events 订阅事件流，使用 StreamConfig.Events 的缓冲配置，流关闭后通道被关闭
+// 事件在发送时不阻塞合成和播放，读取不及时时按配置丢弃；Callbacks 中的回调经由独立的回调队列调用，不受订阅者丢弃的影响
+func (tts *TextToAudioStream) Events() <-chan Event {
+	return tts.Subscribe(context.Background(), nil)
+}
+
+// Subscribe 按指定配置订阅事件流，config 为 nil 时使用 StreamConfig.Events
+// ctx 结束或流关闭后通道被关闭
+func (tts *TextToAudioStream) Subscribe(ctx context.Context, config *EventConfig) <-chan Event {
+	if config == nil {
+		config = tts.config.Events
+	}
+	if config == nil {
+		config = DefaultEventConfig()
+	}
+	return tts.events.subscribe(ctx, config)
+}
+
+// DroppedEvents 返回因订阅者缓冲或回调队列满而丢弃的事件数
+func (tts *TextToAudioStream) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&tts.events.dropped) + tts.dispatcher.droppedEvents()
+}
+
+// emit 发布事件，并交给回调分发器在专用协程中调用对应的回调
+func (tts *TextToAudioStream) emit(event Event) {
+	tts.events.publish(event)
+	tts.dispatcher.enqueue(event)
+}
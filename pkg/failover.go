@@ -0,0 +1,328 @@
+package realtimetts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FailoverConfig 引擎故障切换配置
+type FailoverConfig struct {
+	// Priorities 按引擎名称指定优先级，数值越小越优先
+	// 未指定的引擎使用其在引擎列表中的下标
+	Priorities map[string]int
+
+	HealthWindow           int           // 健康评分统计的最近请求数
+	LatencyThreshold       time.Duration // 首帧延迟超过此值时降低健康评分
+	MinHealthScore         float64       // 低于此评分的引擎仅在没有其他可用引擎时使用
+	Cooldown               time.Duration // 引擎失败后再次尝试前的冷却时间
+	MaxAttemptsPerSentence int           // 每个句子最多尝试的次数
+}
+
+// DefaultFailoverConfig 返回默认故障切换配置
+func DefaultFailoverConfig() *FailoverConfig {
+	return &FailoverConfig{
+		Priorities:             make(map[string]int),
+		HealthWindow:           20,
+		LatencyThreshold:       2 * time.Second,
+		MinHealthScore:         0.5,
+		Cooldown:               30 * time.Second,
+		MaxAttemptsPerSentence: 3,
+	}
+}
+
+// 引擎切换原因
+const (
+	SwitchReasonError     = "合成失败"
+	SwitchReasonCooldown  = "冷却中"
+	SwitchReasonUnhealthy = "健康评分过低"
+	SwitchReasonRecovered = "高优先级引擎已恢复"
+	SwitchReasonRouting   = "按文本选择引擎"
+)
+
+// EngineHealth 引擎健康状态快照
+type EngineHealth struct {
+	Name                string        // 引擎名称
+	Priority            int           // 优先级
+	Score               float64       // 健康评分 (0.0 - 1.0)
+	ErrorRate           float64       // 最近请求的错误率
+	AverageLatency      time.Duration // 最近成功请求的平均首帧延迟
+	ConsecutiveFailures int           // 连续失败次数
+	CooldownUntil       time.Time     // 冷却结束时间
+	LastError           error         // 最后一次错误
+}
+
+// healthSample 一次请求的结果
+type healthSample struct {
+	failed  bool
+	latency time.Duration
+}
+
+// engineState 引擎的运行状态
+type engineState struct {
+	engine              TTSEngine
+	name                string
+	priority            int
+	samples             []healthSample
+	consecutiveFailures int
+	cooldownUntil       time.Time
+	lastError           error
+}
+
+// FailoverManager 引擎故障切换管理器
+// 按优先级选择引擎，依据最近的错误和延迟计算健康评分，
+// 失败的引擎进入冷却期，冷却结束后优先级更高的引擎会被重新选用
+type FailoverManager struct {
+	mu      sync.RWMutex
+	config  *FailoverConfig
+	engines []*engineState // 按优先级排序
+	current int            // 当前使用的引擎下标
+	now     func() time.Time
+}
+
+// NewFailoverManager 创建新的故障切换管理器
+func NewFailoverManager(engines []TTSEngine, config *FailoverConfig) *FailoverManager {
+	if config == nil {
+		config = DefaultFailoverConfig()
+	}
+
+	states := make([]*engineState, len(engines))
+	for i, engine := range engines {
+		name := engine.GetEngineInfo().Name
+		priority := i
+		if p, ok := config.Priorities[name]; ok {
+			priority = p
+		}
+		states[i] = &engineState{engine: engine, name: name, priority: priority}
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].priority < states[j].priority
+	})
+
+	return &FailoverManager{
+		config:  config,
+		engines: states,
+		current: 0,
+		now:     time.Now,
+	}
+}
+
+// SetClock 替换冷却期计算使用的时间来源，传入 nil 恢复为 time.Now
+func (fm *FailoverManager) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.now = now
+}
+
+// MaxAttempts 返回每个句子最多尝试的次数
+func (fm *FailoverManager) MaxAttempts() int {
+	attempts := fm.config.MaxAttemptsPerSentence
+	if attempts <= 0 || attempts > len(fm.engines) {
+		attempts = len(fm.engines)
+	}
+	return attempts
+}
+
+// Current 返回当前使用的引擎
+func (fm *FailoverManager) Current() TTSEngine {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	if len(fm.engines) == 0 {
+		return nil
+	}
+	return fm.engines[fm.current].engine
+}
+
+// Len 返回引擎数量
+func (fm *FailoverManager) Len() int {
+	return len(fm.engines)
+}
+
+// Select 为下一次合成选择引擎
+// tried 为本句已尝试过的引擎下标；优先选择不在冷却期且健康（或刚结束冷却、待试探）
+// 的最高优先级引擎，其次是不在冷却期的引擎，最后是冷却最先结束的引擎。
+// 选中的引擎与当前引擎不同时返回切换原因
+func (fm *FailoverManager) Select(tried map[int]bool) (index int, reason string, ok bool) {
+	return fm.SelectFor(tried, nil)
+}
+
+// SelectFor 与 Select 相同，但跳过 unsupported 中不支持当前文本的引擎；
+// 因文本而发生的切换不视为故障，原因为 SwitchReasonRouting
+func (fm *FailoverManager) SelectFor(tried, unsupported map[int]bool) (index int, reason string, ok bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	now := fm.now()
+	healthy, available, cooling := -1, -1, -1
+	for i, state := range fm.engines {
+		if tried[i] || unsupported[i] {
+			continue
+		}
+		if now.Before(state.cooldownUntil) {
+			if cooling < 0 || state.cooldownUntil.Before(fm.engines[cooling].cooldownUntil) {
+				cooling = i
+			}
+			continue
+		}
+		if available < 0 {
+			available = i
+		}
+		// 冷却结束的失败引擎给予一次试探机会，否则评分永远无法恢复
+		probing := state.consecutiveFailures > 0
+		if healthy < 0 && (probing || fm.score(state) >= fm.config.MinHealthScore) {
+			healthy = i
+		}
+	}
+
+	switch {
+	case healthy >= 0:
+		index = healthy
+	case available >= 0:
+		index = available
+	case cooling >= 0:
+		index = cooling
+	default:
+		return -1, "", false
+	}
+
+	if index != fm.current {
+		reason = fm.switchReason(fm.engines[fm.current], index, unsupported[fm.current], now)
+		fm.current = index
+	}
+	return index, reason, true
+}
+
+// switchReason 推断从当前引擎切换到 index 的原因
+// 当前引擎不支持该文本，或切回一个从未失败过的高优先级引擎时，视为按文本路由
+func (fm *FailoverManager) switchReason(previous *engineState, index int, previousUnsupported bool, now time.Time) string {
+	switch {
+	case previousUnsupported:
+		return SwitchReasonRouting
+	case index < fm.current && fm.engines[index].lastError == nil:
+		return SwitchReasonRouting
+	case index < fm.current:
+		return SwitchReasonRecovered
+	case previous.lastError != nil && now.Before(previous.cooldownUntil):
+		return fmt.Sprintf("%s: %v", SwitchReasonError, previous.lastError)
+	case now.Before(previous.cooldownUntil):
+		return SwitchReasonCooldown
+	default:
+		return SwitchReasonUnhealthy
+	}
+}
+
+// Engine 返回指定下标的引擎
+func (fm *FailoverManager) Engine(index int) TTSEngine {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+	return fm.engines[index].engine
+}
+
+// ReportSuccess 记录一次成功的合成及其首帧延迟
+func (fm *FailoverManager) ReportSuccess(index int, latency time.Duration) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	state := fm.engines[index]
+	fm.addSample(state, healthSample{latency: latency})
+	state.consecutiveFailures = 0
+	state.cooldownUntil = time.Time{}
+}
+
+// ReportFailure 记录一次失败的合成，引擎进入冷却期
+// 只有可重试的错误（如网络、超时、服务繁忙）和未分类的错误计入健康度和冷却；
+// 引擎分类为不可重试的错误（如输入无效、认证失败）不说明引擎暂时不可用，不影响其他请求
+func (fm *FailoverManager) ReportFailure(index int, err error) {
+	if !countsAgainstHealth(err) {
+		return
+	}
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	state := fm.engines[index]
+	fm.addSample(state, healthSample{failed: true})
+	state.consecutiveFailures++
+	state.lastError = err
+	state.cooldownUntil = fm.now().Add(fm.config.Cooldown)
+}
+
+// countsAgainstHealth 判断失败是否计入引擎的健康度和冷却
+func countsAgainstHealth(err error) bool {
+	if errors.Is(err, ErrEngineInvalidInput) {
+		return false
+	}
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) {
+		return engineErr.Retryable
+	}
+	return true
+}
+
+// addSample 记录样本，只保留最近 HealthWindow 个
+func (fm *FailoverManager) addSample(state *engineState, sample healthSample) {
+	state.samples = append(state.samples, sample)
+	if window := fm.config.HealthWindow; window > 0 && len(state.samples) > window {
+		state.samples = state.samples[len(state.samples)-window:]
+	}
+}
+
+// score 计算健康评分：成功率乘以延迟系数
+func (fm *FailoverManager) score(state *engineState) float64 {
+	errorRate, latency := fm.rates(state)
+	score := 1.0 - errorRate
+	if threshold := fm.config.LatencyThreshold; threshold > 0 && latency > threshold {
+		score *= float64(threshold) / float64(latency)
+	}
+	return score
+}
+
+// rates 计算错误率和成功请求的平均延迟
+func (fm *FailoverManager) rates(state *engineState) (errorRate float64, latency time.Duration) {
+	if len(state.samples) == 0 {
+		return 0, 0
+	}
+	var failures, successes int
+	var total time.Duration
+	for _, sample := range state.samples {
+		if sample.failed {
+			failures++
+			continue
+		}
+		successes++
+		total += sample.latency
+	}
+	errorRate = float64(failures) / float64(len(state.samples))
+	if successes > 0 {
+		latency = total / time.Duration(successes)
+	}
+	return errorRate, latency
+}
+
+// Health 返回所有引擎的健康状态，按优先级排序
+func (fm *FailoverManager) Health() []EngineHealth {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	health := make([]EngineHealth, len(fm.engines))
+	for i, state := range fm.engines {
+		errorRate, latency := fm.rates(state)
+		health[i] = EngineHealth{
+			Name:                state.name,
+			Priority:            state.priority,
+			Score:               fm.score(state),
+			ErrorRate:           errorRate,
+			AverageLatency:      latency,
+			ConsecutiveFailures: state.consecutiveFailures,
+			CooldownUntil:       state.cooldownUntil,
+			LastError:           state.lastError,
+		}
+	}
+	return health
+}
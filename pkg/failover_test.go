@@ -0,0 +1,172 @@
+package realtimetts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// namedEngine 仅提供名称的测试引擎
+type namedEngine struct {
+	name string
+}
+
+func (e *namedEngine) GetStreamInfo() *realtimetts.AudioConfiguration {
+	return realtimetts.DefaultAudioConfig()
+}
+
+func (e *namedEngine) Synthesize(ctx context.Context, text string) (*realtimetts.SynthesisStream, error) {
+	stream := realtimetts.NewSynthesisStream(0)
+	stream.Close()
+	return stream, nil
+}
+
+func (e *namedEngine) GetVoices() ([]realtimetts.Voice, error)                { return nil, nil }
+func (e *namedEngine) SetVoice(voice realtimetts.Voice) error                 { return nil }
+func (e *namedEngine) SetVoiceParameters(params map[string]interface{}) error { return nil }
+func (e *namedEngine) SetAudioBuffer(audioBuffer *realtimetts.AudioBuffer)    {}
+func (e *namedEngine) GetEngineInfo() realtimetts.EngineInfo {
+	return realtimetts.EngineInfo{Name: e.name}
+}
+func (e *namedEngine) Initialize() error { return nil }
+func (e *namedEngine) Close() error      { return nil }
+
+func TestFailoverManagerCooldownAndRecovery(t *testing.T) {
+	config := realtimetts.DefaultFailoverConfig()
+	config.Cooldown = 30 * time.Second
+	config.Priorities = map[string]int{"backup": 1, "primary": 0}
+
+	fm := realtimetts.NewFailoverManager([]realtimetts.TTSEngine{
+		&namedEngine{name: "backup"},
+		&namedEngine{name: "primary"},
+	}, config)
+	now := time.Unix(1700000000, 0)
+	fm.SetClock(func() time.Time { return now })
+
+	index, reason, ok := fm.Select(nil)
+	if !ok || fm.Engine(index).GetEngineInfo().Name != "primary" || reason != "" {
+		t.Fatalf("应首先选择首选引擎，实际: %d %q", index, reason)
+	}
+
+	// 首选引擎失败后切换到备用引擎
+	fm.ReportFailure(index, errors.New("boom"))
+	backup, reason, ok := fm.Select(map[int]bool{index: true})
+	if !ok || fm.Engine(backup).GetEngineInfo().Name != "backup" || reason == "" {
+		t.Fatalf("应切换到备用引擎并给出原因，实际: %d %q", backup, reason)
+	}
+	fm.ReportSuccess(backup, 10*time.Millisecond)
+
+	// 冷却期内下一句仍使用备用引擎
+	now = now.Add(config.Cooldown - time.Millisecond)
+	if next, _, _ := fm.Select(nil); next != backup {
+		t.Fatalf("冷却期内不应回到首选引擎")
+	}
+
+	// 冷却结束后回到首选引擎
+	now = now.Add(time.Millisecond)
+	next, reason, _ := fm.Select(nil)
+	if next != index || reason != realtimetts.SwitchReasonRecovered {
+		t.Fatalf("冷却结束后应回到首选引擎，实际: %d %q", next, reason)
+	}
+}
+
+func TestFailoverManagerIgnoresInputErrors(t *testing.T) {
+	fm := realtimetts.NewFailoverManager([]realtimetts.TTSEngine{
+		&namedEngine{name: "primary"},
+		&namedEngine{name: "backup"},
+	}, realtimetts.DefaultFailoverConfig())
+
+	fm.ReportFailure(0, &realtimetts.EngineError{Engine: "primary", Kind: realtimetts.ErrEngineInvalidInput})
+	fm.ReportFailure(0, &realtimetts.EngineError{Engine: "primary", Kind: realtimetts.ErrEngineAuth})
+	if index, reason, _ := fm.Select(nil); index != 0 || reason != "" {
+		t.Fatalf("不可重试的错误不应使引擎冷却，实际: %d %q", index, reason)
+	}
+	if health := fm.Health(); health[0].Score != 1.0 {
+		t.Fatalf("不可重试的错误不应计入健康度: %+v", health[0])
+	}
+
+	fm.ReportFailure(0, &realtimetts.EngineError{Engine: "primary", Kind: realtimetts.ErrEngineNetwork, Retryable: true})
+	if index, _, _ := fm.Select(nil); index != 1 {
+		t.Fatalf("可重试的错误应使引擎冷却")
+	}
+}
+
+func TestFailoverManagerMaxAttempts(t *testing.T) {
+	config := realtimetts.DefaultFailoverConfig()
+	config.MaxAttemptsPerSentence = 2
+
+	fm := realtimetts.NewFailoverManager([]realtimetts.TTSEngine{
+		&namedEngine{name: "a"},
+		&namedEngine{name: "b"},
+		&namedEngine{name: "c"},
+	}, config)
+
+	if got := fm.MaxAttempts(); got != 2 {
+		t.Fatalf("MaxAttempts = %d, 期望 2", got)
+	}
+
+	health := fm.Health()
+	if len(health) != 3 || health[0].Score != 1.0 {
+		t.Fatalf("初始健康评分应为 1.0: %+v", health)
+	}
+}
+
+func TestTextToAudioStreamStopsAfterMaxAttempts(t *testing.T) {
+	var chain []*engines.ToneEngine
+	var list []realtimetts.TTSEngine
+	for _, name := range []string{"a", "b", "c"} {
+		engine := toneEngine(name)
+		engine.SetFault(func(call int, text string) *engines.ToneFault {
+			return &engines.ToneFault{Err: realtimetts.ErrEngineBusy}
+		})
+		chain = append(chain, engine)
+		list = append(list, engine)
+	}
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.Failover.MaxAttemptsPerSentence = 2
+	}, list...)
+
+	results := make(chan realtimetts.UtteranceResult, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnUtteranceComplete = func(result realtimetts.UtteranceResult) { results <- result }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "hello world.")
+	result := wait(t, results, "话语结束")
+
+	if !errors.Is(result.Err, realtimetts.ErrEngineBusy) {
+		t.Fatalf("所有尝试失败后话语应以引擎错误结束: %v", result.Err)
+	}
+	calls := []int{chain[0].GetCallCount(), chain[1].GetCallCount(), chain[2].GetCallCount()}
+	if calls[0] != 1 || calls[1] != 1 || calls[2] != 0 {
+		t.Fatalf("每句最多尝试 2 个引擎, 实际调用次数 %v", calls)
+	}
+}
+
+func TestTextToAudioStreamDoesNotFailOverOnInvalidInput(t *testing.T) {
+	primary := toneEngine("primary")
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		return &engines.ToneFault{Err: &realtimetts.EngineError{Engine: "primary", Kind: realtimetts.ErrEngineInvalidInput}}
+	})
+	backup := toneEngine("backup")
+	stream := newTestStream(t, nil, primary, backup)
+
+	results := make(chan realtimetts.UtteranceResult, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnUtteranceComplete = func(result realtimetts.UtteranceResult) { results <- result }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "hello world.")
+	result := wait(t, results, "话语结束")
+
+	if !errors.Is(result.Err, realtimetts.ErrEngineInvalidInput) {
+		t.Fatalf("输入无效应直接返回给调用方: %v", result.Err)
+	}
+	if backup.GetCallCount() != 0 {
+		t.Fatalf("输入无效时不应切换引擎")
+	}
+}
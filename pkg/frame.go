@@ -0,0 +1,195 @@
+package realtimetts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FrameFlags 音频帧标志位
+type FrameFlags uint8
+
+const (
+	FrameStartOfSentence FrameFlags = 1 << iota // 句子的第一帧音频
+	FrameEndOfSentence                          // 句子结束
+	FrameSilence                                // 插入的静音
+	FrameTiming                                 // 时间信息标记，不含音频
+	FrameEndOfUtterance                         // 话语结束标记，不含音频
+)
+
+// Frame 音频帧
+// 引擎、AudioBuffer 和 StreamPlayer 之间传递的统一数据单元
+type Frame struct {
+	Data        []byte              // PCM 数据
+	Format      *AudioConfiguration // 数据格式，为空时视为与播放器一致
+	UtteranceID uint64              // 话语ID，每次 Feed 的文本为一个话语
+	SentenceID  int                 // 句子在话语中的序号
+	PTS         time.Duration       // 呈现时间戳，相对话语开始
+	Flags       FrameFlags          // 标志位
+	Timing      *TimingInfo         // 时间信息（仅 FrameTiming）
+}
+
+// NewAudioFrame 创建音频帧
+func NewAudioFrame(data []byte, format *AudioConfiguration) Frame {
+	return Frame{Data: data, Format: format}
+}
+
+// NewTimingFrame 创建时间信息帧
+func NewTimingFrame(timing TimingInfo) Frame {
+	return Frame{Flags: FrameTiming, Timing: &timing}
+}
+
+// NewSilenceFrame 按指定格式创建静音帧
+func NewSilenceFrame(duration time.Duration, format *AudioConfiguration) Frame {
+	samples := int(duration * time.Duration(format.SampleRate) / time.Second)
+	if samples < 0 {
+		samples = 0
+	}
+	return Frame{
+		Data:   make([]byte, samples*format.GetBytesPerFrame()),
+		Format: format,
+		Flags:  FrameSilence,
+	}
+}
+
+// Has 检查是否设置了指定标志
+func (f Frame) Has(flag FrameFlags) bool {
+	return f.Flags&flag != 0
+}
+
+// HasAudio 检查帧是否包含音频数据
+func (f Frame) HasAudio() bool {
+	return len(f.Data) > 0
+}
+
+// Duration 根据帧格式计算音频时长
+func (f Frame) Duration() time.Duration {
+	if f.Format == nil {
+		return 0
+	}
+	bytesPerSecond := f.Format.GetBytesPerSecond()
+	if bytesPerSecond <= 0 {
+		return 0
+	}
+	return time.Duration(len(f.Data)) * time.Second / time.Duration(bytesPerSecond)
+}
+
+// ByteEngine 输出原始 []byte 音频块的旧版引擎接口
+// 通过 AdaptByteEngine 包装后即可作为 TTSEngine 使用
+type ByteEngine interface {
+	GetStreamInfo() *AudioConfiguration
+	Synthesize(ctx context.Context, text string) (<-chan []byte, error)
+	GetVoices() ([]Voice, error)
+	SetVoice(voice Voice) error
+	SetVoiceParameters(params map[string]interface{}) error
+	SetAudioBuffer(audioBuffer *AudioBuffer)
+	GetEngineInfo() EngineInfo
+	Initialize() error
+	Close() error
+}
+
+// byteEngineAdapter 将 ByteEngine 适配为 TTSEngine
+type byteEngineAdapter struct {
+	ByteEngine
+}
+
+// AdaptByteEngine 将输出 []byte 的引擎包装为 TTSEngine
+// 音频块按引擎的 GetStreamInfo 标注格式；旧接口无法报告合成中途的错误
+func AdaptByteEngine(engine ByteEngine) TTSEngine {
+	return &byteEngineAdapter{ByteEngine: engine}
+}
+
+// Synthesize 执行合成并将音频块转换为帧
+func (a *byteEngineAdapter) Synthesize(ctx context.Context, text string) (*SynthesisStream, error) {
+	chunks, err := a.ByteEngine.Synthesize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromBytes(ctx, chunks, a.ByteEngine.GetStreamInfo()), nil
+}
+
+// StreamFromBytes 将 []byte 音频块通道转换为合成流
+// 帧的 PTS 按已输出音频的时长累加，ctx 取消时以 ctx 的错误结束
+func StreamFromBytes(ctx context.Context, chunks <-chan []byte, format *AudioConfiguration) *SynthesisStream {
+	stream := NewSynthesisStream(cap(chunks))
+
+	go func() {
+		var pts time.Duration
+		for chunk := range chunks {
+			frame := NewAudioFrame(chunk, format)
+			frame.PTS = pts
+			pts += frame.Duration()
+
+			if err := stream.Send(ctx, frame); err != nil {
+				// 排空上游，避免引擎协程阻塞
+				for range chunks {
+				}
+				stream.CloseWithError(err)
+				return
+			}
+		}
+		stream.Close()
+	}()
+
+	return stream
+}
+
+// SynthesisStream 一次合成输出的帧流
+// 引擎写完后调用 Close，失败时调用 CloseWithError 报告终止错误；
+// 消费者读完 Frames 后通过 Err 判断合成是否成功
+type SynthesisStream struct {
+	frames chan Frame
+	once   sync.Once
+	mu     sync.RWMutex
+	err    error
+}
+
+// NewSynthesisStream 创建合成流
+func NewSynthesisStream(bufferSize int) *SynthesisStream {
+	return &SynthesisStream{
+		frames: make(chan Frame, bufferSize),
+	}
+}
+
+// Frames 返回帧通道，合成结束后关闭
+func (s *SynthesisStream) Frames() <-chan Frame {
+	return s.frames
+}
+
+// Writer 返回帧通道的写入端，供引擎直接发送
+// 只能在 Close/CloseWithError 之前使用
+func (s *SynthesisStream) Writer() chan<- Frame {
+	return s.frames
+}
+
+// Send 发送一帧，通道满时阻塞直到有空间或 ctx 取消
+func (s *SynthesisStream) Send(ctx context.Context, frame Frame) error {
+	select {
+	case s.frames <- frame:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 正常结束合成
+func (s *SynthesisStream) Close() {
+	s.CloseWithError(nil)
+}
+
+// CloseWithError 结束合成并记录终止错误，多次调用只有第一次生效
+func (s *SynthesisStream) CloseWithError(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		close(s.frames)
+	})
+}
+
+// Err 返回合成的终止错误，应在 Frames 关闭后调用
+func (s *SynthesisStream) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
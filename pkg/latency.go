@@ -0,0 +1,202 @@
+package realtimetts
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// 延迟统计保留的最近话语数
+const latencyWindow = 100
+
+// 延迟阈值对应的阶段，用于日志
+const (
+	LatencyStageFirstChunk       = "first_chunk"
+	LatencyStageTimeToFirstAudio = "time_to_first_audio"
+	LatencyStageSentence         = "sentence"
+)
+
+// LatencyThresholds 延迟告警阈值，超过时触发 OnLatencyWarning 并传入实际延迟，0 表示不检查
+type LatencyThresholds struct {
+	TimeToFirstAudio time.Duration // 收到文本到播放第一帧
+	FirstChunk       time.Duration // 收到文本到引擎输出第一帧
+	Sentence         time.Duration // 单句从发送给引擎到收到第一帧
+}
+
+// DefaultLatencyThresholds 返回默认延迟阈值
+func DefaultLatencyThresholds() LatencyThresholds {
+	return LatencyThresholds{
+		TimeToFirstAudio: 2 * time.Second,
+	}
+}
+
+// UtteranceTimings 话语各阶段的时间点，未到达的阶段为零值
+type UtteranceTimings struct {
+	TextReceived            time.Time // 收到文本
+	FirstSentenceDispatched time.Time // 第一句发送给引擎
+	FirstEngineChunk        time.Time // 引擎输出第一帧音频
+	FirstSamplePlayed       time.Time // 第一帧音频开始播放
+	LastSamplePlayed        time.Time // 最后一帧音频播放完毕
+}
+
+// sinceReceived 返回 t 与收到文本的间隔，任一时间点缺失时返回 0
+func (t UtteranceTimings) sinceReceived(at time.Time) time.Duration {
+	if t.TextReceived.IsZero() || at.IsZero() {
+		return 0
+	}
+	return at.Sub(t.TextReceived)
+}
+
+// QueueDelay 收到文本到第一句发送给引擎
+func (t UtteranceTimings) QueueDelay() time.Duration {
+	return t.sinceReceived(t.FirstSentenceDispatched)
+}
+
+// TimeToFirstChunk 收到文本到引擎输出第一帧
+func (t UtteranceTimings) TimeToFirstChunk() time.Duration {
+	return t.sinceReceived(t.FirstEngineChunk)
+}
+
+// TimeToFirstAudio 收到文本到第一帧开始播放，即首音延迟
+func (t UtteranceTimings) TimeToFirstAudio() time.Duration {
+	return t.sinceReceived(t.FirstSamplePlayed)
+}
+
+// EndToEnd 收到文本到最后一帧播放完毕
+func (t UtteranceTimings) EndToEnd() time.Duration {
+	return t.sinceReceived(t.LastSamplePlayed)
+}
+
+// UtteranceResult 话语的处理结果，话语播放结束、失败或被 Stop 取消时通过 OnUtteranceComplete 回调，
+// 每次输入的文本恰好对应一个结果
+type UtteranceResult struct {
+	UtteranceID   uint64
+	Text          string
+	Timings       UtteranceTimings
+	AudioDuration time.Duration // 写入帧队列的音频时长，含句间静音
+	Err           error         // 合成失败或被 Stop 取消时非空
+}
+
+// LatencySummary 最近若干个话语的某项延迟统计
+type LatencySummary struct {
+	Count int
+	Last  time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+// LatencyStats 延迟统计
+// 各项统计基于最近播放完成的话语（至多 100 个），失败或取消的话语只计入 Failed
+type LatencyStats struct {
+	Completed        int64 // 播放完成的话语总数
+	Failed           int64 // 失败或取消的话语总数
+	Warnings         int64 // OnLatencyWarning 触发次数
+	QueueDelay       LatencySummary
+	TimeToFirstChunk LatencySummary
+	TimeToFirstAudio LatencySummary
+	EndToEnd         LatencySummary
+}
+
+// latencyTracker 记录最近话语的延迟
+type latencyTracker struct {
+	mu        sync.Mutex
+	completed int64
+	failed    int64
+	warnings  int64
+	recent    []UtteranceResult // 最近播放完成的话语，按完成顺序
+}
+
+// record 记录结束的话语
+func (lt *latencyTracker) record(result UtteranceResult) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	if result.Err != nil {
+		lt.failed++
+		return
+	}
+	lt.completed++
+	lt.recent = append(lt.recent, result)
+	if len(lt.recent) > latencyWindow {
+		lt.recent = lt.recent[len(lt.recent)-latencyWindow:]
+	}
+}
+
+// warn 记录一次延迟告警
+func (lt *latencyTracker) warn() {
+	lt.mu.Lock()
+	lt.warnings++
+	lt.mu.Unlock()
+}
+
+// results 返回最近播放完成的话语结果的副本
+func (lt *latencyTracker) results() []UtteranceResult {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return append([]UtteranceResult(nil), lt.recent...)
+}
+
+// stats 返回延迟统计
+func (lt *latencyTracker) stats() LatencyStats {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	summarize := func(measure func(UtteranceTimings) time.Duration) LatencySummary {
+		values := make([]time.Duration, 0, len(lt.recent))
+		for _, result := range lt.recent {
+			values = append(values, measure(result.Timings))
+		}
+		return summarizeLatencies(values)
+	}
+	return LatencyStats{
+		Completed:        lt.completed,
+		Failed:           lt.failed,
+		Warnings:         lt.warnings,
+		QueueDelay:       summarize(UtteranceTimings.QueueDelay),
+		TimeToFirstChunk: summarize(UtteranceTimings.TimeToFirstChunk),
+		TimeToFirstAudio: summarize(UtteranceTimings.TimeToFirstAudio),
+		EndToEnd:         summarize(UtteranceTimings.EndToEnd),
+	}
+}
+
+// summarizeLatencies 按完成顺序的延迟计算统计
+func summarizeLatencies(values []time.Duration) LatencySummary {
+	if len(values) == 0 {
+		return LatencySummary{}
+	}
+	summary := LatencySummary{Count: len(values), Last: values[len(values)-1]}
+
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, v := range sorted {
+		total += v
+	}
+	summary.Mean = total / time.Duration(len(sorted))
+	summary.P50 = sorted[(len(sorted)-1)*50/100]
+	summary.P95 = sorted[(len(sorted)-1)*95/100]
+	summary.Max = sorted[len(sorted)-1]
+	return summary
+}
+
+// checkLatency 延迟超过阈值时记录告警并触发 OnLatencyWarning，threshold 为 0 时不检查
+func (tts *TextToAudioStream) checkLatency(utterance *utteranceState, stage string, latency, threshold time.Duration) {
+	if threshold <= 0 || latency <= threshold {
+		return
+	}
+	tts.latency.warn()
+	utterance.logger.Warn("延迟超过阈值", "stage", stage, LogKeyLatency, latency, "threshold", threshold)
+	tts.emit(LatencyWarningEvent{UtteranceID: utterance.id, Stage: stage, Latency: latency, Threshold: threshold})
+}
+
+// GetLatencyStats 获取延迟统计
+func (tts *TextToAudioStream) GetLatencyStats() LatencyStats {
+	return tts.latency.stats()
+}
+
+// GetUtteranceResults 获取最近播放完成的话语结果，按完成顺序
+func (tts *TextToAudioStream) GetUtteranceResults() []UtteranceResult {
+	return tts.latency.results()
+}
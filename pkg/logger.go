@@ -0,0 +1,114 @@
+package realtimetts
+
+import (
+	"context"
+	"log/slog"
+)
+
+// 结构化日志的通用字段名
+const (
+	LogKeyUtterance = "utterance_id"
+	LogKeySentence  = "sentence_id"
+	LogKeyEngine    = "engine"
+	LogKeyBytes     = "bytes"
+	LogKeyLatency   = "latency"
+	LogKeyError     = "error"
+)
+
+// LoggerSetter 可注入日志记录器的组件
+// TextToAudioStream.SetLogger 会将记录器传递给实现了该接口的引擎和音频输出
+type LoggerSetter interface {
+	SetLogger(logger *slog.Logger)
+}
+
+// discardHandler 丢弃所有日志记录
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// nopLogger 未注入记录器时使用，库默认不输出日志
+var nopLogger = slog.New(discardHandler{})
+
+// RedactingLogger 返回写出前脱敏的记录器，logger 为 nil 时返回不输出的记录器
+// 库中各组件的 SetLogger 都经过此函数，注入的记录器无需调用方自行用 NewRedactingHandler 包装
+func RedactingLogger(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return nopLogger
+	}
+	switch logger.Handler().(type) {
+	case *redactingHandler, discardHandler:
+		return logger
+	}
+	return slog.New(NewRedactingHandler(logger.Handler()))
+}
+
+// redactingHandler 在写出前对日志脱敏
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// NewRedactingHandler 包装 next，写出前将消息和属性中已登记的机密替换为掩码，
+// 敏感字段名（token、password 等）的值整体替换
+func NewRedactingHandler(next slog.Handler) slog.Handler {
+	return &redactingHandler{next: next}
+}
+
+// Enabled 实现 slog.Handler
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted)}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name)}
+}
+
+// redactAttr 对单个属性脱敏，分组递归处理
+func redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		if IsSensitiveKey(attr.Key) {
+			return slog.String(attr.Key, RedactedMask)
+		}
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+		if IsSensitiveKey(attr.Key) {
+			return slog.String(attr.Key, RedactedMask)
+		}
+		return slog.String(attr.Key, Redact(value.String()))
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
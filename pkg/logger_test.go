@@ -0,0 +1,53 @@
+package realtimetts_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	realtimetts "realtimetts/pkg"
+)
+
+func TestRedactingHandler(t *testing.T) {
+	realtimetts.RegisterSecret("sk-logged-secret")
+
+	var buf bytes.Buffer
+	logger := slog.New(realtimetts.NewRedactingHandler(slog.NewJSONHandler(&buf, nil)))
+	logger = logger.With("auth", "Bearer sk-logged-secret")
+	logger.Info("request sk-logged-secret",
+		"access_token", "plain-token-value",
+		realtimetts.LogKeyError, errors.New("401: key sk-logged-secret rejected"),
+		slog.Group("request", "api_key", "another-key", realtimetts.LogKeyBytes, 128),
+	)
+
+	out := buf.String()
+	for _, leaked := range []string{"sk-logged-secret", "plain-token-value", "another-key"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("日志泄露 %q: %s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, `"bytes":128`) || !strings.Contains(out, `"error":"401: key `+realtimetts.RedactedMask) {
+		t.Fatalf("非敏感字段应保留: %s", out)
+	}
+}
+
+func TestSetLoggerRedactsInjectedLogger(t *testing.T) {
+	realtimetts.RegisterSecret("sk-injected-secret")
+
+	var buf bytes.Buffer
+	engine := realtimetts.NewBaseEngine("test")
+	engine.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	engine.Logger().Warn("请求失败", realtimetts.LogKeyError, errors.New("key sk-injected-secret rejected"), "password", "hunter2")
+
+	out := buf.String()
+	if strings.Contains(out, "sk-injected-secret") || strings.Contains(out, "hunter2") {
+		t.Fatalf("注入的记录器应自动脱敏: %s", out)
+	}
+
+	wrapped := realtimetts.RedactingLogger(engine.Logger())
+	if wrapped != engine.Logger() {
+		t.Fatal("已脱敏的记录器不应重复包装")
+	}
+}
@@ -0,0 +1,59 @@
+package realtimetts
+
+import "time"
+
+// 队列深度指标的队列名
+const (
+	QueueText   = "text"   // 待处理的输入文本
+	QueueFrames = "frames" // 引擎与播放器之间的帧队列
+)
+
+// MetricsRecorder 流水线指标的接收方
+// 方法在合成和播放协程中同步调用，实现应只做计数，不应阻塞
+type MetricsRecorder interface {
+	// ObserveTimeToFirstAudio 话语从收到文本到播放第一帧音频的耗时
+	ObserveTimeToFirstAudio(d time.Duration)
+	// ObserveSynthesis 单次合成请求的首帧延迟，err 非 nil 时为失败前的耗时
+	ObserveSynthesis(engine string, latency time.Duration, err error)
+	// AddCharacters 引擎成功合成的字符数
+	AddCharacters(engine string, n int)
+	// AddBytesPlayed 写入音频输出的字节数
+	AddBytesPlayed(n int)
+	// IncUnderrun 话语播放中途帧队列耗尽
+	IncUnderrun()
+	// IncDroppedChunk 音频输出拒绝写入而丢弃的音频块
+	IncDroppedChunk()
+	// IncFailover 合成时切换到其他引擎
+	IncFailover(from, to, reason string)
+	// IncCacheLookup 合成结果缓存的查找结果
+	IncCacheLookup(hit bool)
+	// SetQueueDepth 队列当前长度
+	SetQueueDepth(queue string, depth int)
+}
+
+// MetricsSetter 可注入指标接收方的组件
+// TextToAudioStream.SetMetrics 会将接收方传递给实现了该接口的引擎
+type MetricsSetter interface {
+	SetMetrics(metrics MetricsRecorder)
+}
+
+// NopMetrics 丢弃所有指标，可嵌入只关心部分指标的实现中
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveTimeToFirstAudio(time.Duration)         {}
+func (NopMetrics) ObserveSynthesis(string, time.Duration, error) {}
+func (NopMetrics) AddCharacters(string, int)                     {}
+func (NopMetrics) AddBytesPlayed(int)                            {}
+func (NopMetrics) IncUnderrun()                                  {}
+func (NopMetrics) IncDroppedChunk()                              {}
+func (NopMetrics) IncFailover(string, string, string)            {}
+func (NopMetrics) IncCacheLookup(bool)                           {}
+func (NopMetrics) SetQueueDepth(string, int)                     {}
+
+// metricsOrNop 返回 metrics，为 nil 时返回 NopMetrics
+func metricsOrNop(metrics MetricsRecorder) MetricsRecorder {
+	if metrics == nil {
+		return NopMetrics{}
+	}
+	return metrics
+}
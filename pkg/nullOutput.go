@@ -0,0 +1,117 @@
+package realtimetts
+
+import (
+	"sync"
+	"time"
+)
+
+// NullOutput 不依赖声卡的音频输出，写入的数据被丢弃
+// realtime 为 true 时按音频时长阻塞写入，模拟真实设备的播放节奏
+type NullOutput struct {
+	mu       sync.RWMutex
+	config   *AudioConfiguration
+	realtime bool
+	isOpen   bool
+	isActive bool
+	volume   float64
+	muted    bool
+
+	bytesWritten int64
+}
+
+// NewNullOutput 创建空音频输出
+func NewNullOutput(config *AudioConfiguration, realtime bool) *NullOutput {
+	return &NullOutput{
+		config:   config,
+		realtime: realtime,
+		volume:   config.Volume,
+		muted:    config.Muted,
+	}
+}
+
+// OpenStream 打开输出
+func (o *NullOutput) OpenStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.isOpen = true
+	return nil
+}
+
+// StartStream 启动输出
+func (o *NullOutput) StartStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.isOpen {
+		return ErrStreamNotActive
+	}
+	o.isActive = true
+	return nil
+}
+
+// StopStream 停止输出
+func (o *NullOutput) StopStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.isActive = false
+	return nil
+}
+
+// CloseStream 关闭输出
+func (o *NullOutput) CloseStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.isActive = false
+	o.isOpen = false
+	return nil
+}
+
+// WriteAudioData 丢弃音频数据并记录字节数
+func (o *NullOutput) WriteAudioData(data []byte) error {
+	o.mu.Lock()
+	if !o.isActive {
+		o.mu.Unlock()
+		return ErrStreamNotActive
+	}
+	o.bytesWritten += int64(len(data))
+	o.mu.Unlock()
+
+	if o.realtime {
+		if bytesPerSecond := o.config.GetBytesPerSecond(); bytesPerSecond > 0 {
+			time.Sleep(time.Duration(len(data)) * time.Second / time.Duration(bytesPerSecond))
+		}
+	}
+	return nil
+}
+
+// SetVolume 设置音量
+func (o *NullOutput) SetVolume(volume float64) error {
+	if volume < 0.0 || volume > 1.0 {
+		return ErrInvalidVolume
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.volume = volume
+	return nil
+}
+
+// GetVolume 获取音量
+func (o *NullOutput) GetVolume() float64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.volume
+}
+
+// SetMuted 设置静音
+func (o *NullOutput) SetMuted(muted bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.muted = muted
+	return nil
+}
+
+// BytesWritten 返回已写入的字节数
+func (o *NullOutput) BytesWritten() int64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.bytesWritten
+}
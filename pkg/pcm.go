@@ -0,0 +1,143 @@
+package realtimetts
+
+// SameFormat 检查两个配置的 PCM 格式（采样率、声道数、位深度）是否一致
+func SameFormat(a, b *AudioConfiguration) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return a.SampleRate == b.SampleRate && a.Channels == b.Channels && a.BitsPerSample == b.BitsPerSample
+}
+
+// ConvertPCM 将小端 PCM 数据从一种格式转换为另一种格式
+// 支持 8/16/24/32 位深度转换、声道混合和线性插值重采样
+func ConvertPCM(data []byte, from, to *AudioConfiguration) ([]byte, error) {
+	if SameFormat(from, to) {
+		return data, nil
+	}
+	if err := validatePCMFormat(from); err != nil {
+		return nil, err
+	}
+	if err := validatePCMFormat(to); err != nil {
+		return nil, err
+	}
+
+	samples := decodePCMSamples(data, from.BitsPerSample)
+	samples = mixChannels(samples, from.Channels, to.Channels)
+	samples = resampleLinear(samples, to.Channels, from.SampleRate, to.SampleRate)
+	return encodePCMSamples(samples, to.BitsPerSample), nil
+}
+
+// validatePCMFormat 验证格式可用于 PCM 转换
+func validatePCMFormat(c *AudioConfiguration) error {
+	if c.Channels <= 0 {
+		return ErrInvalidChannels
+	}
+	if c.SampleRate <= 0 {
+		return ErrInvalidSampleRate
+	}
+	switch c.BitsPerSample {
+	case 8, 16, 24, 32:
+		return nil
+	default:
+		return ErrInvalidBitsPerSample
+	}
+}
+
+// decodePCMSamples 将 PCM 字节解码为 [-1, 1] 范围的浮点样本（8 位为无符号）
+func decodePCMSamples(data []byte, bits int) []float64 {
+	width := bits / 8
+	samples := make([]float64, len(data)/width)
+	for i := range samples {
+		b := data[i*width:]
+		switch bits {
+		case 8:
+			samples[i] = (float64(b[0]) - 128) / 128.0
+		case 16:
+			samples[i] = float64(int16(uint16(b[0])|uint16(b[1])<<8)) / 32768.0
+		case 24:
+			v := int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16
+			if v&0x800000 != 0 {
+				v |= ^0xFFFFFF
+			}
+			samples[i] = float64(v) / 8388608.0
+		case 32:
+			samples[i] = float64(int32(uint32(b[0])|uint32(b[1])<<8|uint32(b[2])<<16|uint32(b[3])<<24)) / 2147483648.0
+		}
+	}
+	return samples
+}
+
+// encodePCMSamples 将浮点样本编码为 PCM 字节
+func encodePCMSamples(samples []float64, bits int) []byte {
+	width := bits / 8
+	data := make([]byte, len(samples)*width)
+	for i, s := range samples {
+		if s > 1 {
+			s = 1
+		} else if s < -1 {
+			s = -1
+		}
+		b := data[i*width:]
+		switch bits {
+		case 8:
+			b[0] = byte(int(s*127) + 128)
+		case 16:
+			v := int16(s * 32767)
+			b[0], b[1] = byte(v), byte(v>>8)
+		case 24:
+			v := int32(s * 8388607)
+			b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+		case 32:
+			v := int32(s * 2147483647)
+			b[0], b[1], b[2], b[3] = byte(v), byte(v>>8), byte(v>>16), byte(v>>24)
+		}
+	}
+	return data
+}
+
+// mixChannels 转换交错样本的声道数
+// 单声道复制到所有声道，多声道取平均后再分配
+func mixChannels(samples []float64, from, to int) []float64 {
+	if from == to {
+		return samples
+	}
+	frames := len(samples) / from
+	out := make([]float64, frames*to)
+	for f := 0; f < frames; f++ {
+		var sum float64
+		for c := 0; c < from; c++ {
+			sum += samples[f*from+c]
+		}
+		mono := sum / float64(from)
+		for c := 0; c < to; c++ {
+			out[f*to+c] = mono
+		}
+	}
+	return out
+}
+
+// resampleLinear 对交错样本进行线性插值重采样
+func resampleLinear(samples []float64, channels, from, to int) []float64 {
+	if from == to || len(samples) == 0 {
+		return samples
+	}
+	inFrames := len(samples) / channels
+	outFrames := int(int64(inFrames) * int64(to) / int64(from))
+	out := make([]float64, outFrames*channels)
+	ratio := float64(from) / float64(to)
+	for f := 0; f < outFrames; f++ {
+		pos := float64(f) * ratio
+		i := int(pos)
+		frac := pos - float64(i)
+		next := i + 1
+		if next >= inFrames {
+			next = inFrames - 1
+		}
+		for c := 0; c < channels; c++ {
+			a := samples[i*channels+c]
+			b := samples[next*channels+c]
+			out[f*channels+c] = a + (b-a)*frac
+		}
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package realtimetts
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedMask 替换机密内容的掩码
+const RedactedMask = "******"
+
+// 机密登记表的上限，轮换的凭据超过上限后淘汰最早登记的
+const maxRegisteredSecrets = 256
+
+// 短于此长度的值不登记，避免误伤普通文本
+const minSecretLength = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string // 按登记顺序
+	secretSet = make(map[string]bool)
+	byLength  []string // 按长度从长到短，登记时更新，供 Redact 使用
+)
+
+// RegisterSecret 登记需要脱敏的机密值，之后经 Redact 处理的文本中出现的该值都会被替换
+// 凭据来源返回的值会自动登记
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	if secretSet[value] {
+		return
+	}
+	if len(secrets) >= maxRegisteredSecrets {
+		delete(secretSet, secrets[0])
+		secrets = secrets[1:]
+	}
+	secrets = append(secrets, value)
+	secretSet[value] = true
+
+	// 先替换较长的值，避免一个机密是另一个的子串时只替换一部分
+	byLength = append(byLength[:0:0], secrets...)
+	sort.SliceStable(byLength, func(i, j int) bool { return len(byLength[i]) > len(byLength[j]) })
+}
+
+// RegisterSecrets 登记凭据中字段名为敏感字段（见 IsSensitiveKey）的值
+// 集群名、用户名等非敏感字段不登记，避免日志中的普通文本被替换
+func RegisterSecrets(values map[string]string) {
+	for key, value := range values {
+		if IsSensitiveKey(key) {
+			RegisterSecret(value)
+		}
+	}
+}
+
+// Redact 将文本中已登记的机密值替换为掩码
+func Redact(text string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, secret := range byLength {
+		if strings.Contains(text, secret) {
+			text = strings.ReplaceAll(text, secret, RedactedMask)
+		}
+	}
+	return text
+}
+
+// IsSensitiveKey 判断字段名或请求头名是否表示机密
+func IsSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, marker := range []string{"token", "secret", "password", "apikey", "authorization", "credential", "appid", "signature"} {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactJSON 返回脱敏后的 JSON：敏感字段的值替换为掩码，其余文本中的已登记机密同样替换
+// 无法解析时按普通文本处理
+func RedactJSON(raw []byte) string {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Redact(string(raw))
+	}
+	redacted, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return Redact(string(raw))
+	}
+	return Redact(string(redacted))
+}
+
+// redactValue 递归替换敏感字段
+func redactValue(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			if _, isString := value.(string); isString && IsSensitiveKey(key) {
+				node[key] = RedactedMask
+				continue
+			}
+			node[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range node {
+			node[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// RedactHeaders 返回脱敏后的请求头副本，用于记录请求
+func RedactHeaders(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		if IsSensitiveKey(key) || strings.EqualFold(key, "Cookie") {
+			redacted[key] = []string{RedactedMask}
+			continue
+		}
+		copied := make([]string, len(values))
+		for i, value := range values {
+			copied[i] = Redact(value)
+		}
+		redacted[key] = copied
+	}
+	return redacted
+}
@@ -0,0 +1,159 @@
+package realtimetts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// EngineSpec 引擎链中单个引擎的配置
+type EngineSpec struct {
+	Type        string                 `json:"type"`        // 注册的引擎类型，例如 volcengine
+	Name        string                 `json:"name"`        // 实例名称，为空时使用引擎默认名称
+	Credentials string                 `json:"credentials"` // 凭据引用，对应 EngineChainConfig.Providers 或 Credentials 中的键
+	Voice       string                 `json:"voice"`       // 语音ID
+	Params      map[string]interface{} `json:"params"`      // 语音参数，传给 SetVoiceParameters
+	Config      json.RawMessage        `json:"config"`      // 引擎特定配置，由引擎构造函数解析
+}
+
+// EngineChainConfig 引擎链配置，Engines 的顺序即故障切换的优先级
+// 凭据引用先在 Providers 中查找，再在 Credentials 中查找；Credentials 的值支持
+// "env:NAME" 和 "file:/path" 引用，见 ReferenceCredentials
+type EngineChainConfig struct {
+	Engines     []EngineSpec                  `json:"engines"`
+	Credentials map[string]map[string]string  `json:"credentials"`
+	Providers   map[string]CredentialProvider `json:"-"`
+}
+
+// EngineFactory 引擎构造函数
+// credentials 为 spec.Credentials 引用的凭据来源，未引用时为 nil；
+// 引擎应在每次请求时从中获取凭据，而不是在构造时读取一次
+type EngineFactory func(spec EngineSpec, credentials CredentialProvider) (TTSEngine, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]EngineFactory)
+)
+
+// RegisterEngine 按类型名注册引擎构造函数，通常在引擎包的 init 中调用
+// 重复注册同一类型名会 panic
+func RegisterEngine(typeName string, factory EngineFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("realtimetts: RegisterEngine 的构造函数为空")
+	}
+	if _, exists := registry[typeName]; exists {
+		panic("realtimetts: 重复注册引擎类型 " + typeName)
+	}
+	registry[typeName] = factory
+}
+
+// RegisteredEngines 返回已注册的引擎类型名，按字母排序
+func RegisteredEngines() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewEngine 按配置构造并初始化单个引擎，然后应用语音和语音参数
+func NewEngine(spec EngineSpec, credentials CredentialProvider) (TTSEngine, error) {
+	registryMu.RLock()
+	factory, ok := registry[spec.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEngineType, spec.Type)
+	}
+
+	engine, err := factory(spec, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := configureEngine(engine, spec); err != nil {
+		engine.Close()
+		return nil, err
+	}
+	return engine, nil
+}
+
+// configureEngine 初始化引擎并应用语音设置
+func configureEngine(engine TTSEngine, spec EngineSpec) error {
+	if err := engine.Initialize(); err != nil {
+		return fmt.Errorf("初始化失败: %w", err)
+	}
+	if spec.Voice != "" {
+		if err := engine.SetVoice(Voice{ID: spec.Voice, Name: spec.Voice}); err != nil {
+			return fmt.Errorf("设置语音失败: %w", err)
+		}
+	}
+	if len(spec.Params) > 0 {
+		if err := engine.SetVoiceParameters(spec.Params); err != nil {
+			return fmt.Errorf("设置语音参数失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// BuildEngines 按引擎链配置构造引擎列表，可直接传给 NewTextToAudioStream
+// 任一引擎构造失败时关闭已构造的引擎并返回错误
+func BuildEngines(config EngineChainConfig) ([]TTSEngine, error) {
+	if len(config.Engines) == 0 {
+		return nil, ErrNoEnginesAvailable
+	}
+
+	engines := make([]TTSEngine, 0, len(config.Engines))
+	fail := func(err error) ([]TTSEngine, error) {
+		for _, engine := range engines {
+			engine.Close()
+		}
+		return nil, err
+	}
+
+	for i, spec := range config.Engines {
+		var credentials CredentialProvider
+		if spec.Credentials != "" {
+			if provider, ok := config.Providers[spec.Credentials]; ok {
+				credentials = provider
+			} else if values, ok := config.Credentials[spec.Credentials]; ok {
+				credentials = ReferenceCredentials(values)
+			} else {
+				return fail(fmt.Errorf("engines[%d] (%s): %w: %q", i, spec.Type, ErrUnknownCredentials, spec.Credentials))
+			}
+		}
+
+		engine, err := NewEngine(spec, credentials)
+		if err != nil {
+			return fail(fmt.Errorf("engines[%d] (%s): %w", i, spec.Type, err))
+		}
+		engines = append(engines, engine)
+	}
+	return engines, nil
+}
+
+// DecodeEngineConfig 将 spec.Config 解析到 target，未设置的字段保留 target 的默认值
+// 规则与配置文件相同：时长可写作 "300ms" 等字符串，未知字段视为错误，以便及早发现拼写错误
+func DecodeEngineConfig(spec EngineSpec, target interface{}) error {
+	if len(spec.Config) == 0 || string(spec.Config) == "null" {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(spec.Config, &value); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidEngineConfig, spec.Type, err)
+	}
+
+	errs := &ConfigError{}
+	decodeConfigValue("config", value, reflect.ValueOf(target).Elem(), errs)
+	if len(errs.Errors) > 0 {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidEngineConfig, spec.Type, errs)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package realtimetts
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 指数退避重试策略
+type RetryPolicy struct {
+	MaxAttempts  int           // 最多尝试次数（含第一次）
+	InitialDelay time.Duration // 第一次重试前的等待时间
+	MaxDelay     time.Duration // 单次等待的上限
+	Multiplier   float64       // 每次重试等待时间的倍数
+	Jitter       float64       // 随机抖动比例 (0.0 - 1.0)
+}
+
+// DefaultRetryPolicy 返回默认重试策略
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: 200 * time.Millisecond,
+		MaxDelay:     2 * time.Second,
+		Multiplier:   2.0,
+		Jitter:       0.2,
+	}
+}
+
+// Backoff 计算第 retry 次重试（从 1 开始）前的等待时间
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	delay := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
+
+// Do 按策略执行 fn，仅对 IsRetryable 的错误重试
+// 如果 ctx 带有截止时间且剩余时间不足以等待下一次重试，直接返回最后一次错误
+func (p *RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || !IsRetryable(err) || attempt >= attempts {
+			return err
+		}
+
+		delay := p.Backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package realtimetts
+
+import (
+	"context"
+	"sync"
+)
+
+// StreamState 文本转音频流的状态
+type StreamState int
+
+const (
+	StateIdle      StreamState = iota // 未在播放，调用 Play 后开始处理输入
+	StateReady                        // 正在播放，等待输入文本
+	StateBuffering                    // 正在合成话语，尚未播放出声
+	StateSpeaking                     // 正在播放话语的音频
+	StatePaused                       // 已暂停
+	StateClosed                       // 已关闭
+)
+
+// String 返回流状态的字符串表示
+func (s StreamState) String() string {
+	switch s {
+	case StateIdle:
+		return "Idle"
+	case StateReady:
+		return "Ready"
+	case StateBuffering:
+		return "Buffering"
+	case StateSpeaking:
+		return "Speaking"
+	case StatePaused:
+		return "Paused"
+	case StateClosed:
+		return "Closed"
+	default:
+		return "Unknown"
+	}
+}
+
+// DeviceInfoProvider 能报告输出设备信息的音频输出可选实现的接口
+type DeviceInfoProvider interface {
+	GetDeviceInfo() *DeviceInfo
+}
+
+// UtteranceStatus 正在处理的话语
+type UtteranceStatus struct {
+	ID      uint64
+	Text    string
+	Timings UtteranceTimings // 已到达阶段的时间点
+}
+
+// Status 文本转音频流的状态快照
+type Status struct {
+	State             StreamState
+	CurrentUtterance  *UtteranceStatus // 最早开始且尚未播放结束的话语，没有时为 nil
+	PendingUtterances int              // 已开始合成但尚未播放结束的话语数
+	QueueLength       int              // 等待合成的输入文本数
+	CurrentEngine     string
+	EngineStatus      EngineStatus // 当前引擎的状态
+	EngineCount       int
+	Buffer            BufferStats
+	Device            *DeviceInfo // 音频输出未实现 DeviceInfoProvider 或设备未打开时为 nil
+	Playback          PlaybackStats
+	Latency           LatencyStats
+}
+
+// IsPlaying 是否处于播放中（包括等待输入和暂停）
+func (s Status) IsPlaying() bool {
+	return s.State != StateIdle && s.State != StateClosed
+}
+
+// changedFrom 与上一次通知相比是否发生了需要通知 Watch 的变化
+// 缓冲和播放统计持续变化，不作为通知条件
+func (s Status) changedFrom(previous Status) bool {
+	var id, previousID uint64
+	if s.CurrentUtterance != nil {
+		id = s.CurrentUtterance.ID
+	}
+	if previous.CurrentUtterance != nil {
+		previousID = previous.CurrentUtterance.ID
+	}
+	return s.State != previous.State ||
+		id != previousID ||
+		s.PendingUtterances != previous.PendingUtterances ||
+		s.QueueLength != previous.QueueLength ||
+		s.CurrentEngine != previous.CurrentEngine ||
+		s.EngineStatus != previous.EngineStatus
+}
+
+// statusWatchers Watch 的订阅者
+type statusWatchers struct {
+	mu       sync.Mutex
+	channels map[chan Status]struct{}
+	last     Status
+	notified bool          // last 是否有效
+	closed   chan struct{} // 流关闭时关闭
+}
+
+// GetStatus 获取状态快照
+func (tts *TextToAudioStream) GetStatus() Status {
+	tts.mu.RLock()
+	status := Status{
+		State:             tts.stateLocked(),
+		PendingUtterances: len(tts.utterances),
+		QueueLength:       len(tts.textBuffer),
+		EngineCount:       len(tts.engines),
+	}
+	for _, utterance := range tts.utterances {
+		if status.CurrentUtterance == nil || utterance.id < status.CurrentUtterance.ID {
+			status.CurrentUtterance = &UtteranceStatus{ID: utterance.id, Text: utterance.text, Timings: utterance.timings}
+		}
+	}
+	status.CurrentEngine = tts.getCurrentEngineName()
+	status.EngineStatus = tts.engineStatusLocked(status.CurrentEngine)
+	tts.mu.RUnlock()
+
+	status.Buffer = tts.GetBufferStats()
+	status.Playback = tts.GetPlaybackStats()
+	status.Device = tts.player.GetDeviceInfo()
+	status.Latency = tts.latency.stats()
+	return status
+}
+
+// stateLocked 返回当前的流状态，调用方需持有 tts.mu
+func (tts *TextToAudioStream) stateLocked() StreamState {
+	switch {
+	case tts.closed:
+		return StateClosed
+	case !tts.isPlaying:
+		return StateIdle
+	case tts.isPaused:
+		return StatePaused
+	}
+	state := StateReady
+	for _, utterance := range tts.utterances {
+		if !utterance.timings.FirstSamplePlayed.IsZero() {
+			return StateSpeaking
+		}
+		state = StateBuffering
+	}
+	return state
+}
+
+// engineStatusLocked 返回引擎的状态，调用方需持有 tts.mu
+// 引擎状态由合成过程维护：合成中为 Synthesizing，最近一次合成失败为 Error，关闭后为 Closed
+func (tts *TextToAudioStream) engineStatusLocked(name string) EngineStatus {
+	if status, ok := tts.engineStatus[name]; ok {
+		return status
+	}
+	return EngineStatusReady
+}
+
+// setEngineStatus 更新引擎状态并通知 Watch
+func (tts *TextToAudioStream) setEngineStatus(name string, status EngineStatus) {
+	tts.mu.Lock()
+	tts.engineStatus[name] = status
+	tts.mu.Unlock()
+	tts.notifyStatus()
+}
+
+// Watch 订阅状态变化，返回的通道先收到当前状态，此后在流状态、当前话语、队列长度、
+// 当前引擎或其状态变化时收到新的快照
+// 通道只保留最新的快照，读取不及时时中间的变化会被合并；ctx 结束或流关闭后通道被关闭
+func (tts *TextToAudioStream) Watch(ctx context.Context) <-chan Status {
+	ch := make(chan Status, 1)
+	watchers := &tts.watchers
+
+	watchers.mu.Lock()
+	select {
+	case <-watchers.closed:
+		ch <- tts.GetStatus()
+		close(ch)
+		watchers.mu.Unlock()
+		return ch
+	default:
+	}
+	watchers.channels[ch] = struct{}{}
+	tts.broadcastLocked(ch)
+	watchers.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-watchers.closed:
+		}
+		watchers.mu.Lock()
+		delete(watchers.channels, ch)
+		close(ch)
+		watchers.mu.Unlock()
+	}()
+	return ch
+}
+
+// notifyStatus 状态发生变化时向 Watch 的订阅者发送快照，调用方不能持有 tts.mu
+func (tts *TextToAudioStream) notifyStatus() {
+	tts.watchers.mu.Lock()
+	defer tts.watchers.mu.Unlock()
+	if len(tts.watchers.channels) == 0 {
+		tts.watchers.notified = false
+		return
+	}
+	tts.broadcastLocked(nil)
+}
+
+// broadcastLocked 状态与上次发送的不同时发送给所有订阅者，否则只发送给新订阅的 initial
+// 调用方需持有 tts.watchers.mu
+func (tts *TextToAudioStream) broadcastLocked(initial chan Status) {
+	watchers := &tts.watchers
+	status := tts.GetStatus()
+	if watchers.notified && !status.changedFrom(watchers.last) {
+		if initial != nil {
+			initial <- status
+		}
+		return
+	}
+	watchers.last, watchers.notified = status, true
+
+	for ch := range watchers.channels {
+		// 只保留最新的快照：通道已满时替换未读取的旧快照
+		select {
+		case ch <- status:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- status
+		}
+	}
+}
+
+// closeWatchers 流关闭时发送最终状态并关闭所有订阅通道
+func (tts *TextToAudioStream) closeWatchers() {
+	tts.notifyStatus()
+	watchers := &tts.watchers
+	watchers.mu.Lock()
+	defer watchers.mu.Unlock()
+	select {
+	case <-watchers.closed:
+	default:
+		close(watchers.closed)
+	}
+}
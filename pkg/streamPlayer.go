@@ -2,7 +2,9 @@ package realtimetts
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -13,7 +15,8 @@ import (
 // 提供 start/stop/pause/resume/mute 接口
 type StreamPlayer struct {
 	bufferManager *AudioBuffer
-	audioStream   *AudioStream
+	audioStream   AudioOutput
+	config        *AudioConfiguration // 播放格式
 
 	// 播放控制
 	mu             sync.RWMutex
@@ -31,9 +34,20 @@ type StreamPlayer struct {
 	onPlaybackStop   func()
 	onPlaybackPause  func()
 	onPlaybackResume func()
+	onUtteranceStart func(utteranceID uint64)
+	onUtteranceEnd   func(utteranceID uint64)
 
 	// 统计信息
-	stats *PlaybackStats
+	statsMu sync.RWMutex
+	stats   *PlaybackStats
+
+	logger  *slog.Logger    // 诊断日志，默认不输出
+	metrics MetricsRecorder // 指标，默认丢弃
+
+	// 当前播放的话语，仅在播放协程中访问
+	playingUtterance uint64
+	inUtterance      bool // 已播放该话语的音频且未取到结束标记
+	starved          bool // 本次帧队列耗尽已计为欠载
 }
 
 // PlaybackThread 播放线程
@@ -44,7 +58,6 @@ type PlaybackThread struct {
 
 // PlaybackStats 播放统计信息
 type PlaybackStats struct {
-	mu               sync.RWMutex
 	BytesPlayed      int64         // 已播放字节数
 	ChunksPlayed     int64         // 已播放块数
 	WordsPlayed      int64         // 已播放单词数
@@ -53,13 +66,17 @@ type PlaybackStats struct {
 	LastActivityTime time.Time     // 最后活动时间
 }
 
-// NewStreamPlayer 创建新的流播放器
+// NewStreamPlayer 创建新的流播放器，使用 PortAudio 输出
 func NewStreamPlayer(audioBuffer *AudioBuffer, config *AudioConfiguration, bufferSize int) *StreamPlayer {
-	audioStream := NewAudioStream(config)
+	return NewStreamPlayerWithOutput(audioBuffer, config, NewAudioStream(config))
+}
 
+// NewStreamPlayerWithOutput 使用指定的音频输出创建流播放器
+func NewStreamPlayerWithOutput(audioBuffer *AudioBuffer, config *AudioConfiguration, output AudioOutput) *StreamPlayer {
 	return &StreamPlayer{
 		bufferManager:    audioBuffer,
-		audioStream:      audioStream,
+		audioStream:      output,
+		config:           config,
 		playbackThread:   nil,
 		playbackActive:   false,
 		playbackPaused:   false,
@@ -80,9 +97,44 @@ func NewStreamPlayer(audioBuffer *AudioBuffer, config *AudioConfiguration, buffe
 			StartTime:        time.Time{},
 			LastActivityTime: time.Time{},
 		},
+		logger:  nopLogger,
+		metrics: NopMetrics{},
 	}
 }
 
+// SetMetrics 设置指标接收方，传入 nil 表示不记录
+func (sp *StreamPlayer) SetMetrics(metrics MetricsRecorder) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.metrics = metricsOrNop(metrics)
+}
+
+// getMetrics 返回当前的指标接收方
+func (sp *StreamPlayer) getMetrics() MetricsRecorder {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+	return sp.metrics
+}
+
+// SetLogger 设置诊断日志记录器，传入 nil 表示不输出
+// 音频输出实现了 LoggerSetter 时一并设置
+func (sp *StreamPlayer) SetLogger(logger *slog.Logger) {
+	sp.mu.Lock()
+	sp.logger = RedactingLogger(logger)
+	sp.mu.Unlock()
+
+	if setter, ok := sp.audioStream.(LoggerSetter); ok {
+		setter.SetLogger(logger)
+	}
+}
+
+// getLogger 返回当前的日志记录器
+func (sp *StreamPlayer) getLogger() *slog.Logger {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+	return sp.logger
+}
+
 // Start 开始播放
 func (sp *StreamPlayer) Start() error {
 	sp.mu.Lock()
@@ -117,14 +169,14 @@ func (sp *StreamPlayer) Start() error {
 	sp.pauseEvent = make(chan struct{})
 	sp.resumeEvent = make(chan struct{})
 
-	// 启动播放协程
-	go sp.playbackWorker()
+	// 启动播放协程，信号通道以参数传入，Stop 后立即 Start 时旧协程不会读到新通道
+	go sp.playbackWorker(sp.immediateStop, sp.pauseEvent, sp.resumeEvent)
 
 	// 更新统计信息
-	sp.stats.mu.Lock()
+	sp.statsMu.Lock()
 	sp.stats.StartTime = time.Now()
 	sp.stats.LastActivityTime = time.Now()
-	sp.stats.mu.Unlock()
+	sp.statsMu.Unlock()
 
 	// 触发回调
 	if sp.onPlaybackStart != nil {
@@ -169,10 +221,10 @@ func (sp *StreamPlayer) Stop() error {
 	sp.bufferManager.ClearBuffer()
 
 	// 更新统计信息
-	sp.stats.mu.Lock()
+	sp.statsMu.Lock()
 	sp.stats.PlaybackDuration = time.Since(sp.stats.StartTime)
 	sp.stats.LastActivityTime = time.Now()
-	sp.stats.mu.Unlock()
+	sp.statsMu.Unlock()
 
 	// 触发回调
 	if sp.onPlaybackStop != nil {
@@ -293,8 +345,8 @@ func (sp *StreamPlayer) GetBufferedSeconds() float64 {
 
 // GetStats 获取播放统计信息
 func (sp *StreamPlayer) GetStats() PlaybackStats {
-	sp.stats.mu.RLock()
-	defer sp.stats.mu.RUnlock()
+	sp.statsMu.RLock()
+	defer sp.statsMu.RUnlock()
 
 	return PlaybackStats{
 		BytesPlayed:      sp.stats.BytesPlayed,
@@ -308,7 +360,8 @@ func (sp *StreamPlayer) GetStats() PlaybackStats {
 
 // WaitForPlaybackComplete 等待播放完成
 func (sp *StreamPlayer) WaitForPlaybackComplete(timeout time.Duration) error {
-	fmt.Printf("   ⏳ 等待播放完成，超时时间: %v\n", timeout)
+	logger := sp.getLogger()
+	logger.Debug("等待播放完成", "timeout", timeout)
 
 	startTime := time.Now()
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -322,38 +375,34 @@ func (sp *StreamPlayer) WaitForPlaybackComplete(timeout time.Duration) error {
 		select {
 		case <-ticker.C:
 			// 检查是否还有音频数据在缓冲区中
-			sp.stats.mu.RLock()
+			sp.statsMu.RLock()
 			currentLastActivity := sp.stats.LastActivityTime
 			currentBytesPlayed := sp.stats.BytesPlayed
-			sp.stats.mu.RUnlock()
+			sp.statsMu.RUnlock()
 
 			// 如果有新的音频活动，重置无活动开始时间
 			if currentLastActivity.After(lastActivityTime) {
 				lastActivityTime = currentLastActivity
 				totalBytesPlayed = currentBytesPlayed
 				noActivityStartTime = time.Time{} // 重置
-				fmt.Printf("   📊 播放进度: %d 字节\n", currentBytesPlayed)
+				logger.Debug("播放进度", LogKeyBytes, currentBytesPlayed)
 			} else if noActivityStartTime.IsZero() {
 				// 开始记录无活动时间
 				noActivityStartTime = time.Now()
-				fmt.Printf("   ⏸️  检测到音频活动停止，开始等待PortAudio缓冲区播放完成...\n")
 			}
 
 			// 如果没有活动超过3秒，认为播放完成
 			// 3秒足够PortAudio播放完缓冲区中的数据
 			if !noActivityStartTime.IsZero() && time.Since(noActivityStartTime) > 3*time.Second {
-				fmt.Printf("   ✅ 播放完成！总播放字节数: %d\n", totalBytesPlayed)
-
 				// 额外等待1秒确保PortAudio内部缓冲区播放完成
-				fmt.Printf("   ⏳ 额外等待1秒确保PortAudio缓冲区播放完成...\n")
 				time.Sleep(1 * time.Second)
-				fmt.Printf("   🎵 PortAudio缓冲区播放完成！\n")
+				logger.Debug("播放完成", LogKeyBytes, totalBytesPlayed, "elapsed", time.Since(startTime))
 				return nil
 			}
 
 			// 检查超时
 			if time.Since(startTime) > timeout {
-				fmt.Printf("   ⚠️  等待播放完成超时\n")
+				logger.Warn("等待播放完成超时", "timeout", timeout, LogKeyBytes, totalBytesPlayed)
 				return fmt.Errorf("等待播放完成超时")
 			}
 		}
@@ -380,72 +429,147 @@ func (sp *StreamPlayer) SetCallbacks(
 	sp.onPlaybackResume = onPlaybackResume
 }
 
+// SetOnUtteranceStart 设置话语开始播放回调
+// 播放器将某个话语的第一帧音频写入音频流后触发
+func (sp *StreamPlayer) SetOnUtteranceStart(onUtteranceStart func(utteranceID uint64)) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.onUtteranceStart = onUtteranceStart
+}
+
+// SetOnUtteranceEnd 设置话语结束回调
+// 播放器取出话语结束标记时触发，此时该话语的音频均已写入音频流
+func (sp *StreamPlayer) SetOnUtteranceEnd(onUtteranceEnd func(utteranceID uint64)) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.onUtteranceEnd = onUtteranceEnd
+}
+
 // playbackWorker 播放工作协程
-func (sp *StreamPlayer) playbackWorker() {
+func (sp *StreamPlayer) playbackWorker(immediateStop, pauseEvent, resumeEvent <-chan struct{}) {
 	ticker := time.NewTicker(5 * time.Millisecond) // 5ms 检查间隔，提高响应性
 	defer ticker.Stop()
 
-	fmt.Println("   🔄 playbackWorker 启动")
+	logger := sp.getLogger()
+	logger.Debug("播放协程启动")
 	loopCount := 0
 
 	for {
 		select {
-		case <-sp.immediateStop:
-			fmt.Println("   🛑 playbackWorker 收到停止信号")
+		case <-immediateStop:
+			logger.Debug("播放协程停止")
 			return
 
-		case <-sp.pauseEvent:
-			fmt.Println("   ⏸️  playbackWorker 收到暂停信号")
+		case <-pauseEvent:
+			logger.Debug("播放暂停")
 			// 等待恢复信号
 			select {
-			case <-sp.resumeEvent:
-				fmt.Println("   ▶️  playbackWorker 收到恢复信号")
+			case <-resumeEvent:
+				logger.Debug("播放恢复")
 				continue
-			case <-sp.immediateStop:
-				fmt.Println("   🛑 playbackWorker 暂停时收到停止信号")
+			case <-immediateStop:
+				logger.Debug("播放协程停止", "paused", true)
 				return
 			}
 
 		case <-ticker.C:
 			loopCount++
 
-			// 处理音频数据
-			if err := sp.processAudioChunk(); err != nil {
+			// 按顺序处理队列中的下一帧
+			if err := sp.processFrame(); err != nil {
 				// 如果缓冲区为空，继续等待
 				if err == ErrBufferTimeout {
 					continue
 				}
+				// 已被 Stop 时写入失败是预期的，不能再停止此后 Start 的新一轮播放
+				select {
+				case <-immediateStop:
+					return
+				default:
+				}
 				// 其他错误，停止播放
-				fmt.Printf("   ❌ 处理音频块错误: %v，停止播放\n", err)
+				logger.Error("处理音频帧失败，停止播放", LogKeyError, err)
 				sp.Stop()
 				return
 			}
-
-			// 处理时间信息
-			sp.processTimingInfo()
 		}
 	}
 }
 
-// processAudioChunk 处理音频块
-func (sp *StreamPlayer) processAudioChunk() error {
-	// 从缓冲区获取音频数据
-	audioData, err := sp.bufferManager.GetFromBuffer(200 * time.Millisecond)
+// processFrame 从帧队列取出一帧并按类型处理
+// 话语播放中途帧队列超过等待时间仍为空时计为一次欠载
+func (sp *StreamPlayer) processFrame() error {
+	metrics := sp.getMetrics()
+	frame, err := sp.bufferManager.GetFrame(200 * time.Millisecond)
 	if err != nil {
+		if err == ErrBufferTimeout && sp.inUtterance && !sp.starved {
+			sp.starved = true
+			metrics.IncUnderrun()
+		}
 		return err
 	}
+	metrics.SetQueueDepth(QueueFrames, sp.bufferManager.Len())
+
+	if frame.Timing != nil {
+		sp.processTimingInfo(*frame.Timing)
+	}
+
+	if frame.HasAudio() {
+		// 引擎输出格式与播放格式不一致时先转换
+		audioData, err := ConvertPCM(frame.Data, frame.Format, sp.config)
+		if err != nil {
+			return fmt.Errorf("转换音频格式失败: %w", err)
+		}
+		if err := sp.processAudioChunk(audioData); err != nil {
+			if errors.Is(err, ErrBufferFull) {
+				metrics.IncDroppedChunk()
+			}
+			return err
+		}
+		metrics.AddBytesPlayed(len(audioData))
+
+		sp.starved = false
+		if !sp.inUtterance || frame.UtteranceID != sp.playingUtterance {
+			sp.inUtterance = true
+			sp.playingUtterance = frame.UtteranceID
+			sp.mu.RLock()
+			onUtteranceStart := sp.onUtteranceStart
+			sp.mu.RUnlock()
+			if onUtteranceStart != nil {
+				onUtteranceStart(frame.UtteranceID)
+			}
+		}
+	}
 
+	if frame.Has(FrameEndOfUtterance) {
+		sp.mu.RLock()
+		onUtteranceEnd := sp.onUtteranceEnd
+		logger := sp.logger
+		sp.mu.RUnlock()
+		sp.inUtterance, sp.starved = false, false
+		logger.Debug("话语播放完成", LogKeyUtterance, frame.UtteranceID, "duration", frame.PTS)
+		if onUtteranceEnd != nil {
+			onUtteranceEnd(frame.UtteranceID)
+		}
+	}
+	return nil
+}
+
+// processAudioChunk 处理音频块
+func (sp *StreamPlayer) processAudioChunk(audioData []byte) error {
 	// 写入音频流
 	if err := sp.audioStream.WriteAudioData(audioData); err != nil {
 		return fmt.Errorf("写入音频数据失败: %w", err)
 	}
 
 	// 更新统计信息
-	sp.stats.mu.Lock()
+	sp.statsMu.Lock()
 	sp.stats.BytesPlayed += int64(len(audioData))
 	sp.stats.ChunksPlayed++
 	sp.stats.LastActivityTime = time.Now()
-	sp.stats.mu.Unlock()
+	sp.statsMu.Unlock()
 
 	// 触发回调
 	if sp.onAudioChunk != nil {
@@ -456,20 +580,22 @@ func (sp *StreamPlayer) processAudioChunk() error {
 }
 
 // processTimingInfo 处理时间信息
-func (sp *StreamPlayer) processTimingInfo() {
-	// 从缓冲区获取时间信息
-	timing, err := sp.bufferManager.GetTimingInfo(10 * time.Millisecond)
-	if err != nil {
-		return
-	}
-
+func (sp *StreamPlayer) processTimingInfo(timing TimingInfo) {
 	// 更新统计信息
-	sp.stats.mu.Lock()
+	sp.statsMu.Lock()
 	sp.stats.WordsPlayed++
-	sp.stats.mu.Unlock()
+	sp.statsMu.Unlock()
 
 	// 触发回调
 	if sp.onWord != nil {
 		sp.onWord(timing)
 	}
 }
+
+// GetDeviceInfo 获取输出设备信息，音频输出未实现 DeviceInfoProvider 时返回 nil
+func (sp *StreamPlayer) GetDeviceInfo() *DeviceInfo {
+	if provider, ok := sp.audioStream.(DeviceInfoProvider); ok {
+		return provider.GetDeviceInfo()
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package realtimetts
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TextToAudioStream 主控制器
@@ -14,21 +19,26 @@ type TextToAudioStream struct {
 	mu sync.RWMutex
 
 	// 引擎管理
-	engines       []TTSEngine
-	currentEngine int
+	engines  []TTSEngine
+	failover *FailoverManager
+
+	// 话语计数，每次处理输入文本时递增
+	utteranceSeq uint64
 
 	// 播放控制
-	player       *StreamPlayer
-	playLock     sync.Mutex
-	ttsAudioChan chan [][]byte
+	player      *StreamPlayer
+	playLock    sync.Mutex
+	audioBuffer *AudioBuffer // 引擎输出与播放器之间唯一的帧队列
 
 	// 文本处理
-	textBuffer    chan string
+	textBuffer    chan textRequest
 	charBuffer    chan rune
 	textProcessor *TextProcessor
 
 	// 回调系统
-	callbacks *Callbacks
+	callbacks  *Callbacks
+	events     *eventBus           // Events 的订阅者
+	dispatcher *callbackDispatcher // 在专用协程中调用回调
 
 	// 状态管理
 	isPlaying bool
@@ -38,6 +48,27 @@ type TextToAudioStream struct {
 
 	// 配置
 	config *StreamConfig
+
+	logger         *slog.Logger         // 诊断日志，默认不输出
+	metrics        MetricsRecorder      // 指标，默认丢弃
+	tracerProvider trace.TracerProvider // 追踪，为空时使用 otel 全局设置
+
+	// 开始合成但尚未播放结束的话语
+	utterances map[uint64]*utteranceState
+	latency    latencyTracker // 最近话语的延迟统计
+
+	engineStatus map[string]EngineStatus // 按名称记录的引擎状态，未记录的为 Ready
+	closed       bool
+	watchers     statusWatchers
+}
+
+// textRequest 一次输入的文本
+type textRequest struct {
+	ctx      context.Context // 调用方的上下文，仅用于传递追踪的父 Span
+	id       uint64          // 话语ID，输入时分配
+	text     string
+	received time.Time
+	result   chan UtteranceResult // 可选，话语结束时接收结果，容量为 1
 }
 
 // StreamConfig 流配置
@@ -54,6 +85,13 @@ type StreamConfig struct {
 	Tokenizer               string
 	Language                string
 	Muted                   bool
+	Failover                *FailoverConfig
+	SentenceTimeout         time.Duration // 单句合成在引擎开始输出前（含引擎内部重试）的截止时间，0 表示不限
+	Output                  AudioOutput   // 音频输出，为空时使用 PortAudio
+	LatencyThresholds       LatencyThresholds
+	Events                  *EventConfig  // Events 的默认缓冲配置
+	CallbackQueue           *EventConfig  // 回调队列的容量和溢出策略，为空时使用 DefaultCallbackQueueConfig
+	CallbackTimeBudget      time.Duration // 单个回调执行超过此时长时记录警告并通过 OnError 报告，0 表示不检查
 }
 
 // TextProcessor 文本处理器
@@ -80,7 +118,12 @@ func NewTextToAudioStream(engines []TTSEngine, config *StreamConfig) *TextToAudi
 	audioBuffer.Start()
 
 	// 创建播放器
-	player := NewStreamPlayer(audioBuffer, config.AudioConfig, 1000)
+	var player *StreamPlayer
+	if config.Output != nil {
+		player = NewStreamPlayerWithOutput(audioBuffer, config.AudioConfig, config.Output)
+	} else {
+		player = NewStreamPlayer(audioBuffer, config.AudioConfig, 1000)
+	}
 
 	// 创建回调系统
 	callbacks := NewCallbacks()
@@ -96,21 +139,33 @@ func NewTextToAudioStream(engines []TTSEngine, config *StreamConfig) *TextToAudi
 
 	stream := &TextToAudioStream{
 		engines:       engines,
-		currentEngine: 0,
+		failover:      NewFailoverManager(engines, config.Failover),
 		player:        player,
 		playLock:      sync.Mutex{},
-		ttsAudioChan:  make(chan [][]byte, 1000),
-		textBuffer:    make(chan string, 100),
+		audioBuffer:   audioBuffer,
+		textBuffer:    make(chan textRequest, 100),
 		charBuffer:    make(chan rune, 1000),
 		textProcessor: textProcessor,
 		callbacks:     callbacks,
+		events:        newEventBus(),
 		isPlaying:     false,
 		isPaused:      false,
 		ctx:           ctx,
 		cancel:        cancel,
 		config:        config,
+		logger:        nopLogger,
+		metrics:       NopMetrics{},
+		utterances:    make(map[uint64]*utteranceState),
+		engineStatus:  make(map[string]EngineStatus),
+		watchers: statusWatchers{
+			channels: make(map[chan Status]struct{}),
+			closed:   make(chan struct{}),
+		},
 	}
 
+	stream.dispatcher = newCallbackDispatcher(config.CallbackQueue, config.CallbackTimeBudget,
+		stream.invokeCallbacks, stream.callbackOverBudget)
+
 	// 将AudioBuffer注入到所有引擎中
 	for _, engine := range engines {
 		engine.SetAudioBuffer(audioBuffer)
@@ -125,6 +180,8 @@ func NewTextToAudioStream(engines []TTSEngine, config *StreamConfig) *TextToAudi
 		stream.onPlaybackPause,
 		stream.onPlaybackResume,
 	)
+	player.SetOnUtteranceStart(stream.onUtteranceStart)
+	player.SetOnUtteranceEnd(stream.onUtteranceEnd)
 
 	return stream
 }
@@ -144,32 +201,113 @@ func DefaultStreamConfig() *StreamConfig {
 		Tokenizer:               "nltk",
 		Language:                "en",
 		Muted:                   false,
+		Failover:                DefaultFailoverConfig(),
+		SentenceTimeout:         20 * time.Second,
+		LatencyThresholds:       DefaultLatencyThresholds(),
+		Events:                  DefaultEventConfig(),
+		CallbackQueue:           DefaultCallbackQueueConfig(),
+		CallbackTimeBudget:      100 * time.Millisecond,
+	}
+}
+
+// SetLogger 设置诊断日志记录器，传入 nil 表示不输出，写出前经 RedactingLogger 脱敏
+// 记录器同时传递给播放器、音频输出和实现了 LoggerSetter 的引擎，引擎的日志带有 engine 字段
+func (tts *TextToAudioStream) SetLogger(logger *slog.Logger) {
+	tts.mu.Lock()
+	tts.logger = RedactingLogger(logger)
+	tts.mu.Unlock()
+
+	tts.player.SetLogger(logger)
+	for _, engine := range tts.engines {
+		setter, ok := engine.(LoggerSetter)
+		if !ok {
+			continue
+		}
+		if logger == nil {
+			setter.SetLogger(nil)
+		} else {
+			setter.SetLogger(logger.With(LogKeyEngine, engine.GetEngineInfo().Name))
+		}
+	}
+}
+
+// SetMetrics 设置指标接收方，传入 nil 表示不记录
+// 接收方同时传递给播放器和实现了 MetricsSetter 的引擎
+func (tts *TextToAudioStream) SetMetrics(metrics MetricsRecorder) {
+	tts.mu.Lock()
+	tts.metrics = metricsOrNop(metrics)
+	tts.mu.Unlock()
+
+	tts.player.SetMetrics(metrics)
+	for _, engine := range tts.engines {
+		if setter, ok := engine.(MetricsSetter); ok {
+			setter.SetMetrics(metrics)
+		}
 	}
 }
 
+// getMetrics 返回当前的指标接收方
+func (tts *TextToAudioStream) getMetrics() MetricsRecorder {
+	tts.mu.RLock()
+	defer tts.mu.RUnlock()
+	return tts.metrics
+}
+
+// getLogger 返回当前的日志记录器
+func (tts *TextToAudioStream) getLogger() *slog.Logger {
+	tts.mu.RLock()
+	defer tts.mu.RUnlock()
+	return tts.logger
+}
+
 // Feed 输入文本
 func (tts *TextToAudioStream) Feed(text string) error {
+	return tts.FeedContext(context.Background(), text)
+}
+
+// FeedContext 输入文本，ctx 中的追踪 Span 作为该话语 Span 的父 Span
+// 播放中也可以输入，文本排在已输入的话语之后；ctx 不控制合成和播放的生命周期，取消合成请使用 Stop
+func (tts *TextToAudioStream) FeedContext(ctx context.Context, text string) error {
+	return tts.feed(textRequest{ctx: ctx, text: text})
+}
+
+// FeedWithResult 输入文本，返回在该话语结束时收到结果的通道
+// 结果直接发送到通道，不经过事件订阅和回调队列，不会因读取不及时被丢弃；
+// 包括被 Stop 丢弃的文本在内，每次成功输入的文本恰好收到一个结果
+func (tts *TextToAudioStream) FeedWithResult(ctx context.Context, text string) (<-chan UtteranceResult, error) {
+	result := make(chan UtteranceResult, 1)
+	if err := tts.feed(textRequest{ctx: ctx, text: text, result: result}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// feed 分配话语ID并把文本加入输入队列
+func (tts *TextToAudioStream) feed(request textRequest) error {
+	defer tts.notifyStatus()
 	tts.mu.Lock()
 	defer tts.mu.Unlock()
 
-	if tts.isPlaying {
-		return fmt.Errorf("正在播放中，无法输入新文本")
+	if tts.closed {
+		return ErrStreamClosed
 	}
 
-	// 发送文本到缓冲区
-	select {
-	case tts.textBuffer <- text:
-		return nil
-	default:
+	// 只有持有 tts.mu 时才会发送，检查未满后发送不会阻塞
+	if len(tts.textBuffer) == cap(tts.textBuffer) {
 		return fmt.Errorf("文本缓冲区已满")
 	}
+	request.id = atomic.AddUint64(&tts.utteranceSeq, 1)
+	request.received = time.Now()
+	tts.textBuffer <- request
+	tts.metrics.SetQueueDepth(QueueText, len(tts.textBuffer))
+	return nil
 }
 
 // FeedAsync 异步输入文本
 func (tts *TextToAudioStream) FeedAsync(text string) {
 	go func() {
 		if err := tts.Feed(text); err != nil {
-			tts.callbacks.SafeCallWithArgs(tts.callbacks.OnError, err)
+			tts.emit(ErrorEvent{Err: err})
 		}
 	}()
 }
@@ -179,15 +317,25 @@ func (tts *TextToAudioStream) Play() error {
 	tts.playLock.Lock()
 	defer tts.playLock.Unlock()
 
+	tts.mu.Lock()
 	if tts.isPlaying {
+		tts.mu.Unlock()
 		return fmt.Errorf("已经在播放中")
 	}
 
 	tts.isPlaying = true
 	tts.isPaused = false
 
+	// 上一次 Stop 已取消上下文时重新创建
+	if tts.ctx.Err() != nil {
+		tts.ctx, tts.cancel = context.WithCancel(context.Background())
+	}
+	ctx := tts.ctx
+	tts.mu.Unlock()
+	tts.notifyStatus()
+
 	// 启动播放协程
-	go tts.playWorker()
+	go tts.playWorker(ctx)
 
 	return nil
 }
@@ -196,145 +344,413 @@ func (tts *TextToAudioStream) Play() error {
 func (tts *TextToAudioStream) PlayAsync() {
 	go func() {
 		if err := tts.Play(); err != nil {
-			tts.callbacks.SafeCallWithArgs(tts.callbacks.OnError, err)
+			tts.emit(ErrorEvent{Err: err})
 		}
 	}()
 }
 
 // playWorker 播放工作协程
-func (tts *TextToAudioStream) playWorker() {
+func (tts *TextToAudioStream) playWorker(ctx context.Context) {
 	defer func() {
+		// Stop 后立即 Play 时新的播放已经开始，不能覆盖其状态
 		tts.mu.Lock()
-		tts.isPlaying = false
+		if tts.ctx == ctx {
+			tts.isPlaying = false
+		}
 		tts.mu.Unlock()
+		tts.notifyStatus()
 	}()
 
 	// 启动播放器
 	if err := tts.player.Start(); err != nil {
-		tts.callbacks.SafeCallWithArgs(tts.callbacks.OnError, err)
+		tts.emit(ErrorEvent{Err: err})
 		return
 	}
 
-	// 处理文本流
+	// 处理文本流，等待期间输入的文本立即开始合成
 	for {
 		select {
-		case text := <-tts.textBuffer:
-			if err := tts.processText(text); err != nil {
-				tts.callbacks.SafeCallWithArgs(tts.callbacks.OnError, err)
+		case request, ok := <-tts.textBuffer:
+			if !ok {
 				return
 			}
-		case <-tts.ctx.Done():
-			return
-		default:
-			// 检查是否还有文本需要处理
-			if len(tts.textBuffer) == 0 {
-				time.Sleep(100 * time.Millisecond)
+			tts.getMetrics().SetQueueDepth(QueueText, len(tts.textBuffer))
+			// 一个话语失败不影响之后的话语，只有 Stop 才结束播放
+			if err := tts.processText(ctx, request); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				tts.emit(ErrorEvent{Err: err})
 			}
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
 // processText 处理文本
-func (tts *TextToAudioStream) processText(text string) error {
+// 每次输入的文本作为一个话语，合成完毕后在帧队列中写入结束标记，
+// 播放器取出结束标记时话语结束；中途失败时话语在此结束
+func (tts *TextToAudioStream) processText(ctx context.Context, request textRequest) error {
 	// 触发文本流开始回调
-	tts.callbacks.SafeCall(tts.callbacks.OnTextStreamStart)
+	tts.emit(TextStreamStartEvent{})
 
 	// 分词处理
-	sentences := tts.textProcessor.splitIntoSentences(text)
-
-	for _, sentence := range sentences {
-		if err := tts.synthesizeSentence(sentence); err != nil {
+	sentences := tts.textProcessor.splitIntoSentences(request.text)
+
+	id := request.id
+	ctx, span := tts.tracer().Start(trace.ContextWithSpan(ctx, trace.SpanFromContext(request.ctx)), "tts.utterance",
+		trace.WithTimestamp(request.received),
+		trace.WithAttributes(
+			AttrUtteranceID.Int64(int64(id)),
+			AttrSentences.Int(len(sentences)),
+			AttrCharacters.Int(len([]rune(request.text))),
+		))
+	utterance := &utteranceState{
+		id:      id,
+		text:    request.text,
+		span:    span,
+		logger:  tts.getLogger().With(LogKeyUtterance, id),
+		timings: UtteranceTimings{TextReceived: request.received},
+		result:  request.result,
+	}
+	tts.mu.Lock()
+	tts.utterances[id] = utterance
+	tts.mu.Unlock()
+	tts.notifyStatus()
+
+	utterance.logger.Debug("开始合成话语", "sentences", len(sentences), "chars", len([]rune(request.text)))
+	for i, sentence := range sentences {
+		if err := tts.synthesizeSentence(ctx, utterance, i, sentence); err != nil {
+			tts.finishUtterance(id, err)
 			return err
 		}
+		tts.mu.Lock()
+		utterance.duration = utterance.pts
+		tts.mu.Unlock()
 	}
 
+	end := Frame{UtteranceID: utterance.id, PTS: utterance.pts, Flags: FrameEndOfUtterance}
+	if err := tts.audioBuffer.PutFrame(ctx, end); err != nil {
+		tts.finishUtterance(id, err)
+		return err
+	}
+	utterance.logger.Debug("话语合成完成", "duration", utterance.pts)
+
 	// 触发文本流结束回调
-	tts.callbacks.SafeCall(tts.callbacks.OnTextStreamStop)
+	tts.emit(TextStreamStopEvent{})
 
 	return nil
 }
 
+// utteranceState 开始合成但尚未播放结束的话语
+type utteranceState struct {
+	id     uint64
+	text   string
+	span   trace.Span
+	logger *slog.Logger // 带有 utterance_id 字段
+	result chan UtteranceResult
+
+	// 仅在合成协程中访问
+	pts     time.Duration // 已写入帧队列的音频时长
+	started bool          // 是否已输出第一帧音频
+
+	// 由 tts.mu 保护
+	buffering trace.Span       // 首帧入队到开始播放
+	playback  trace.Span       // 开始播放到播放结束
+	timings   UtteranceTimings // 各阶段的时间点
+	duration  time.Duration    // 已合成完毕的句子的音频时长
+}
+
 // synthesizeSentence 合成句子
-func (tts *TextToAudioStream) synthesizeSentence(sentence string) error {
-	// 触发句子合成开始回调
-	tts.callbacks.SafeCallWithArgs(tts.callbacks.OnEngineSynthesisStart, tts.getCurrentEngineName())
+// 由故障切换管理器选择引擎；引擎失败（包括合成中途失败）时报告 OnEngineError，
+// 撤回其尚未播放的音频后换下一个引擎重试整句，每个引擎最多尝试一次，总次数不超过 MaxAttemptsPerSentence。
+// 失败引擎的音频已有部分被播放器取走时无法撤回，不再切换引擎，避免听众重复听到句子开头。
+// 不支持该文本的引擎（TextSupporter 或返回 ErrTextNotSupported）被直接跳过，不计入尝试次数
+func (tts *TextToAudioStream) synthesizeSentence(ctx context.Context, utterance *utteranceState, sentenceID int, sentence string) (err error) {
+	ctx, span := tts.tracer().Start(ctx, "tts.sentence", trace.WithAttributes(
+		AttrSentenceID.Int(sentenceID),
+		AttrCharacters.Int(len([]rune(sentence))),
+	))
+	defer func() { endSpan(span, err) }()
 
 	startTime := time.Now()
+	tts.emit(SentenceEvent{Sentence: sentence})
+
+	unsupported := tts.unsupportedEngines(sentence)
+	tried := make(map[int]bool)
+	var lastErr error
+	for attempt := 0; attempt < tts.failover.MaxAttempts(); {
+		previousName := tts.getCurrentEngineName()
+		index, reason, ok := tts.failover.SelectFor(tried, unsupported)
+		if !ok {
+			break
+		}
+		tried[index] = true
+
+		engine := tts.failover.Engine(index)
+		engineName := engine.GetEngineInfo().Name
+		if reason != "" {
+			tts.getMetrics().IncFailover(previousName, engineName, reason)
+			utterance.logger.Info("切换引擎", "from", previousName, LogKeyEngine, engineName, "reason", reason)
+			// 触发引擎切换回调
+			tts.emit(EngineSwitchEvent{From: previousName, To: engineName, Reason: reason})
+		}
 
-	// 获取当前引擎
-	engine := tts.getCurrentEngine()
-	if engine == nil {
-		return fmt.Errorf("没有可用的引擎")
-	}
+		// 触发句子合成开始回调
+		tts.setEngineStatus(engineName, EngineStatusSynthesizing)
+		tts.emit(EngineSynthesisStartEvent{Engine: engineName})
+		latency, played, engineErr, err := tts.synthesizeWithEngine(ctx, engine, utterance, sentenceID, sentence, attempt+1)
+		tts.emit(EngineSynthesisStopEvent{Engine: engineName})
+		if err == nil && engineErr != nil && !errors.Is(engineErr, ErrTextNotSupported) {
+			tts.setEngineStatus(engineName, EngineStatusError)
+		} else {
+			tts.setEngineStatus(engineName, EngineStatusReady)
+		}
+		if err != nil {
+			return err
+		}
+		if errors.Is(engineErr, ErrTextNotSupported) && !played {
+			unsupported[index] = true
+			continue
+		}
+		attempt++
+		metrics := tts.getMetrics()
+		metrics.ObserveSynthesis(engineName, latency, engineErr)
+		if engineErr == nil {
+			metrics.AddCharacters(engineName, len([]rune(sentence)))
+			tts.failover.ReportSuccess(index, latency)
+			tts.checkLatency(utterance, LatencyStageSentence, latency, tts.config.LatencyThresholds.Sentence)
+
+			// 触发句子合成完成回调
+			duration := time.Since(startTime)
+			tts.emit(SentenceSynthesizedEvent{Sentence: sentence, Duration: duration})
+			return nil
+		}
 
-	// 合成音频
-	audioChunks, err := engine.Synthesize(tts.ctx, sentence)
-	if err != nil {
-		// 尝试切换到下一个引擎
-		if tts.switchToNextEngine() {
-			return tts.synthesizeSentence(sentence) // 重试
+		lastErr = engineErr
+		utterance.logger.Warn("引擎合成失败", LogKeyEngine, engineName, LogKeySentence, sentenceID,
+			"attempt", attempt, LogKeyError, engineErr)
+		tts.failover.ReportFailure(index, engineErr)
+		tts.emit(EngineErrorEvent{Engine: engineName, Err: engineErr})
+		if played {
+			return fmt.Errorf("引擎在部分音频播放后失败，无法切换引擎: %w", engineErr)
+		}
+		if errors.Is(engineErr, ErrEngineInvalidInput) {
+			// 输入本身无效，换引擎也无法合成
+			return engineErr
 		}
-		return fmt.Errorf("所有引擎都失败了: %w", err)
 	}
 
-	// 发送音频数据到播放器
-	for audioData := range audioChunks {
-		select {
-		case tts.ttsAudioChan <- [][]byte{audioData}:
-		case <-tts.ctx.Done():
-			return tts.ctx.Err()
+	if lastErr == nil {
+		if len(unsupported) > 0 {
+			return fmt.Errorf("%w: %s", ErrTextNotSupported, sentence)
 		}
+		return ErrNoEnginesAvailable
 	}
+	return fmt.Errorf("所有引擎都失败了: %w", lastErr)
+}
 
-	// 触发句子合成完成回调
-	duration := time.Since(startTime)
-	tts.callbacks.SafeCallWithArgs(tts.callbacks.OnSentenceSynthesized, sentence, duration)
+// unsupportedEngines 返回声明不支持该文本的引擎下标
+func (tts *TextToAudioStream) unsupportedEngines(sentence string) map[int]bool {
+	unsupported := make(map[int]bool)
+	for i := 0; i < tts.failover.Len(); i++ {
+		if supporter, ok := tts.failover.Engine(i).(TextSupporter); ok && !supporter.SupportsText(sentence) {
+			unsupported[i] = true
+		}
+	}
+	return unsupported
+}
 
-	return nil
+// sentenceContext 句子合成的上下文，截止时间只约束引擎开始输出之前（含引擎内部重试）
+// 开始输出后写入帧队列的速度受播放（包括暂停）约束，不应再计入截止时间
+type sentenceContext struct {
+	context.Context
+	deadline time.Time
+	timer    *time.Timer
+	armed    atomic.Bool
 }
 
-// getCurrentEngine 获取当前引擎
-func (tts *TextToAudioStream) getCurrentEngine() TTSEngine {
-	tts.mu.RLock()
-	defer tts.mu.RUnlock()
+// newSentenceContext 创建在 timeout 后取消的句子上下文，disarm 之后不再超时
+func newSentenceContext(parent context.Context, timeout time.Duration) (*sentenceContext, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	sc := &sentenceContext{Context: ctx, deadline: time.Now().Add(timeout)}
+	sc.armed.Store(true)
+	sc.timer = time.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) })
+	return sc, func() {
+		sc.timer.Stop()
+		cancel(context.Canceled)
+	}
+}
 
-	if len(tts.engines) == 0 {
-		return nil
+// Deadline 未解除时报告句子截止时间，供 RetryPolicy 计算剩余的重试预算
+func (sc *sentenceContext) Deadline() (time.Time, bool) {
+	if sc.armed.Load() {
+		return sc.deadline, true
 	}
+	return sc.Context.Deadline()
+}
 
-	return tts.engines[tts.currentEngine]
+// Err 超时取消时返回 context.DeadlineExceeded，与 context.WithTimeout 一致
+func (sc *sentenceContext) Err() error {
+	err := sc.Context.Err()
+	if err != nil && context.Cause(sc.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
 }
 
-// getCurrentEngineName 获取当前引擎名称
-func (tts *TextToAudioStream) getCurrentEngineName() string {
-	engine := tts.getCurrentEngine()
-	if engine == nil {
-		return "unknown"
+// disarm 引擎开始输出后解除截止时间；已超时则保持取消状态
+func (sc *sentenceContext) disarm() {
+	if sc.timer.Stop() {
+		sc.armed.Store(false)
 	}
-	return engine.GetEngineInfo().Name
 }
 
-// switchToNextEngine 切换到下一个引擎
-func (tts *TextToAudioStream) switchToNextEngine() bool {
+// synthesizeWithEngine 使用指定引擎合成句子并写入帧队列，attempt 为本句的第几次尝试（从 1 开始）
+// latency 为首帧延迟；engineErr 为引擎侧的失败，此时本次写入的帧已从队列撤回，
+// played 表示其中有帧已被播放器取走、无法撤回；err 为上下文取消或缓冲区关闭，应直接返回
+func (tts *TextToAudioStream) synthesizeWithEngine(ctx context.Context, engine TTSEngine, utterance *utteranceState, sentenceID int, sentence string, attempt int) (latency time.Duration, played bool, engineErr error, err error) {
+	// 退出时取消引擎侧的合成，避免写入失败后引擎协程阻塞；
+	// 句子截止时间同时约束引擎内部的重试预算，引擎输出第一帧后解除
+	var synthCtx context.Context
+	var cancel context.CancelFunc
+	disarm := func() {}
+	if tts.config.SentenceTimeout > 0 {
+		var sentenceCtx *sentenceContext
+		sentenceCtx, cancel = newSentenceContext(ctx, tts.config.SentenceTimeout)
+		synthCtx, disarm = sentenceCtx, sentenceCtx.disarm
+	} else {
+		synthCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// 引擎在 synthCtx 上创建的 Span（如 HTTP 请求）挂在本次尝试之下
+	var audioBytes int
+	synthCtx, span := tts.tracer().Start(synthCtx, "tts.synthesize", trace.WithAttributes(
+		AttrEngine.String(engine.GetEngineInfo().Name),
+		AttrSentenceID.Int(sentenceID),
+		AttrAttempt.Int(attempt),
+	))
+	defer func() {
+		span.SetAttributes(AttrBytes.Int(audioBytes), AttrLatencyMs.Int64(latency.Milliseconds()))
+		if engineErr != nil {
+			endSpan(span, engineErr)
+		} else {
+			endSpan(span, err)
+		}
+	}()
+
+	// 合成音频
+	startTime := time.Now()
 	tts.mu.Lock()
-	defer tts.mu.Unlock()
+	if utterance.timings.FirstSentenceDispatched.IsZero() {
+		utterance.timings.FirstSentenceDispatched = startTime
+	}
+	tts.mu.Unlock()
+	stream, err := engine.Synthesize(synthCtx, sentence)
+	if err != nil {
+		return 0, false, err, ctx.Err()
+	}
+
+	// 发送音频帧到帧队列，记录写入的帧数以便失败时撤回
+	sentenceStarted := false
+	sentenceStart := utterance.pts
+	queued := 0
+	for frame := range stream.Frames() {
+		if queued == 0 {
+			disarm()
+		}
+		frame.UtteranceID = utterance.id
+		frame.SentenceID = sentenceID
+		frame.PTS = utterance.pts
+		if frame.Format == nil {
+			frame.Format = engine.GetStreamInfo()
+		}
+		if frame.Timing != nil {
+			// 引擎报告的时间相对本句，换算为相对话语开始
+			timing := *frame.Timing
+			timing.StartTime += sentenceStart
+			timing.EndTime += sentenceStart
+			frame.Timing = &timing
+		}
+
+		if frame.HasAudio() {
+			if !sentenceStarted {
+				frame.Flags |= FrameStartOfSentence
+				sentenceStarted = true
+				latency = time.Since(startTime)
+			}
+			if !utterance.started {
+				tts.emit(AudioStreamStartEvent{UtteranceID: utterance.id})
+				utterance.started = true
+				_, buffering := tts.tracer().Start(trace.ContextWithSpan(context.Background(), utterance.span), "tts.buffering")
+				tts.mu.Lock()
+				utterance.buffering = buffering
+				utterance.timings.FirstEngineChunk = time.Now()
+				firstChunk := utterance.timings.TimeToFirstChunk()
+				tts.mu.Unlock()
+				tts.checkLatency(utterance, LatencyStageFirstChunk, firstChunk, tts.config.LatencyThresholds.FirstChunk)
+			}
+			utterance.pts += frame.Duration()
+			audioBytes += len(frame.Data)
+		}
+
+		if err := tts.audioBuffer.PutFrame(ctx, frame); err != nil {
+			return 0, false, nil, err
+		}
+		queued++
+	}
 
-	if len(tts.engines) <= 1 {
-		return false // 只有一个引擎，无法切换
+	// 引擎报告的终止错误；上下文已取消时以取消为准
+	if err := stream.Err(); err != nil {
+		if ctx.Err() != nil {
+			return 0, false, err, ctx.Err()
+		}
+		// 本句只有成功后才写入句尾静音，队列中属于本句的帧都来自这次尝试
+		removed := tts.audioBuffer.RemoveFrames(func(frame Frame) bool {
+			return frame.UtteranceID == utterance.id && frame.SentenceID == sentenceID
+		})
+		utterance.pts = sentenceStart
+		return 0, removed < queued, err, nil
 	}
 
-	oldEngine := tts.engines[tts.currentEngine].GetEngineInfo().Name
-	tts.currentEngine = (tts.currentEngine + 1) % len(tts.engines)
-	newEngine := tts.engines[tts.currentEngine].GetEngineInfo().Name
+	// 句后静音，同时标记句子结束
+	silence := NewSilenceFrame(tts.config.SentenceSilenceDuration, tts.config.AudioConfig)
+	silence.UtteranceID = utterance.id
+	silence.SentenceID = sentenceID
+	silence.PTS = utterance.pts
+	silence.Flags |= FrameEndOfSentence
+	utterance.pts += silence.Duration()
+	if err := tts.audioBuffer.PutFrame(ctx, silence); err != nil {
+		return 0, false, nil, err
+	}
 
-	// 触发引擎切换回调
-	tts.callbacks.SafeCallWithArgs(tts.callbacks.OnEngineSwitch, oldEngine, newEngine)
+	if !sentenceStarted {
+		latency = time.Since(startTime)
+	}
+	utterance.logger.Debug("句子合成完成", LogKeyEngine, engine.GetEngineInfo().Name, LogKeySentence, sentenceID,
+		LogKeyBytes, audioBytes, LogKeyLatency, latency, "duration", time.Since(startTime))
+	return latency, false, nil, nil
+}
 
-	return true
+// getCurrentEngine 获取当前引擎
+func (tts *TextToAudioStream) getCurrentEngine() TTSEngine {
+	return tts.failover.Current()
+}
+
+// getCurrentEngineName 获取当前引擎名称
+func (tts *TextToAudioStream) getCurrentEngineName() string {
+	engine := tts.getCurrentEngine()
+	if engine == nil {
+		return "unknown"
+	}
+	return engine.GetEngineInfo().Name
 }
 
 // Pause 暂停播放
 func (tts *TextToAudioStream) Pause() error {
+	defer tts.notifyStatus()
 	tts.mu.Lock()
 	defer tts.mu.Unlock()
 
@@ -352,6 +768,7 @@ func (tts *TextToAudioStream) Pause() error {
 
 // Resume 恢复播放
 func (tts *TextToAudioStream) Resume() error {
+	defer tts.notifyStatus()
 	tts.mu.Lock()
 	defer tts.mu.Unlock()
 
@@ -367,12 +784,15 @@ func (tts *TextToAudioStream) Resume() error {
 	return tts.player.Resume()
 }
 
-// Stop 停止播放
+// Stop 停止播放，正在合成和尚未合成的文本都被丢弃
 func (tts *TextToAudioStream) Stop() error {
 	tts.playLock.Lock()
 	defer tts.playLock.Unlock()
+	defer tts.notifyStatus()
 
+	tts.mu.Lock()
 	if !tts.isPlaying {
+		tts.mu.Unlock()
 		return nil
 	}
 
@@ -382,11 +802,32 @@ func (tts *TextToAudioStream) Stop() error {
 	// 取消上下文
 	tts.cancel()
 
+	// 丢弃尚未开始合成的文本
+	var discarded []textRequest
+	for len(tts.textBuffer) > 0 {
+		discarded = append(discarded, <-tts.textBuffer)
+	}
+	tts.metrics.SetQueueDepth(QueueText, 0)
+
+	// 已取消的话语不会再播放结束
+	ids := make([]uint64, 0, len(tts.utterances))
+	for id := range tts.utterances {
+		ids = append(ids, id)
+	}
+	tts.mu.Unlock()
+	for _, id := range ids {
+		tts.finishUtterance(id, context.Canceled)
+	}
+	for _, request := range discarded {
+		tts.discardRequest(request)
+	}
+
 	// 停止播放器
 	return tts.player.Stop()
 }
 
 // SetCallbacks 设置回调函数
+// 回调在专用协程中按事件发生的顺序调用，不阻塞合成和播放；回调中的 panic 被恢复并通过 OnError 报告
 func (tts *TextToAudioStream) SetCallbacks(callbacks *Callbacks) {
 	tts.mu.Lock()
 	defer tts.mu.Unlock()
@@ -395,29 +836,15 @@ func (tts *TextToAudioStream) SetCallbacks(callbacks *Callbacks) {
 	tts.textProcessor.callbacks = callbacks
 }
 
-// GetStatus 获取状态信息
-func (tts *TextToAudioStream) GetStatus() map[string]interface{} {
-	tts.mu.RLock()
-	defer tts.mu.RUnlock()
-
-	status := map[string]interface{}{
-		"is_playing":     tts.isPlaying,
-		"is_paused":      tts.isPaused,
-		"current_engine": tts.getCurrentEngineName(),
-		"engine_count":   len(tts.engines),
-	}
-
-	if tts.player != nil {
-		status["player_stats"] = tts.player.GetStats()
-	}
-
-	return status
+// GetEngineHealth 获取各引擎的健康状态，按优先级排序
+func (tts *TextToAudioStream) GetEngineHealth() []EngineHealth {
+	return tts.failover.Health()
 }
 
 // GetBufferStats 获取缓冲管理器统计信息
 func (tts *TextToAudioStream) GetBufferStats() BufferStats {
-	if tts.player != nil && tts.player.bufferManager != nil {
-		return tts.player.bufferManager.GetStats()
+	if tts.audioBuffer != nil {
+		return tts.audioBuffer.GetStats()
 	}
 	return BufferStats{}
 }
@@ -438,58 +865,166 @@ func (tts *TextToAudioStream) WaitForPlaybackComplete(timeout time.Duration) err
 	return fmt.Errorf("播放器未初始化")
 }
 
-// Close 关闭流
+// Close 关闭流，可并发或重复调用，只有第一次调用执行关闭
+// 停止播放或关闭引擎出错时仍会释放其余资源，返回遇到的错误
 func (tts *TextToAudioStream) Close() error {
-	// 停止播放
-	if err := tts.Stop(); err != nil {
-		return err
+	// 在写锁下检查并标记，并发的 Close 只有一个继续执行；标记后 FeedContext 不再接受文本
+	tts.mu.Lock()
+	if tts.closed {
+		tts.mu.Unlock()
+		return nil
 	}
+	tts.closed = true
+	tts.mu.Unlock()
 
-	// 停止播放器
+	var errs []error
+
+	// 停止播放，播放器尚未开始或已经停止时忽略
+	if err := tts.Stop(); err != nil && !errors.Is(err, ErrPlayerNotPlaying) {
+		errs = append(errs, err)
+	}
+
+	// 停止播放器，Stop 已停止时忽略
 	if tts.player != nil {
-		if err := tts.player.Stop(); err != nil {
-			return err
+		if err := tts.player.Stop(); err != nil && !errors.Is(err, ErrPlayerNotPlaying) {
+			errs = append(errs, err)
 		}
 	}
 
 	// 关闭引擎
 	for _, engine := range tts.engines {
 		if err := engine.Close(); err != nil {
-			return err
+			errs = append(errs, err)
+			continue
 		}
+		tts.setEngineStatus(engine.GetEngineInfo().Name, EngineStatusClosed)
 	}
 
-	// 关闭通道
+	// 关闭帧队列和通道，持有锁使 FeedContext 不会向已关闭的通道发送
+	tts.mu.Lock()
+	tts.audioBuffer.Close()
 	close(tts.textBuffer)
 	close(tts.charBuffer)
-	close(tts.ttsAudioChan)
+	tts.mu.Unlock()
+	tts.closeWatchers()
+	tts.events.close()
+	tts.dispatcher.close()
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // 回调函数实现
 func (tts *TextToAudioStream) onAudioChunk(data []byte) {
-	tts.callbacks.SafeCallWithArgs(tts.callbacks.OnAudioChunk, data)
+	tts.emit(AudioChunkEvent{Data: data})
 }
 
 func (tts *TextToAudioStream) onWord(timing TimingInfo) {
-	tts.callbacks.SafeCallWithArgs(tts.callbacks.OnWord, timing.Word)
+	tts.emit(WordEvent{Word: timing.Word, StartTime: timing.StartTime, EndTime: timing.EndTime})
 }
 
 func (tts *TextToAudioStream) onPlaybackStart() {
-	tts.callbacks.SafeCall(tts.callbacks.OnPlaybackStart)
+	tts.emit(PlaybackStartEvent{})
 }
 
 func (tts *TextToAudioStream) onPlaybackStop() {
-	tts.callbacks.SafeCall(tts.callbacks.OnPlaybackStop)
+	tts.emit(PlaybackStopEvent{})
 }
 
 func (tts *TextToAudioStream) onPlaybackPause() {
-	tts.callbacks.SafeCall(tts.callbacks.OnPlaybackPause)
+	tts.emit(PlaybackPauseEvent{})
 }
 
 func (tts *TextToAudioStream) onPlaybackResume() {
-	tts.callbacks.SafeCall(tts.callbacks.OnPlaybackResume)
+	tts.emit(PlaybackResumeEvent{})
+}
+
+// onUtteranceStart 话语第一帧音频开始播放，记录首音延迟
+// 结束缓冲 Span 并开始播放 Span
+func (tts *TextToAudioStream) onUtteranceStart(utteranceID uint64) {
+	tts.mu.Lock()
+	utterance, ok := tts.utterances[utteranceID]
+	metrics := tts.metrics
+	var buffering trace.Span
+	var timeToFirstAudio time.Duration
+	if ok {
+		buffering, utterance.buffering = utterance.buffering, nil
+		_, utterance.playback = tts.tracerLocked().Start(trace.ContextWithSpan(context.Background(), utterance.span), "tts.playback")
+		utterance.timings.FirstSamplePlayed = time.Now()
+		timeToFirstAudio = utterance.timings.TimeToFirstAudio()
+	}
+	tts.mu.Unlock()
+	if ok {
+		tts.notifyStatus()
+		endSpan(buffering, nil)
+		metrics.ObserveTimeToFirstAudio(timeToFirstAudio)
+		tts.checkLatency(utterance, LatencyStageTimeToFirstAudio, timeToFirstAudio, tts.config.LatencyThresholds.TimeToFirstAudio)
+	}
+}
+
+// onUtteranceEnd 话语的结束标记已播放
+func (tts *TextToAudioStream) onUtteranceEnd(utteranceID uint64) {
+	tts.finishUtterance(utteranceID, nil)
+	tts.emit(AudioStreamStopEvent{UtteranceID: utteranceID})
+}
+
+// finishUtterance 结束话语及其尚未结束的 Span，记录延迟并触发 OnUtteranceComplete
+func (tts *TextToAudioStream) finishUtterance(utteranceID uint64, err error) {
+	tts.mu.Lock()
+	utterance, ok := tts.utterances[utteranceID]
+	delete(tts.utterances, utteranceID)
+	var buffering, playback trace.Span
+	var result UtteranceResult
+	if ok {
+		buffering, playback = utterance.buffering, utterance.playback
+		utterance.buffering, utterance.playback = nil, nil
+		if err == nil {
+			utterance.timings.LastSamplePlayed = time.Now()
+		}
+		result = UtteranceResult{
+			UtteranceID:   utterance.id,
+			Text:          utterance.text,
+			Timings:       utterance.timings,
+			AudioDuration: utterance.duration,
+			Err:           err,
+		}
+	}
+	tts.mu.Unlock()
+	if !ok {
+		return
+	}
+	tts.notifyStatus()
+
+	endSpan(buffering, err)
+	endSpan(playback, err)
+	endSpan(utterance.span, err)
+
+	tts.latency.record(result)
+	utterance.logger.Debug("话语结束", "time_to_first_audio", result.Timings.TimeToFirstAudio(),
+		"end_to_end", result.Timings.EndToEnd(), LogKeyError, err)
+	deliverResult(utterance.result, result)
+	tts.emit(UtteranceCompleteEvent{Result: result})
+}
+
+// deliverResult 把结果发送给 FeedWithResult 的调用方
+// 话语只从 utterances 中删除一次，容量为 1 的通道不会阻塞
+func deliverResult(ch chan UtteranceResult, result UtteranceResult) {
+	if ch != nil {
+		ch <- result
+	}
+}
+
+// discardRequest 尚未开始合成就被 Stop 丢弃的文本同样作为取消的话语结束，
+// 使每次输入的文本都恰好触发一次 OnUtteranceComplete
+func (tts *TextToAudioStream) discardRequest(request textRequest) {
+	result := UtteranceResult{
+		UtteranceID: request.id,
+		Text:        request.text,
+		Timings:     UtteranceTimings{TextReceived: request.received},
+		Err:         context.Canceled,
+	}
+	tts.latency.record(result)
+	deliverResult(request.result, result)
+	tts.emit(UtteranceCompleteEvent{Result: result})
 }
 
 // TextProcessor 方法实现
@@ -0,0 +1,603 @@
+package realtimetts_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+)
+
+// toneEngine 返回每个词 20ms 的测试音引擎
+func toneEngine(name string) *engines.ToneEngine {
+	return engines.NewToneEngine(engines.ToneConfig{Name: name, WordDuration: 20 * time.Millisecond})
+}
+
+// newTestStream 创建输出到 NullOutput 的流，configure 可修改默认配置；测试结束时关闭流
+func newTestStream(t *testing.T, configure func(*realtimetts.StreamConfig), list ...realtimetts.TTSEngine) *realtimetts.TextToAudioStream {
+	t.Helper()
+	config := realtimetts.DefaultStreamConfig()
+	config.Output = realtimetts.NewNullOutput(config.AudioConfig, false)
+	if configure != nil {
+		configure(config)
+	}
+	stream := realtimetts.NewTextToAudioStream(list, config)
+	t.Cleanup(func() { stream.Close() })
+	return stream
+}
+
+// play 输入文本并开始播放
+func play(t *testing.T, stream *realtimetts.TextToAudioStream, text string) {
+	t.Helper()
+	if err := stream.Feed(text); err != nil {
+		t.Fatal(err)
+	}
+	stream.PlayAsync()
+}
+
+// wait 等待通道中的下一个值，5 秒内未收到时测试失败
+func wait[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("等待%s超时", what)
+	}
+	panic("unreachable")
+}
+
+// lockedBuffer 可并发写入的缓冲区
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTextToAudioStreamFailoverWithToneEngines(t *testing.T) {
+	primary := toneEngine("primary")
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		return &engines.ToneFault{Err: realtimetts.ErrEngineBusy}
+	})
+	backup := toneEngine("backup")
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.SentenceSilenceDuration = 0
+	}, primary, backup)
+
+	var mu sync.Mutex
+	var switches []string
+	var words []string
+	done := make(chan struct{})
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnEngineSwitch = func(from, to, reason string) {
+		mu.Lock()
+		switches = append(switches, from+"->"+to)
+		mu.Unlock()
+	}
+	callbacks.OnWord = func(word string) {
+		mu.Lock()
+		words = append(words, word)
+		mu.Unlock()
+	}
+	callbacks.OnAudioStreamStop = func() { close(done) }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "你好世界。")
+	wait(t, done, "播放结束")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(switches) != 1 || switches[0] != "primary->backup" {
+		t.Fatalf("应从 primary 切换到 backup: %v", switches)
+	}
+	if len(words) != 4 || words[0] != "你" {
+		t.Fatalf("逐词回调不正确: %v", words)
+	}
+	if primary.GetCallCount() != 1 || backup.GetCallCount() != 1 {
+		t.Fatalf("调用次数不正确: primary=%d backup=%d", primary.GetCallCount(), backup.GetCallCount())
+	}
+}
+
+func TestTextToAudioStreamStructuredLogging(t *testing.T) {
+	primary := toneEngine("primary")
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		return &engines.ToneFault{Err: realtimetts.ErrEngineBusy}
+	})
+	stream := newTestStream(t, nil, primary, toneEngine("backup"))
+
+	var logs lockedBuffer
+	stream.SetLogger(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	done := make(chan struct{})
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStop = func() { close(done) }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "你好世界。")
+	wait(t, done, "播放结束")
+
+	var failed, synthesized bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("无效的日志行 %q: %v", line, err)
+		}
+		switch record["msg"] {
+		case "引擎合成失败":
+			failed = record["level"] == "WARN" && record["engine"] == "primary" && record["utterance_id"] == 1.0
+		case "句子合成完成":
+			synthesized = record["engine"] == "backup" && record["bytes"].(float64) > 0 && record["latency"] != nil
+		}
+	}
+	if !failed || !synthesized {
+		t.Fatalf("缺少结构化日志 failed=%v synthesized=%v:\n%s", failed, synthesized, logs.String())
+	}
+}
+
+func TestTextToAudioStreamLatencyMeasurement(t *testing.T) {
+	engine := engines.NewToneEngine(engines.ToneConfig{Name: "slow", WordDuration: 20 * time.Millisecond, Latency: 50 * time.Millisecond})
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.LatencyThresholds = realtimetts.LatencyThresholds{FirstChunk: 20 * time.Millisecond}
+	}, engine)
+
+	var warnings []time.Duration
+	results := make(chan realtimetts.UtteranceResult, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnLatencyWarning = func(latency time.Duration) { warnings = append(warnings, latency) }
+	callbacks.OnUtteranceComplete = func(result realtimetts.UtteranceResult) { results <- result }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "你好世界。")
+	result := wait(t, results, "播放结束")
+
+	if result.Err != nil || result.Text != "你好世界。" || result.AudioDuration <= 0 {
+		t.Fatalf("话语结果不正确: %+v", result)
+	}
+	timings := result.Timings
+	points := []time.Time{timings.TextReceived, timings.FirstSentenceDispatched, timings.FirstEngineChunk,
+		timings.FirstSamplePlayed, timings.LastSamplePlayed}
+	for i := 1; i < len(points); i++ {
+		if points[i].IsZero() || points[i].Before(points[i-1]) {
+			t.Fatalf("时间点 %d 缺失或早于前一阶段: %+v", i, timings)
+		}
+	}
+	if timings.TimeToFirstChunk() < 50*time.Millisecond || timings.EndToEnd() < timings.TimeToFirstAudio() {
+		t.Fatalf("延迟不正确: first_chunk=%v ttfa=%v e2e=%v",
+			timings.TimeToFirstChunk(), timings.TimeToFirstAudio(), timings.EndToEnd())
+	}
+
+	// 首帧超过 20ms 阈值；首音阈值未设置，不应告警
+	if len(warnings) != 1 || warnings[0] != timings.TimeToFirstChunk() {
+		t.Fatalf("应触发一次首帧延迟告警: %v", warnings)
+	}
+
+	stats := stream.GetLatencyStats()
+	if stats.Completed != 1 || stats.Warnings != 1 || stats.TimeToFirstAudio.Last != timings.TimeToFirstAudio() ||
+		stats.EndToEnd.Max != timings.EndToEnd() {
+		t.Fatalf("延迟统计不正确: %+v", stats)
+	}
+	if recent := stream.GetUtteranceResults(); len(recent) != 1 || recent[0].UtteranceID != result.UtteranceID {
+		t.Fatalf("最近话语结果不正确: %+v", recent)
+	}
+}
+
+func TestTextToAudioStreamWatch(t *testing.T) {
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.Output = realtimetts.NewNullOutput(config.AudioConfig, true)
+	}, toneEngine("tone"))
+
+	updates := stream.Watch(context.Background())
+	if status := <-updates; status.State != realtimetts.StateIdle || status.CurrentEngine != "tone" {
+		t.Fatalf("初始状态不正确: %+v", status)
+	}
+
+	if err := stream.Feed("你好世界。"); err != nil {
+		t.Fatal(err)
+	}
+	if status := <-updates; status.QueueLength != 1 {
+		t.Fatalf("输入后队列长度应为 1: %+v", status)
+	}
+	stream.PlayAsync()
+
+	// 等待开始播放后回到等待输入
+	var speaking *realtimetts.UtteranceStatus
+	for done := false; !done; {
+		status := wait(t, updates, "状态变化")
+		switch {
+		case status.State == realtimetts.StateSpeaking:
+			speaking = status.CurrentUtterance
+		case status.State == realtimetts.StateReady && speaking != nil && status.PendingUtterances == 0:
+			done = true
+		}
+	}
+	if speaking == nil || speaking.Text != "你好世界。" || speaking.Timings.FirstSamplePlayed.IsZero() {
+		t.Fatalf("播放中的话语不正确: %+v", speaking)
+	}
+
+	status := stream.GetStatus()
+	if !status.IsPlaying() || status.EngineStatus != realtimetts.EngineStatusReady ||
+		status.Playback.BytesPlayed == 0 || status.Latency.Completed != 1 {
+		t.Fatalf("播放结束后的状态不正确: %+v", status)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var last realtimetts.Status
+	for status := range updates {
+		last = status
+	}
+	if last.State != realtimetts.StateClosed || last.EngineStatus != realtimetts.EngineStatusClosed {
+		t.Fatalf("关闭后应收到 Closed 状态: %+v", last)
+	}
+}
+
+func TestTextToAudioStreamEvents(t *testing.T) {
+	stream := newTestStream(t, nil, toneEngine("tone"))
+
+	var stopped sync.WaitGroup
+	stopped.Add(1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStop = stopped.Done
+	stream.SetCallbacks(callbacks)
+
+	events := stream.Events()
+	// 不读取的订阅者：只保留第一个事件，其余被丢弃
+	slow := stream.Subscribe(context.Background(), &realtimetts.EventConfig{BufferSize: 1, Overflow: realtimetts.DropNewest})
+
+	play(t, stream, "你好世界。")
+	stopped.Wait()
+
+	var order []string
+	var complete realtimetts.UtteranceCompleteEvent
+	for done := false; !done; {
+		switch e := wait(t, events, "全部事件").(type) {
+		case realtimetts.TextStreamStartEvent:
+			order = append(order, "text_start")
+		case realtimetts.EngineSynthesisStartEvent:
+			order = append(order, "synthesis_start:"+e.Engine)
+		case realtimetts.AudioStreamStartEvent:
+			order = append(order, "audio_start")
+		case realtimetts.UtteranceCompleteEvent:
+			order = append(order, "complete")
+			complete = e
+		case realtimetts.AudioStreamStopEvent:
+			order = append(order, "audio_stop")
+			done = e.UtteranceID == complete.Result.UtteranceID
+		}
+	}
+	want := []string{"text_start", "synthesis_start:tone", "audio_start", "complete", "audio_stop"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("事件顺序 %v, 期望 %v", order, want)
+	}
+	if complete.Result.Text != "你好世界。" || complete.Result.Err != nil {
+		t.Fatalf("话语结果不正确: %+v", complete.Result)
+	}
+
+	if first := <-slow; first == nil || stream.DroppedEvents() == 0 {
+		t.Fatalf("缓冲满后应丢弃新事件: first=%T dropped=%d", first, stream.DroppedEvents())
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 流关闭后订阅通道被关闭，range 才会结束
+	for range events {
+	}
+	for range slow {
+	}
+}
+
+func TestTextToAudioStreamCallbackDispatch(t *testing.T) {
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.CallbackTimeBudget = 20 * time.Millisecond
+	}, toneEngine("tone"))
+
+	var logs lockedBuffer
+	stream.SetLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	release := make(chan struct{})
+	errs := make(chan error, 2)
+	var order []string
+	stopped := make(chan struct{})
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnTextStreamStart = func() { panic("boom") }
+	callbacks.OnError = func(err error) { errs <- err }
+	callbacks.OnAudioStreamStart = func() {
+		order = append(order, "audio_start")
+		<-release
+	}
+	callbacks.OnAudioStreamStop = func() {
+		order = append(order, "audio_stop")
+		close(stopped)
+	}
+	stream.SetCallbacks(callbacks)
+
+	events := stream.Events()
+	play(t, stream, "你好世界。")
+
+	// 回调阻塞时播放照常完成
+	for done := false; !done; {
+		_, done = wait(t, events, "播放结束，回调阻塞了播放").(realtimetts.AudioStreamStopEvent)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("OnAudioStreamStop 不应早于阻塞中的 OnAudioStreamStart 执行")
+	default:
+	}
+	close(release)
+
+	wait(t, stopped, "回调")
+	if err := <-errs; !errors.Is(err, realtimetts.ErrCallbackPanic) || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("panic 应通过 OnError 报告: %v", err)
+	}
+	if err := wait(t, errs, "超时报告"); !errors.Is(err, realtimetts.ErrCallbackOverBudget) {
+		t.Fatalf("超时应通过 OnError 报告: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "audio_start,audio_stop" {
+		t.Fatalf("回调顺序 %s", got)
+	}
+	if !strings.Contains(logs.String(), "回调执行超过时间预算") {
+		t.Fatalf("缺少超时警告:\n%s", logs.String())
+	}
+}
+
+func TestTextToAudioStreamCallbackQueueOverflow(t *testing.T) {
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		config.CallbackQueue = &realtimetts.EventConfig{BufferSize: 1, Overflow: realtimetts.DropOldest}
+	}, toneEngine("tone"))
+
+	release := make(chan struct{})
+	handled := make(chan string, 64)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnTextStreamStart = func() { <-release }
+	callbacks.OnAudioStreamStart = func() { handled <- "audio_start" }
+	callbacks.OnAudioChunk = func([]byte) { handled <- "audio_chunk" }
+	callbacks.OnUtteranceComplete = func(realtimetts.UtteranceResult) { handled <- "complete" }
+	callbacks.OnAudioStreamStop = func() { handled <- "audio_stop" }
+	callbacks.OnPlaybackStop = func() { handled <- "playback_stop" }
+	stream.SetCallbacks(callbacks)
+
+	events := stream.Events()
+	play(t, stream, "one two three four.")
+	for done := false; !done; {
+		_, done = wait(t, events, "播放结束，回调队列阻塞了播放").(realtimetts.AudioStreamStopEvent)
+	}
+	if stream.DroppedEvents() == 0 {
+		t.Fatal("回调队列满后应丢弃事件")
+	}
+
+	// 只保留最新的事件，较早的音频开始事件已被丢弃
+	close(release)
+	for got := ""; got != "audio_stop"; {
+		if got = wait(t, handled, "音频结束回调"); got == "audio_start" {
+			t.Fatal("被丢弃的事件不应调用回调")
+		}
+	}
+}
+
+// gatedOutput 在 gate 关闭前阻塞写入的音频输出，使播放器停在第一帧，后续帧留在队列中
+type gatedOutput struct {
+	*realtimetts.NullOutput
+	gate chan struct{}
+}
+
+func (o *gatedOutput) WriteAudioData(data []byte) error {
+	<-o.gate
+	return o.NullOutput.WriteAudioData(data)
+}
+
+func TestTextToAudioStreamFailoverRetractsQueuedAudio(t *testing.T) {
+	// primary 合成第二句时在输出两帧后失败，这两帧仍在队列中，应被撤回后由 backup 重新合成
+	primary := engines.NewToneEngine(engines.ToneConfig{Name: "primary", WordDuration: 20 * time.Millisecond, ChunkSize: 320})
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		if call == 2 {
+			return &engines.ToneFault{Err: realtimetts.ErrEngineBusy, AfterChunks: 2}
+		}
+		return nil
+	})
+	backup := engines.NewToneEngine(engines.ToneConfig{Name: "backup", WordDuration: 20 * time.Millisecond, ChunkSize: 320})
+
+	var output *gatedOutput
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		output = &gatedOutput{NullOutput: realtimetts.NewNullOutput(config.AudioConfig, false), gate: make(chan struct{})}
+		config.Output = output
+	}, primary, backup)
+
+	var mu sync.Mutex
+	var words []string
+	switched := make(chan struct{}, 1)
+	results := make(chan realtimetts.UtteranceResult, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnWord = func(word string) {
+		mu.Lock()
+		words = append(words, word)
+		mu.Unlock()
+	}
+	callbacks.OnEngineSwitch = func(from, to, reason string) { switched <- struct{}{} }
+	callbacks.OnUtteranceComplete = func(result realtimetts.UtteranceResult) { results <- result }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "hello world. good morning.")
+	wait(t, switched, "切换引擎")
+	close(output.gate)
+	result := wait(t, results, "播放结束")
+
+	if result.Err != nil {
+		t.Fatalf("切换引擎后应正常完成: %v", result.Err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(words, " "); got != "hello world good morning" {
+		t.Fatalf("撤回的音频不应播放, 逐词回调: %s", got)
+	}
+	format := realtimetts.DefaultAudioConfig()
+	played := time.Duration(output.BytesWritten()) * time.Second / time.Duration(format.GetBytesPerSecond())
+	if diff := played - result.AudioDuration; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Fatalf("播放的音频 %v 与话语时长 %v 不一致", played, result.AudioDuration)
+	}
+}
+
+func TestTextToAudioStreamPauseLongerThanSentenceTimeout(t *testing.T) {
+	// 每个词 100 帧，整句超过帧队列容量，暂停期间合成协程阻塞在写入上，不应被句子截止时间打断
+	engine := engines.NewToneEngine(engines.ToneConfig{Name: "tone", WordDuration: time.Second, ChunkSize: 320})
+
+	var output *gatedOutput
+	stream := newTestStream(t, func(config *realtimetts.StreamConfig) {
+		output = &gatedOutput{NullOutput: realtimetts.NewNullOutput(config.AudioConfig, false), gate: make(chan struct{})}
+		config.Output = output
+		config.SentenceTimeout = 100 * time.Millisecond
+	}, engine)
+
+	started := make(chan struct{}, 1)
+	engineErrs := make(chan error, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStart = func() { started <- struct{}{} }
+	callbacks.OnEngineError = func(engine string, err error) {
+		select {
+		case engineErrs <- err:
+		default:
+		}
+	}
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, strings.Repeat("la ", 12)+".")
+	wait(t, started, "开始播放")
+	if err := stream.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	close(output.gate)
+	time.Sleep(300 * time.Millisecond)
+	if stats := stream.GetBufferStats(); stats.AudioQueueSize < 900 {
+		t.Fatalf("暂停期间帧队列应接近写满: %d", stats.AudioQueueSize)
+	}
+	if err := stream.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 恢复后合成协程继续读取引擎输出，超时取消的引擎会在此时报告错误
+	select {
+	case err := <-engineErrs:
+		t.Fatalf("暂停超过句子截止时间不应导致合成失败: %v", err)
+	case <-time.After(time.Second):
+	}
+	if engine.GetCallCount() != 1 {
+		t.Fatalf("引擎应只被调用一次: %d", engine.GetCallCount())
+	}
+}
+
+func TestTextToAudioStreamNoFailoverAfterPlayback(t *testing.T) {
+	// 第一帧播放后 primary 才失败，已播放的音频无法撤回，不应由 backup 重复合成整句
+	primary := engines.NewToneEngine(engines.ToneConfig{
+		Name: "primary", WordDuration: 20 * time.Millisecond, ChunkSize: 320, ChunkInterval: 20 * time.Millisecond,
+	})
+	primary.SetFault(func(call int, text string) *engines.ToneFault {
+		return &engines.ToneFault{Err: realtimetts.ErrEngineBusy, AfterChunks: 3}
+	})
+	backup := toneEngine("backup")
+	stream := newTestStream(t, nil, primary, backup)
+
+	results := make(chan realtimetts.UtteranceResult, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnUtteranceComplete = func(result realtimetts.UtteranceResult) { results <- result }
+	stream.SetCallbacks(callbacks)
+
+	play(t, stream, "hello world.")
+	result := wait(t, results, "话语结束")
+
+	if !errors.Is(result.Err, realtimetts.ErrEngineBusy) {
+		t.Fatalf("话语应以引擎错误结束: %v", result.Err)
+	}
+	if backup.GetCallCount() != 0 {
+		t.Fatalf("部分音频已播放时不应切换引擎, backup 调用 %d 次", backup.GetCallCount())
+	}
+}
+
+func TestTextToAudioStreamConcurrentClose(t *testing.T) {
+	stream := newTestStream(t, nil, toneEngine("tone"))
+	play(t, stream, "hello world.")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stream.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := stream.Feed("again."); !errors.Is(err, realtimetts.ErrStreamClosed) {
+		t.Fatalf("关闭后输入应返回 ErrStreamClosed: %v", err)
+	}
+}
+
+func TestTextToAudioStreamFeedWithResult(t *testing.T) {
+	stream := newTestStream(t, nil, toneEngine("tone"))
+	// 不读取的订阅者：完成事件在此被丢弃，FeedWithResult 的结果不受影响
+	stream.Subscribe(context.Background(), &realtimetts.EventConfig{BufferSize: 1, Overflow: realtimetts.DropNewest})
+
+	started := make(chan struct{}, 1)
+	callbacks := realtimetts.NewCallbacks()
+	callbacks.OnAudioStreamStart = func() {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+	}
+	stream.SetCallbacks(callbacks)
+
+	first, err := stream.FeedWithResult(context.Background(), "hello world.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.PlayAsync()
+	if result := wait(t, first, "话语结果"); result.Err != nil || result.Text != "hello world." {
+		t.Fatalf("话语结果不正确: %+v", result)
+	}
+
+	// 暂停后输入的文本被 Stop 取消或丢弃，同样收到结果
+	if err := stream.Feed("good morning."); err != nil {
+		t.Fatal(err)
+	}
+	wait(t, started, "开始播放")
+	if err := stream.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	var pending []<-chan realtimetts.UtteranceResult
+	for _, text := range []string{"one.", "two."} {
+		result, err := stream.FeedWithResult(context.Background(), text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending = append(pending, result)
+	}
+	if err := stream.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range pending {
+		if result := wait(t, ch, "话语结果"); !errors.Is(result.Err, context.Canceled) {
+			t.Fatalf("停止后的话语应以取消结束: %+v", result)
+		}
+	}
+}
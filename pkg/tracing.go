@@ -0,0 +1,67 @@
+package realtimetts
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName 本库创建追踪 Span 时使用的 Tracer 名
+const TracerName = "realtimetts"
+
+// 追踪 Span 的属性名
+const (
+	AttrUtteranceID = attribute.Key("tts.utterance_id")
+	AttrSentenceID  = attribute.Key("tts.sentence_id")
+	AttrEngine      = attribute.Key("tts.engine")
+	AttrCharacters  = attribute.Key("tts.characters")
+	AttrSentences   = attribute.Key("tts.sentences")
+	AttrBytes       = attribute.Key("tts.bytes")
+	AttrAttempt     = attribute.Key("tts.attempt")
+	AttrLatencyMs   = attribute.Key("tts.first_chunk_latency_ms")
+)
+
+// SetTracerProvider 设置追踪使用的 TracerProvider，传入 nil 表示使用 otel 全局的 TracerProvider
+// 未配置 otel 时全局的 TracerProvider 不记录任何 Span
+//
+// 每个话语一个 tts.utterance Span，其父 Span 取自 FeedContext 传入的上下文；
+// 其下有每句的 tts.sentence、每次引擎尝试的 tts.synthesize（引擎的 HTTP 请求 Span 挂在其下）、
+// 从首帧入队到开始播放的 tts.buffering，以及从开始播放到播放结束的 tts.playback
+func (tts *TextToAudioStream) SetTracerProvider(provider trace.TracerProvider) {
+	tts.mu.Lock()
+	defer tts.mu.Unlock()
+	tts.tracerProvider = provider
+}
+
+// tracer 返回当前的 Tracer
+func (tts *TextToAudioStream) tracer() trace.Tracer {
+	tts.mu.RLock()
+	defer tts.mu.RUnlock()
+	return tts.tracerLocked()
+}
+
+// tracerLocked 返回当前的 Tracer，调用方需持有 tts.mu
+func (tts *TextToAudioStream) tracerLocked() trace.Tracer {
+	provider := tts.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(TracerName)
+}
+
+// endSpan 结束 span，err 非 nil 时记录错误状态
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		// 错误信息可能含有机密，记录脱敏后的文本
+		message := Redact(err.Error())
+		span.RecordError(errors.New(message))
+		span.SetStatus(codes.Error, message)
+	}
+	span.End()
+}
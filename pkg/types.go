@@ -2,6 +2,9 @@ package realtimetts
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -61,6 +64,17 @@ func (f AudioFormat) String() string {
 	}
 }
 
+// UnmarshalText 按名称解析音频格式（不区分大小写），用于从配置文件读取
+func (f *AudioFormat) UnmarshalText(text []byte) error {
+	for _, candidate := range []AudioFormat{FormatWAV, FormatMP3, FormatMPEG, FormatOGG, FormatFLAC} {
+		if strings.EqualFold(candidate.String(), string(text)) {
+			*f = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, text)
+}
+
 // EngineInfo 引擎信息结构体
 type EngineInfo struct {
 	Name         string            // 引擎名称
@@ -77,7 +91,11 @@ type TTSEngine interface {
 	GetStreamInfo() *AudioConfiguration
 
 	// Synthesize 执行文本到音频的合成
-	Synthesize(ctx context.Context, text string) (<-chan []byte, error)
+	// 返回的帧由 TextToAudioStream 补充话语信息后按顺序写入 AudioBuffer 的帧队列，
+	// 引擎不应自行向 AudioBuffer 写入音频；输出 []byte 的引擎可用 AdaptByteEngine 包装。
+	// 合成中途失败时引擎必须通过 SynthesisStream.CloseWithError 报告错误，
+	// 而不是只关闭流，否则调用方无法切换引擎重试
+	Synthesize(ctx context.Context, text string) (*SynthesisStream, error)
 
 	// GetVoices 获取可用语音列表
 	GetVoices() ([]Voice, error)
@@ -89,6 +107,7 @@ type TTSEngine interface {
 	SetVoiceParameters(params map[string]interface{}) error
 
 	// SetAudioBuffer 设置音频缓冲管理器
+	// 引擎可据此查询缓冲时长以调整合成节奏
 	SetAudioBuffer(audioBuffer *AudioBuffer)
 
 	// GetEngineInfo 获取引擎信息
@@ -100,3 +119,41 @@ type TTSEngine interface {
 	// Close 关闭引擎
 	Close() error
 }
+
+// TextSupporter 只能合成部分文本的引擎可选实现的接口
+// TextToAudioStream 在选择引擎前询问，不支持的文本直接交给下一个引擎，不影响健康评分；
+// 未实现此接口的引擎也可以在 Synthesize 中返回 ErrTextNotSupported 拒绝文本
+type TextSupporter interface {
+	SupportsText(text string) bool
+}
+
+// Fingerprinter 引擎可选实现的接口，返回引擎内部影响合成结果的设置摘要
+// 例如构造时指定的语音、语速和模型；CachedEngine 把它计入缓存键，摘要中不应包含凭据
+type Fingerprinter interface {
+	Fingerprint() string
+}
+
+// FingerprintOf 把设置序列化为稳定的摘要字符串，供引擎实现 Fingerprinter
+func FingerprintOf(settings interface{}) string {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Sprintf("%v", settings)
+	}
+	return string(data)
+}
+
+// AudioOutput 音频输出接口
+// 默认实现为基于 PortAudio 的 AudioStream；测试或没有声卡的环境可替换为 NullOutput
+type AudioOutput interface {
+	OpenStream() error
+	StartStream() error
+	StopStream() error
+	CloseStream() error
+
+	// WriteAudioData 写入与播放格式一致的 PCM 数据
+	WriteAudioData(data []byte) error
+
+	SetVolume(volume float64) error
+	GetVolume() float64
+	SetMuted(muted bool) error
+}
@@ -0,0 +1,207 @@
+package realtimetts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WAV 格式标签
+const (
+	wavFormatPCM        = 0x0001
+	wavFormatExtensible = 0xFFFE
+
+	// wavMaxFormatSize fmt 块长度上限，WAVE_FORMAT_EXTENSIBLE 也只有 40 字节；
+	// 长度来自不可信的文件头，不能按其直接分配内存
+	wavMaxFormatSize = 64
+)
+
+// DecodeWAV 读取 RIFF/WAVE 数据，返回 PCM 数据及其格式
+// 支持 8/16/24/32 位整数 PCM（含 WAVE_FORMAT_EXTENSIBLE），未知的块会被跳过
+func DecodeWAV(r io.Reader) ([]byte, *AudioConfiguration, error) {
+	format, size, err := ReadWAVHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 流式写出、长度未知的 WAV 读到 EOF 为止；
+	// 头中记录的长度不可信，按实际读到的数据分配内存而不是预先分配
+	if size != wavUnknownSize {
+		r = io.LimitReader(r, size)
+	}
+	data, err := io.ReadAll(r)
+	n := len(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取WAV数据失败: %w", err)
+	}
+	// 流式写出的文件可能在头中记录了错误的长度，按实际读取的整帧截断
+	data = data[:n-n%format.GetBytesPerFrame()]
+	return data, format, nil
+}
+
+// ReadWAVHeader 读取 WAV 头直到 data 块开始，返回格式和头中记录的数据长度
+// 之后从 r 读取的即为 PCM 数据；流式输出的 WAV 记录的长度可能不准确，应读到 EOF 为止
+func ReadWAVHeader(r io.Reader) (*AudioConfiguration, int64, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, fmt.Errorf("读取WAV头失败: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("%w: 不是WAV文件", ErrUnsupportedFormat)
+	}
+
+	var format *AudioConfiguration
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, 0, fmt.Errorf("读取WAV块失败: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size > wavMaxFormatSize {
+				return nil, 0, fmt.Errorf("%w: fmt 块过大 (%d 字节)", ErrUnsupportedFormat, size)
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, 0, fmt.Errorf("读取WAV格式块失败: %w", err)
+			}
+			parsed, err := parseWAVFormat(body)
+			if err != nil {
+				return nil, 0, err
+			}
+			format = parsed
+
+		case "data":
+			if format == nil {
+				return nil, 0, fmt.Errorf("%w: data 块位于 fmt 块之前", ErrUnsupportedFormat)
+			}
+			return format, size, nil
+
+		default:
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, 0, fmt.Errorf("跳过WAV块 %q 失败: %w", id, err)
+			}
+		}
+
+		// 奇数长度的块有一个填充字节
+		if size%2 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				return nil, 0, fmt.Errorf("读取WAV块失败: %w", err)
+			}
+		}
+	}
+}
+
+// parseWAVFormat 解析 fmt 块
+func parseWAVFormat(body []byte) (*AudioConfiguration, error) {
+	if len(body) < 16 {
+		return nil, fmt.Errorf("%w: fmt 块过短", ErrUnsupportedFormat)
+	}
+
+	tag := binary.LittleEndian.Uint16(body[0:2])
+	if tag == wavFormatExtensible && len(body) >= 26 {
+		// 子格式 GUID 的前两个字节为实际的格式标签
+		tag = binary.LittleEndian.Uint16(body[24:26])
+	}
+	if tag != wavFormatPCM {
+		return nil, fmt.Errorf("%w: WAV格式标签 0x%04X", ErrUnsupportedFormat, tag)
+	}
+
+	format := &AudioConfiguration{
+		Format:        FormatWAV,
+		Channels:      int(binary.LittleEndian.Uint16(body[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(body[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(body[14:16])),
+		Volume:        1.0,
+	}
+	if err := validatePCMFormat(format); err != nil {
+		return nil, err
+	}
+	return format, nil
+}
+
+// EncodeWAV 将 PCM 数据写为 RIFF/WAVE 格式
+func EncodeWAV(w io.Writer, data []byte, format *AudioConfiguration) error {
+	if err := validatePCMFormat(format); err != nil {
+		return err
+	}
+	if _, err := w.Write(wavHeader(format, uint32(len(data)))); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// wavUnknownSize 流式输出时头中记录的未知长度
+const wavUnknownSize = 0xFFFFFFFF
+
+// wavHeader 返回 44 字节的 PCM WAV 头，dataSize 为 wavUnknownSize 时 RIFF 长度同样记为未知
+func wavHeader(format *AudioConfiguration, dataSize uint32) []byte {
+	riffSize := uint32(wavUnknownSize)
+	if dataSize != wavUnknownSize {
+		riffSize = 36 + dataSize
+	}
+
+	blockAlign := format.Channels * format.BitsPerSample / 8
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], riffSize)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], wavFormatPCM)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(format.Channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(format.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(format.SampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(format.BitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], dataSize)
+	return header
+}
+
+// WAVWriter 边合成边写出 WAV 的写入器
+// 总长度未知，头中的长度记为 0xFFFFFFFF（ReadWAVHeader 和常见播放器按读到 EOF 处理）；
+// 底层是 io.WriteSeeker（如文件）时 Close 回填实际长度
+type WAVWriter struct {
+	w       io.Writer
+	format  *AudioConfiguration
+	written int64
+}
+
+// NewWAVWriter 创建 WAV 写入器并立即写出头部
+func NewWAVWriter(w io.Writer, format *AudioConfiguration) (*WAVWriter, error) {
+	if err := validatePCMFormat(format); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(wavHeader(format, wavUnknownSize)); err != nil {
+		return nil, err
+	}
+	return &WAVWriter{w: w, format: format}, nil
+}
+
+// Write 写入与 format 一致的 PCM 数据
+func (ww *WAVWriter) Write(data []byte) (int, error) {
+	n, err := ww.w.Write(data)
+	ww.written += int64(n)
+	return n, err
+}
+
+// Close 结束写入，底层支持 Seek 时回填头中的长度，不关闭底层写入器
+func (ww *WAVWriter) Close() error {
+	seeker, ok := ww.w.(io.WriteSeeker)
+	if !ok || ww.written > wavUnknownSize-36 {
+		return nil
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := seeker.Write(wavHeader(ww.format, uint32(ww.written))); err != nil {
+		return err
+	}
+	_, err := seeker.Seek(0, io.SeekEnd)
+	return err
+}
@@ -0,0 +1,33 @@
+package realtimetts_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	realtimetts "realtimetts/pkg"
+)
+
+func TestDecodeWAVTrustsActualDataOverHeader(t *testing.T) {
+	format := &realtimetts.AudioConfiguration{SampleRate: 8000, Channels: 1, BitsPerSample: 16}
+	pcm := bytes.Repeat([]byte{1, 2}, 100)
+	var buf bytes.Buffer
+	if err := realtimetts.EncodeWAV(&buf, pcm, format); err != nil {
+		t.Fatal(err)
+	}
+
+	// data 块声明接近 4 GiB，实际只有 200 字节
+	wav := buf.Bytes()
+	binary.LittleEndian.PutUint32(wav[40:44], 0xFFFFFFF0)
+	data, decoded, err := realtimetts.DecodeWAV(bytes.NewReader(wav))
+	if err != nil || !bytes.Equal(data, pcm) || decoded.SampleRate != 8000 {
+		t.Fatalf("应按实际数据解码: %d 字节, %v", len(data), err)
+	}
+
+	// fmt 块声明的长度超过上限
+	binary.LittleEndian.PutUint32(wav[16:20], 0xFFFFFFF0)
+	if _, _, err := realtimetts.DecodeWAV(bytes.NewReader(wav)); !errors.Is(err, realtimetts.ErrUnsupportedFormat) {
+		t.Fatalf("过大的 fmt 块应被拒绝: %v", err)
+	}
+}
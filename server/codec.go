@@ -0,0 +1,53 @@
+package server
+
+import (
+	"io"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// 内置的响应格式
+const (
+	FormatWAV  = "wav"
+	FormatPCM  = "pcm"
+	FormatOpus = "opus"
+)
+
+// EncoderFactory 为一次响应创建编码器，写入的是 format 格式的 PCM 数据
+// 返回的编码器在合成结束后被关闭，Close 应写出缓冲的数据但不关闭 w
+type EncoderFactory func(w io.Writer, format *realtimetts.AudioConfiguration) (io.WriteCloser, error)
+
+// Codec 响应格式
+type Codec struct {
+	ContentType string
+	New         EncoderFactory
+}
+
+// defaultCodecs 返回内置的编码格式
+// Opus 需要 libopus 等外部编码器，未内置，可通过 Config.Codecs 以 FormatOpus 注册
+func defaultCodecs() map[string]Codec {
+	return map[string]Codec{
+		FormatWAV: {
+			ContentType: "audio/wav",
+			New: func(w io.Writer, format *realtimetts.AudioConfiguration) (io.WriteCloser, error) {
+				return realtimetts.NewWAVWriter(w, format)
+			},
+		},
+		FormatPCM: {
+			ContentType: "audio/pcm",
+			New: func(w io.Writer, format *realtimetts.AudioConfiguration) (io.WriteCloser, error) {
+				return nopWriteCloser{w}, nil
+			},
+		},
+	}
+}
+
+// nopWriteCloser 原样写出 PCM
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close 实现 io.Closer
+func (nopWriteCloser) Close() error {
+	return nil
+}
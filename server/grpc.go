@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	realtimetts "realtimetts/pkg"
+	"realtimetts/ttspb"
+)
+
+// RegisterGRPC 在 gRPC 服务上注册 TTSService，与 HTTP 接口共用引擎链和会话实现
+// 服务关闭后 Synthesize 返回 Unavailable，进行中的会话随之结束
+func (s *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	ttspb.RegisterTTSServiceServer(registrar, &grpcService{server: s})
+}
+
+// grpcService 实现 ttspb.TTSServiceServer
+type grpcService struct {
+	ttspb.UnimplementedTTSServiceServer
+	server *Server
+}
+
+// Synthesize 实现流式合成会话
+// 接收在独立协程中进行，当前协程按顺序发送会话的音频和消息；客户端关闭发送方向后，
+// 会话合成剩余文本，等所有话语的 done 发出后正常结束流
+func (g *grpcService) Synthesize(stream ttspb.TTSService_SynthesizeServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	id := atomic.AddUint64(&g.server.requestSeq, 1)
+	ss, err := g.server.openSession(first.GetStart().GetVoice(), g.server.logger.With("session_id", id))
+	if err != nil {
+		return grpcError(err)
+	}
+	defer ss.close()
+	defer ss.cancel()
+
+	if err := ss.start(); err != nil {
+		return grpcError(err)
+	}
+	if first.GetStart() == nil {
+		ss.receive(first)
+	}
+
+	received := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				ss.finish()
+				received <- nil
+				return
+			}
+			if err != nil {
+				received <- err
+				return
+			}
+			ss.receive(req)
+		}
+	}()
+
+	for {
+		select {
+		case message := <-ss.send:
+			if err := sendResponse(stream, ss, message); err != nil {
+				return err
+			}
+		case err := <-received:
+			if err != nil {
+				return err
+			}
+			// finish 返回时所有 done 都已排队，发完队列中剩余的内容即可结束
+			for {
+				select {
+				case message := <-ss.send:
+					if err := sendResponse(stream, ss, message); err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
+		case <-ss.ctx.Done():
+			return status.Error(codes.Unavailable, ErrServerClosed.Error())
+		}
+	}
+}
+
+// receive 把客户端请求转换为会话消息
+func (ss *session) receive(req *ttspb.SynthesizeRequest) {
+	switch r := req.Request.(type) {
+	case *ttspb.SynthesizeRequest_Text:
+		ss.handle(StreamMessage{Type: MessageText, Text: r.Text.GetText()})
+	case *ttspb.SynthesizeRequest_Control:
+		switch r.Control.GetAction() {
+		case ttspb.Control_ACTION_FLUSH:
+			ss.handle(StreamMessage{Type: MessageFlush})
+		case ttspb.Control_ACTION_INTERRUPT:
+			ss.handle(StreamMessage{Type: MessageInterrupt})
+		default:
+			ss.sendError(errors.New("未知的控制动作: " + r.Control.GetAction().String()))
+		}
+	case *ttspb.SynthesizeRequest_Start:
+		ss.sendError(errors.New("start 只能是第一条消息"))
+	default:
+		ss.sendError(errors.New("空的请求"))
+	}
+}
+
+// sendResponse 把会话待发送的内容转换为响应发出，跳过被打断的轮次的音频
+func sendResponse(stream ttspb.TTSService_SynthesizeServer, ss *session, message outgoing) error {
+	if ss.stale(message) {
+		return nil
+	}
+	if message.message == nil {
+		return stream.Send(&ttspb.SynthesizeResponse{Response: &ttspb.SynthesizeResponse_Audio{
+			Audio: &ttspb.AudioFrame{Data: message.audio},
+		}})
+	}
+
+	m := message.message
+	resp := &ttspb.SynthesizeResponse{}
+	switch m.Type {
+	case MessageReady:
+		resp.Response = &ttspb.SynthesizeResponse_Ready{Ready: &ttspb.Ready{Format: audioFormat(ss.format), Engine: m.Engine}}
+	case MessageSentence:
+		resp.Response = &ttspb.SynthesizeResponse_Sentence{Sentence: &ttspb.Sentence{Text: m.Text}}
+	case MessageWord:
+		resp.Response = &ttspb.SynthesizeResponse_Word{Word: &ttspb.WordTiming{Word: m.Word, StartMs: m.StartMs, EndMs: m.EndMs}}
+	case MessageDone:
+		resp.Response = &ttspb.SynthesizeResponse_Done{Done: &ttspb.UtteranceDone{
+			UtteranceId: m.UtteranceID,
+			Text:        m.Text,
+			DurationMs:  m.DurationMs,
+			Interrupted: m.Interrupted,
+			Error:       m.Error,
+		}}
+	case MessageInterrupted:
+		resp.Response = &ttspb.SynthesizeResponse_Interrupted{Interrupted: &ttspb.Interrupted{}}
+	case MessageError:
+		resp.Response = &ttspb.SynthesizeResponse_Error{Error: &ttspb.Error{Message: m.Error}}
+	default:
+		return nil
+	}
+	return stream.Send(resp)
+}
+
+// ListVoices 实现 TTSService.ListVoices
+func (g *grpcService) ListVoices(ctx context.Context, req *ttspb.ListVoicesRequest) (*ttspb.ListVoicesResponse, error) {
+	voices, err := g.server.voices()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &ttspb.ListVoicesResponse{}
+	for _, voice := range voices {
+		resp.Voices = append(resp.Voices, &ttspb.Voice{
+			Engine: voice.Engine, Id: voice.ID, Name: voice.Name, Language: voice.Language,
+			Gender: voice.Gender, Description: voice.Description,
+		})
+	}
+	return resp, nil
+}
+
+// GetEngineInfo 实现 TTSService.GetEngineInfo，不返回可能带有密钥的引擎配置
+func (g *grpcService) GetEngineInfo(ctx context.Context, req *ttspb.GetEngineInfoRequest) (*ttspb.GetEngineInfoResponse, error) {
+	chain, err := g.server.chain("")
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &ttspb.GetEngineInfoResponse{}
+	for i, health := range chain.health() {
+		engine := chain.failover.Engine(i)
+		info := engine.GetEngineInfo()
+		resp.Engines = append(resp.Engines, &ttspb.EngineInfo{
+			Name:                health.Name,
+			Version:             info.Version,
+			Description:         info.Description,
+			Capabilities:        info.Capabilities,
+			Format:              audioFormat(engine.GetStreamInfo()),
+			Score:               health.Score,
+			ErrorRate:           health.ErrorRate,
+			AverageLatencyMs:    health.AverageLatencyMs,
+			ConsecutiveFailures: int32(health.ConsecutiveFailures),
+			LastError:           health.LastError,
+		})
+	}
+	return resp, nil
+}
+
+// audioFormat 转换音频格式，format 为空时返回 nil
+func audioFormat(format *realtimetts.AudioConfiguration) *ttspb.AudioFormat {
+	if format == nil {
+		return nil
+	}
+	return &ttspb.AudioFormat{
+		SampleRate:    int32(format.SampleRate),
+		Channels:      int32(format.Channels),
+		BitsPerSample: int32(format.BitsPerSample),
+	}
+}
+
+// grpcError 把服务错误转换为 gRPC 状态，错误信息经过脱敏
+func grpcError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ErrUnknownVoice), errors.Is(err, realtimetts.ErrEngineInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrServerClosed):
+		code = codes.Unavailable
+	}
+	return status.Error(code, realtimetts.Redact(err.Error()))
+}
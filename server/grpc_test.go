@@ -0,0 +1,255 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	realtimetts "realtimetts/pkg"
+	"realtimetts/server"
+	"realtimetts/ttspb"
+)
+
+// newGRPCClient 在内存连接上启动 gRPC 服务，未设置引擎链时使用测试音引擎
+func newGRPCClient(t *testing.T, config server.Config) ttspb.TTSServiceClient {
+	t.Helper()
+	if len(config.Chain.Engines) == 0 {
+		config.Chain = toneChain(t, `{"sample_rate": 8000, "word_duration": "20ms"}`)
+	}
+	s, err := server.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	s.RegisterGRPC(grpcServer)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		s.Close()
+		grpcServer.Stop()
+	})
+	return ttspb.NewTTSServiceClient(conn)
+}
+
+// openSynthesize 打开流式合成会话并读取 Ready
+func openSynthesize(t *testing.T, client ttspb.TTSServiceClient, first *ttspb.SynthesizeRequest) (ttspb.TTSService_SynthesizeClient, *ttspb.Ready) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	stream, err := client.Synthesize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(first); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetReady() == nil {
+		t.Fatalf("第一条响应应为 Ready: %v", resp)
+	}
+	return stream, resp.GetReady()
+}
+
+func textRequest(text string) *ttspb.SynthesizeRequest {
+	return &ttspb.SynthesizeRequest{Request: &ttspb.SynthesizeRequest_Text{Text: &ttspb.TextChunk{Text: text}}}
+}
+
+func controlRequest(action ttspb.Control_Action) *ttspb.SynthesizeRequest {
+	return &ttspb.SynthesizeRequest{Request: &ttspb.SynthesizeRequest_Control{Control: &ttspb.Control{Action: action}}}
+}
+
+func TestGRPCSynthesize(t *testing.T) {
+	client := newGRPCClient(t, server.Config{})
+	stream, ready := openSynthesize(t, client, &ttspb.SynthesizeRequest{
+		Request: &ttspb.SynthesizeRequest_Start{Start: &ttspb.StartSession{}},
+	})
+	format := realtimetts.DefaultAudioConfig()
+	if ready.GetFormat().GetSampleRate() != int32(format.SampleRate) || ready.GetEngine() != "tone" {
+		t.Fatalf("Ready 不正确: %v", ready)
+	}
+
+	// 关闭发送方向后合成未凑成整句的剩余文本，所有话语结束后流正常结束
+	for _, text := range []string{"hello ", "world. and"} {
+		if err := stream.Send(textRequest(text)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var audio int
+	var words []*ttspb.WordTiming
+	var done []*ttspb.UtteranceDone
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch r := resp.Response.(type) {
+		case *ttspb.SynthesizeResponse_Audio:
+			audio += len(r.Audio.GetData())
+		case *ttspb.SynthesizeResponse_Word:
+			words = append(words, r.Word)
+		case *ttspb.SynthesizeResponse_Done:
+			done = append(done, r.Done)
+		case *ttspb.SynthesizeResponse_Error:
+			t.Fatalf("不应出错: %s", r.Error.GetMessage())
+		}
+	}
+
+	if len(done) != 2 || done[0].GetText() != "hello world." || done[1].GetText() != " and" {
+		t.Fatalf("Done 不正确: %v", done)
+	}
+	if len(words) != 3 || words[0].GetWord() != "hello" || words[1].GetStartMs() <= words[0].GetStartMs() {
+		t.Fatalf("WordTiming 不正确: %v", words)
+	}
+	duration := time.Duration(audio) * time.Second / time.Duration(format.GetBytesPerSecond())
+	expected := time.Duration(done[0].GetDurationMs()+done[1].GetDurationMs()) * time.Millisecond
+	if diff := duration - expected; diff < 0 || diff > 2*time.Millisecond {
+		t.Fatalf("音频 %v 与 Done 的时长 %v 不一致", duration, expected)
+	}
+}
+
+func TestGRPCSynthesizeInterrupt(t *testing.T) {
+	client := newGRPCClient(t, server.Config{
+		Chain: toneChain(t, `{"word_duration": "20ms", "chunk_interval": "20ms", "chunk_size": 320}`),
+	})
+	// 第一条消息不是 StartSession 时使用默认语音并照常处理
+	stream, _ := openSynthesize(t, client, textRequest(strings.Repeat("word ", 50)+"."))
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetWord() != nil {
+			break
+		}
+	}
+
+	if err := stream.Send(controlRequest(ttspb.Control_ACTION_INTERRUPT)); err != nil {
+		t.Fatal(err)
+	}
+	var interrupted, cancelled bool
+	for !interrupted || !cancelled {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case resp.GetInterrupted() != nil:
+			interrupted = true
+		case resp.GetDone() != nil:
+			if !resp.GetDone().GetInterrupted() {
+				t.Fatalf("被打断的话语不应正常完成: %v", resp)
+			}
+			cancelled = true
+		}
+	}
+
+	// 打断后会话继续可用
+	if err := stream.Send(textRequest("again.")); err != nil {
+		t.Fatal(err)
+	}
+	stream.CloseSend()
+	var last *ttspb.UtteranceDone
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetDone() != nil {
+			last = resp.GetDone()
+		}
+	}
+	if last.GetText() != "again." || last.GetInterrupted() {
+		t.Fatalf("打断后的话语应正常完成: %v", last)
+	}
+}
+
+func TestGRPCSynthesizeErrors(t *testing.T) {
+	client := newGRPCClient(t, server.Config{})
+
+	stream, err := client.Synthesize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Send(&ttspb.SynthesizeRequest{Request: &ttspb.SynthesizeRequest_Start{Start: &ttspb.StartSession{Voice: "nope"}}})
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("未知语音应返回 InvalidArgument: %v", err)
+	}
+
+	// 重复的 StartSession 和未知的控制动作只产生错误响应，会话继续
+	stream, _ = openSynthesize(t, client, &ttspb.SynthesizeRequest{
+		Request: &ttspb.SynthesizeRequest_Start{Start: &ttspb.StartSession{Voice: "beep"}},
+	})
+	stream.Send(&ttspb.SynthesizeRequest{Request: &ttspb.SynthesizeRequest_Start{Start: &ttspb.StartSession{}}})
+	stream.Send(controlRequest(ttspb.Control_ACTION_UNSPECIFIED))
+	stream.CloseSend()
+	var errs int
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetError() != nil {
+			errs++
+		}
+	}
+	if errs != 2 {
+		t.Fatalf("期望 2 条错误响应, 实际 %d", errs)
+	}
+}
+
+func TestGRPCVoicesAndEngineInfo(t *testing.T) {
+	client := newGRPCClient(t, server.Config{})
+	ctx := context.Background()
+
+	voices, err := client.ListVoices(ctx, &ttspb.ListVoicesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voices.GetVoices()) != 3 || voices.GetVoices()[0].GetEngine() != "tone" {
+		t.Fatalf("语音列表不正确: %v", voices)
+	}
+
+	info, err := client.GetEngineInfo(ctx, &ttspb.GetEngineInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.GetEngines()) != 1 || info.GetEngines()[0].GetName() != "tone" || info.GetEngines()[0].GetFormat().GetSampleRate() != 8000 {
+		t.Fatalf("引擎信息不正确: %v", info)
+	}
+}
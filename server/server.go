@@ -0,0 +1,598 @@
+// Package server 通过 HTTP 提供语音合成服务，供非 Go 服务使用本库的引擎链
+//
+//	POST /v1/synthesize  {"text": "...", "voice": "...", "format": "wav|pcm"}，以分块传输边合成边返回音频
+//	GET  /v1/stream      WebSocket 会话，边输入文本边接收音频和事件，见 StreamMessage
+//	GET  /v1/voices      各引擎 GetVoices 返回的语音
+//	GET  /healthz        各引擎的健康状态
+//
+// RegisterGRPC 以 gRPC 提供同样的功能，服务定义见 ttspb 包
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"realtimetts/cache"
+	realtimetts "realtimetts/pkg"
+)
+
+// 服务错误
+var (
+	ErrUnknownVoice  = errors.New("没有引擎支持该语音")
+	ErrUnknownFormat = errors.New("不支持的音频格式")
+	ErrServerClosed  = errors.New("服务已关闭")
+)
+
+// LogKeyFormat 日志中响应格式的字段名
+const LogKeyFormat = "format"
+
+// Config 服务配置
+type Config struct {
+	Chain         realtimetts.EngineChainConfig // 引擎链，每种语音各构造一份
+	Audio         *realtimetts.AudioConfiguration
+	Failover      *realtimetts.FailoverConfig
+	Cache         cache.Store                // 合成结果缓存，各语音的引擎共用；为空时不缓存
+	Codecs        map[string]Codec           // 附加或替换的响应格式，例如以 FormatOpus 注册 Opus 编码器
+	RecordDir     string                     // 非空时每次合成的音频另存为该目录下的 WAV 文件
+	CheckOrigin   func(r *http.Request) bool // WebSocket 会话的来源检查，为空时只允许同源
+	Voices        []string                   // GetVoices 未列出但允许请求的语音ID
+	MaxTextLength int                        // 单次请求的最大字符数
+	Logger        *slog.Logger               // 诊断日志，写出前自动脱敏
+}
+
+// DefaultConfig 返回默认配置，Chain 需由调用方设置
+func DefaultConfig() Config {
+	return Config{
+		Audio:         realtimetts.DefaultAudioConfig(),
+		Failover:      realtimetts.DefaultFailoverConfig(),
+		MaxTextLength: 5000,
+	}
+}
+
+// Server 语音合成服务
+type Server struct {
+	config Config
+	codecs map[string]Codec
+	logger *slog.Logger
+	mux    *http.ServeMux
+
+	mu       sync.Mutex
+	chains   map[string]*engineChain // 按语音ID，"" 为引擎链配置中的默认语音
+	sessions map[*session]struct{}
+	closed   bool
+
+	requestSeq uint64
+}
+
+// engineChain 一种语音的引擎链
+type engineChain struct {
+	engines  []realtimetts.TTSEngine
+	failover *realtimetts.FailoverManager
+}
+
+// SynthesizeRequest POST /v1/synthesize 的请求体
+type SynthesizeRequest struct {
+	Text   string `json:"text"`
+	Voice  string `json:"voice"`  // 语音ID，为空时使用引擎链配置中的语音
+	Format string `json:"format"` // wav（默认）、pcm 或已注册的格式
+}
+
+// New 创建服务并构造默认语音的引擎链，配置中未设置的字段使用 DefaultConfig 的值
+func New(config Config) (*Server, error) {
+	defaults := DefaultConfig()
+	if config.Audio == nil {
+		config.Audio = defaults.Audio
+	}
+	if config.Failover == nil {
+		config.Failover = defaults.Failover
+	}
+	if config.MaxTextLength <= 0 {
+		config.MaxTextLength = defaults.MaxTextLength
+	}
+	if config.RecordDir != "" {
+		if err := os.MkdirAll(config.RecordDir, 0o755); err != nil {
+			return nil, fmt.Errorf("创建录音目录失败: %w", err)
+		}
+	}
+
+	codecs := defaultCodecs()
+	for name, codec := range config.Codecs {
+		codecs[name] = codec
+	}
+
+	s := &Server{
+		config:   config,
+		codecs:   codecs,
+		logger:   realtimetts.RedactingLogger(config.Logger),
+		chains:   make(map[string]*engineChain),
+		sessions: make(map[*session]struct{}),
+	}
+	if _, err := s.chain(""); err != nil {
+		return nil, err
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /v1/synthesize", s.handleSynthesize)
+	s.mux.HandleFunc("GET /v1/stream", s.handleStream)
+	s.mux.HandleFunc("GET /v1/voices", s.handleVoices)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	return s, nil
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close 结束所有流式会话并关闭所有引擎，之后的请求返回 503
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for ss := range s.sessions {
+		ss.cancel()
+	}
+	var errs []error
+	for _, chain := range s.chains {
+		for _, engine := range chain.engines {
+			errs = append(errs, engine.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// chain 返回语音对应的引擎链，首次使用时构造
+func (s *Server) chain(voice string) (*engineChain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrServerClosed
+	}
+	if chain, ok := s.chains[voice]; ok {
+		return chain, nil
+	}
+
+	engines, err := s.buildEngines(voice)
+	if err != nil {
+		return nil, err
+	}
+	chain := &engineChain{engines: engines, failover: realtimetts.NewFailoverManager(engines, s.config.Failover)}
+	s.chains[voice] = chain
+	return chain, nil
+}
+
+// buildEngines 按引擎链配置构造一组新的引擎并设置语音
+// 引擎的语音是共享状态，因此每种语音（以及每个流式会话）使用独立的引擎实例。
+// 语音必须由某个引擎的 GetVoices 列出或在 Config.Voices 中，否则返回 ErrUnknownVoice：
+// 多数引擎的 SetVoice 接受任意字符串，不加限制时客户端可以用不同的语音ID让服务无限构造引擎链。
+// 列出了语音但不含该语音的引擎，以及 SetVoice 失败的引擎被关闭，不在返回的引擎链中
+func (s *Server) buildEngines(voice string) ([]realtimetts.TTSEngine, error) {
+	built, err := realtimetts.BuildEngines(s.config.Chain)
+	if err != nil {
+		return nil, err
+	}
+	allowed := voice == "" || slices.Contains(s.config.Voices, voice)
+	engines := make([]realtimetts.TTSEngine, 0, len(built))
+	for _, engine := range built {
+		if s.config.Cache != nil {
+			engine = cache.NewCachedEngine(engine, s.config.Cache)
+		}
+		if setter, ok := engine.(realtimetts.LoggerSetter); ok {
+			setter.SetLogger(s.logger.With(realtimetts.LogKeyEngine, engine.GetEngineInfo().Name))
+		}
+		if voice != "" {
+			listed, accepted := listsVoice(engine, voice)
+			allowed = allowed || listed
+			if !accepted && !slices.Contains(s.config.Voices, voice) {
+				engine.Close()
+				continue
+			}
+			if err := engine.SetVoice(realtimetts.Voice{ID: voice}); err != nil {
+				engine.Close()
+				continue
+			}
+		}
+		engines = append(engines, engine)
+	}
+	if !allowed || len(engines) == 0 {
+		closeEngines(engines)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVoice, voice)
+	}
+	return engines, nil
+}
+
+// listsVoice 检查引擎的 GetVoices 是否列出了该语音
+// 未列出任何语音的引擎（如预录音频）由 SetVoice 决定是否接受
+func listsVoice(engine realtimetts.TTSEngine, voice string) (listed, accepted bool) {
+	voices, err := engine.GetVoices()
+	if err != nil || len(voices) == 0 {
+		return false, true
+	}
+	for _, v := range voices {
+		if v.ID == voice {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// closeEngines 关闭未交给引擎链或会话的引擎
+func closeEngines(engines []realtimetts.TTSEngine) {
+	for _, engine := range engines {
+		engine.Close()
+	}
+}
+
+// handleSynthesize 处理 POST /v1/synthesize
+// 在第一帧音频合成后才写出响应头，此前所有引擎都失败时返回 JSON 错误；
+// 开始输出后的失败无法再改为错误响应，此时中断连接，客户端会读到不完整的分块响应
+func (s *Server) handleSynthesize(w http.ResponseWriter, r *http.Request) {
+	var req SynthesizeRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("无效的请求体: %w", err))
+		return
+	}
+	if req.Text == "" {
+		writeError(w, http.StatusBadRequest, errors.New("text 不能为空"))
+		return
+	}
+	if n := len([]rune(req.Text)); n > s.config.MaxTextLength {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("文本长度 %d 超过上限 %d", n, s.config.MaxTextLength))
+		return
+	}
+	if req.Format == "" {
+		req.Format = FormatWAV
+	}
+	codec, ok := s.codecs[req.Format]
+	if !ok {
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("%w: %s，可选 %v", ErrUnknownFormat, req.Format, s.formats()))
+		return
+	}
+
+	chain, err := s.chain(req.Voice)
+	switch {
+	case errors.Is(err, ErrUnknownVoice):
+		writeError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, ErrServerClosed):
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	id := atomic.AddUint64(&s.requestSeq, 1)
+	logger := s.logger.With("request_id", id)
+	out := &responseWriter{server: s, w: w, codec: codec, format: s.config.Audio, id: id}
+	defer out.close(logger)
+
+	start := time.Now()
+	err = s.synthesize(r.Context(), chain, req.Text, out.write, logger)
+	switch {
+	case err == nil:
+		logger.Info("合成完成", LogKeyFormat, req.Format, realtimetts.LogKeyBytes, out.bytes, "duration", time.Since(start))
+	case !out.started:
+		status := http.StatusBadGateway
+		if r.Context().Err() != nil {
+			status = http.StatusServiceUnavailable
+		} else if errors.Is(err, realtimetts.ErrEngineInvalidInput) {
+			status = http.StatusBadRequest
+		}
+		logger.Warn("合成失败", realtimetts.LogKeyError, err)
+		writeError(w, status, err)
+	default:
+		logger.Warn("合成中途失败，中断响应", realtimetts.LogKeyError, err, realtimetts.LogKeyBytes, out.bytes)
+		out.close(logger)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// synthesize 按故障切换顺序选择引擎合成 text，把转换为输出格式的 PCM 交给 write
+// 引擎在输出任何音频前失败时换下一个引擎；已输出音频后失败则返回错误，避免重复的音频；
+// 引擎拒绝输入时直接返回，换引擎也无法合成
+func (s *Server) synthesize(ctx context.Context, chain *engineChain, text string, write func([]byte) error, logger *slog.Logger) error {
+	unsupported := make(map[int]bool)
+	for i := 0; i < chain.failover.Len(); i++ {
+		if supporter, ok := chain.failover.Engine(i).(realtimetts.TextSupporter); ok && !supporter.SupportsText(text) {
+			unsupported[i] = true
+		}
+	}
+
+	tried := make(map[int]bool)
+	var lastErr error
+	for attempt := 0; attempt < chain.failover.MaxAttempts(); {
+		index, reason, ok := chain.failover.SelectFor(tried, unsupported)
+		if !ok {
+			break
+		}
+		tried[index] = true
+		engine := chain.failover.Engine(index)
+		name := engine.GetEngineInfo().Name
+		if reason != "" {
+			logger.Info("切换引擎", realtimetts.LogKeyEngine, name, "reason", reason)
+		}
+
+		latency, wrote, err := s.synthesizeWith(ctx, engine, text, write)
+		if err == nil {
+			chain.failover.ReportSuccess(index, latency)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, realtimetts.ErrTextNotSupported) && !wrote {
+			unsupported[index] = true
+			continue
+		}
+		attempt++
+		chain.failover.ReportFailure(index, err)
+		logger.Warn("引擎合成失败", realtimetts.LogKeyEngine, name, realtimetts.LogKeyError, err)
+		if wrote || errors.Is(err, realtimetts.ErrEngineInvalidInput) {
+			return err
+		}
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		if len(unsupported) > 0 {
+			return realtimetts.ErrTextNotSupported
+		}
+		return realtimetts.ErrNoEnginesAvailable
+	}
+	return fmt.Errorf("所有引擎都失败了: %w", lastErr)
+}
+
+// synthesizeWith 使用一个引擎合成，返回首帧延迟和是否已输出音频
+func (s *Server) synthesizeWith(ctx context.Context, engine realtimetts.TTSEngine, text string, write func([]byte) error) (latency time.Duration, wrote bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	stream, err := engine.Synthesize(ctx, text)
+	if err != nil {
+		return 0, false, err
+	}
+	for frame := range stream.Frames() {
+		if !frame.HasAudio() {
+			continue
+		}
+		format := frame.Format
+		if format == nil {
+			format = engine.GetStreamInfo()
+		}
+		data := frame.Data
+		if !realtimetts.SameFormat(format, s.config.Audio) {
+			if data, err = realtimetts.ConvertPCM(data, format, s.config.Audio); err != nil {
+				return latency, wrote, err
+			}
+		}
+		if !wrote {
+			latency = time.Since(start)
+		}
+		if err := write(data); err != nil {
+			return latency, true, err
+		}
+		wrote = true
+	}
+	return latency, wrote, stream.Err()
+}
+
+// responseWriter 在第一帧音频时写出响应头，并可同时录制到文件
+type responseWriter struct {
+	server *Server
+	w      http.ResponseWriter
+	codec  Codec
+	format *realtimetts.AudioConfiguration
+	id     uint64
+
+	started bool
+	closed  bool
+	bytes   int64
+	encoder io.WriteCloser
+	record  *os.File
+	wav     *realtimetts.WAVWriter
+}
+
+// write 写出一段 PCM，首次调用时写出响应头
+func (rw *responseWriter) write(data []byte) error {
+	if !rw.started {
+		if err := rw.start(); err != nil {
+			return err
+		}
+	}
+	if _, err := rw.encoder.Write(data); err != nil {
+		return err
+	}
+	if rw.wav != nil {
+		if _, err := rw.wav.Write(data); err != nil {
+			return err
+		}
+	}
+	rw.bytes += int64(len(data))
+	if flusher, ok := rw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// start 写出响应头并创建编码器和录音文件
+func (rw *responseWriter) start() error {
+	header := rw.w.Header()
+	header.Set("Content-Type", rw.codec.ContentType)
+	header.Set("X-Sample-Rate", fmt.Sprint(rw.format.SampleRate))
+	header.Set("X-Channels", fmt.Sprint(rw.format.Channels))
+	header.Set("X-Bits-Per-Sample", fmt.Sprint(rw.format.BitsPerSample))
+	rw.w.WriteHeader(http.StatusOK)
+	rw.started = true
+
+	encoder, err := rw.codec.New(rw.w, rw.format)
+	if err != nil {
+		return err
+	}
+	rw.encoder = encoder
+
+	if dir := rw.server.config.RecordDir; dir != "" {
+		name := filepath.Join(dir, fmt.Sprintf("%s-%d.wav", time.Now().Format("20060102-150405"), rw.id))
+		file, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("创建录音文件失败: %w", err)
+		}
+		rw.record = file
+		if rw.wav, err = realtimetts.NewWAVWriter(file, rw.format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close 结束编码器和录音文件，可重复调用
+func (rw *responseWriter) close(logger *slog.Logger) {
+	if rw.closed {
+		return
+	}
+	rw.closed = true
+	if rw.encoder != nil {
+		if err := rw.encoder.Close(); err != nil {
+			logger.Warn("结束编码失败", realtimetts.LogKeyError, err)
+		}
+	}
+	if rw.record != nil {
+		if err := errors.Join(rw.wav.Close(), rw.record.Close()); err != nil {
+			logger.Warn("保存录音失败", realtimetts.LogKeyError, err)
+		}
+	}
+}
+
+// voiceInfo GET /v1/voices 返回的语音
+type voiceInfo struct {
+	Engine      string `json:"engine"`
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Gender      string `json:"gender,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// handleVoices 处理 GET /v1/voices，列出默认引擎链中各引擎的语音
+// 个别引擎获取失败时跳过，全部失败时返回 502
+func (s *Server) handleVoices(w http.ResponseWriter, r *http.Request) {
+	voices, err := s.voices()
+	switch {
+	case errors.Is(err, ErrServerClosed):
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"voices": voices})
+}
+
+// voices 返回默认引擎链中各引擎的语音，个别引擎获取失败时跳过，全部失败时返回错误
+func (s *Server) voices() ([]voiceInfo, error) {
+	chain, err := s.chain("")
+	if err != nil {
+		return nil, err
+	}
+
+	voices := []voiceInfo{}
+	var errs []error
+	for _, engine := range chain.engines {
+		name := engine.GetEngineInfo().Name
+		list, err := engine.GetVoices()
+		if err != nil {
+			s.logger.Warn("获取语音列表失败", realtimetts.LogKeyEngine, name, realtimetts.LogKeyError, err)
+			errs = append(errs, err)
+			continue
+		}
+		for _, voice := range list {
+			voices = append(voices, voiceInfo{
+				Engine: name, ID: voice.ID, Name: voice.Name, Language: voice.Language,
+				Gender: voice.Gender, Description: voice.Description,
+			})
+		}
+	}
+	if len(errs) == len(chain.engines) {
+		return nil, errors.Join(errs...)
+	}
+	return voices, nil
+}
+
+// engineHealth /healthz 返回的引擎状态
+type engineHealth struct {
+	Name                string  `json:"name"`
+	Score               float64 `json:"score"`
+	ErrorRate           float64 `json:"error_rate"`
+	AverageLatencyMs    int64   `json:"average_latency_ms"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	LastError           string  `json:"last_error,omitempty"`
+}
+
+// handleHealth 处理 GET /healthz，返回默认引擎链各引擎的健康状态
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	chain, err := s.chain("")
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "engines": chain.health()})
+}
+
+// health 返回引擎链各引擎的健康状态，错误信息经过脱敏
+func (c *engineChain) health() []engineHealth {
+	engines := []engineHealth{}
+	for _, health := range c.failover.Health() {
+		entry := engineHealth{
+			Name:                health.Name,
+			Score:               health.Score,
+			ErrorRate:           health.ErrorRate,
+			AverageLatencyMs:    health.AverageLatency.Milliseconds(),
+			ConsecutiveFailures: health.ConsecutiveFailures,
+		}
+		if health.LastError != nil {
+			entry.LastError = realtimetts.Redact(health.LastError.Error())
+		}
+		engines = append(engines, entry)
+	}
+	return engines
+}
+
+// formats 返回支持的响应格式，按名称排序
+func (s *Server) formats() []string {
+	names := make([]string, 0, len(s.codecs))
+	for name := range s.codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeJSON 写出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError 写出 JSON 错误，错误信息经过脱敏
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": realtimetts.Redact(err.Error())})
+}
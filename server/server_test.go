@@ -0,0 +1,230 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"realtimetts/cache"
+	_ "realtimetts/engines"
+	realtimetts "realtimetts/pkg"
+	"realtimetts/server"
+)
+
+// toneChain 返回只有一个测试音引擎的引擎链，config 为引擎配置的 JSON
+func toneChain(t *testing.T, config string) realtimetts.EngineChainConfig {
+	t.Helper()
+	var chain realtimetts.EngineChainConfig
+	err := json.Unmarshal([]byte(`{"engines": [{"type": "tone", "name": "tone", "config": `+config+`}]}`), &chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+// newTestServer 启动服务，未设置引擎链时使用测试音引擎
+func newTestServer(t *testing.T, config server.Config) *httptest.Server {
+	t.Helper()
+	if len(config.Chain.Engines) == 0 {
+		config.Chain = toneChain(t, `{"sample_rate": 8000, "word_duration": "20ms"}`)
+	}
+	s, err := server.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+	return ts
+}
+
+// synthesize 请求合成，返回响应和响应体
+func synthesize(t *testing.T, ts *httptest.Server, req server.SynthesizeRequest) (*http.Response, []byte) {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	resp, err := http.Post(ts.URL+"/v1/synthesize", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+func TestServerSynthesize(t *testing.T) {
+	store := cache.NewMemoryStore(0, 0)
+	recordDir := t.TempDir()
+	ts := newTestServer(t, server.Config{Cache: store, RecordDir: recordDir})
+	format := realtimetts.DefaultAudioConfig()
+
+	resp, body := synthesize(t, ts, server.SynthesizeRequest{Text: "hello world"})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/wav" {
+		t.Fatalf("WAV 响应不正确: %d %s %s", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+	pcm, decoded, err := realtimetts.DecodeWAV(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !realtimetts.SameFormat(decoded, format) || len(pcm) == 0 {
+		t.Fatalf("音频应转换为服务的输出格式: %+v, %d 字节", decoded, len(pcm))
+	}
+
+	// 相同文本命中缓存，PCM 响应与 WAV 中的数据一致
+	resp, raw := synthesize(t, ts, server.SynthesizeRequest{Text: "hello world", Format: server.FormatPCM})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Sample-Rate") != strconv.Itoa(format.SampleRate) {
+		t.Fatalf("PCM 响应头不正确: %d %v", resp.StatusCode, resp.Header)
+	}
+	if !bytes.Equal(raw, pcm) {
+		t.Fatalf("PCM 响应应与 WAV 数据一致: %d != %d 字节", len(raw), len(pcm))
+	}
+	if store.Len() != 1 {
+		t.Fatalf("相同文本应只缓存一次: %d", store.Len())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(recordDir, "*.wav"))
+	if len(files) != 2 {
+		t.Fatalf("每次合成应录制一个文件: %v", files)
+	}
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if recorded, _, err := realtimetts.DecodeWAV(file); err != nil || !bytes.Equal(recorded, pcm) {
+		t.Fatalf("录音文件应包含完整音频: %v", err)
+	}
+}
+
+func TestServerSynthesizeErrors(t *testing.T) {
+	ts := newTestServer(t, server.Config{MaxTextLength: 10})
+
+	tests := []struct {
+		name   string
+		req    server.SynthesizeRequest
+		status int
+	}{
+		{"empty text", server.SynthesizeRequest{}, http.StatusBadRequest},
+		{"too long", server.SynthesizeRequest{Text: strings.Repeat("字", 11)}, http.StatusRequestEntityTooLarge},
+		{"opus not registered", server.SynthesizeRequest{Text: "hi", Format: server.FormatOpus}, http.StatusUnsupportedMediaType},
+		{"unknown voice", server.SynthesizeRequest{Text: "hi", Voice: "nope"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := synthesize(t, ts, tt.req)
+			var payload struct{ Error string }
+			if resp.StatusCode != tt.status || json.Unmarshal(body, &payload) != nil || payload.Error == "" {
+				t.Fatalf("期望 %d 和 JSON 错误, 实际 %d %s", tt.status, resp.StatusCode, body)
+			}
+		})
+	}
+
+	resp, body := synthesize(t, ts, server.SynthesizeRequest{Text: "hi", Voice: "beep", Format: server.FormatPCM})
+	if resp.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Fatalf("引擎支持的语音应能合成: %d", resp.StatusCode)
+	}
+}
+
+func TestServerVoiceValidation(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 480))
+	}))
+	defer upstream.Close()
+
+	// OpenAI 引擎的 SetVoice 接受任意字符串，只有 GetVoices 列出的语音和 Config.Voices 中的语音可以请求
+	var chain realtimetts.EngineChainConfig
+	err := json.Unmarshal([]byte(`{"engines": [{"type": "openai", "name": "openai",
+		"config": {"api_key": "sk-test", "base_url": "`+upstream.URL+`/v1"}}]}`), &chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newTestServer(t, server.Config{Chain: chain, Voices: []string{"custom"}})
+
+	for voice, status := range map[string]int{
+		"nova":     http.StatusOK,
+		"custom":   http.StatusOK,
+		"anything": http.StatusBadRequest,
+	} {
+		resp, body := synthesize(t, ts, server.SynthesizeRequest{Text: "hi", Voice: voice, Format: server.FormatPCM})
+		if resp.StatusCode != status {
+			t.Fatalf("语音 %s: 期望 %d, 实际 %d %s", voice, status, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestServerCustomCodec(t *testing.T) {
+	// 假的 Opus 编码器，验证注册的格式被使用
+	codec := server.Codec{
+		ContentType: "audio/ogg",
+		New: func(w io.Writer, format *realtimetts.AudioConfiguration) (io.WriteCloser, error) {
+			return &countingWriter{w: w}, nil
+		},
+	}
+	ts := newTestServer(t, server.Config{Codecs: map[string]server.Codec{server.FormatOpus: codec}})
+
+	resp, body := synthesize(t, ts, server.SynthesizeRequest{Text: "hi", Format: server.FormatOpus})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/ogg" || string(body) != "closed" {
+		t.Fatalf("应使用注册的编码器: %d %s %q", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+}
+
+func TestServerVoicesAndHealth(t *testing.T) {
+	ts := newTestServer(t, server.Config{})
+
+	resp, err := http.Get(ts.URL + "/v1/voices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var voices struct {
+		Voices []struct{ Engine, ID string }
+	}
+	json.NewDecoder(resp.Body).Decode(&voices)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(voices.Voices) != 3 || voices.Voices[0].Engine != "tone" {
+		t.Fatalf("语音列表不正确: %d %+v", resp.StatusCode, voices)
+	}
+
+	resp, err = http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var health struct {
+		Status  string
+		Engines []struct{ Name string }
+	}
+	json.NewDecoder(resp.Body).Decode(&health)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || health.Status != "ok" || len(health.Engines) != 1 {
+		t.Fatalf("健康检查不正确: %d %+v", resp.StatusCode, health)
+	}
+}
+
+// countingWriter 丢弃音频，关闭时写出 "closed"
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.n += len(p)
+	return len(p), nil
+}
+
+func (cw *countingWriter) Close() error {
+	if cw.n == 0 {
+		return io.ErrShortWrite
+	}
+	_, err := io.WriteString(cw.w, "closed")
+	return err
+}
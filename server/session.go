@@ -0,0 +1,409 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// 会话消息类型
+// 客户端发送 text、flush、interrupt；服务端发送 ready、sentence、word、done、interrupted、error，
+// 音频单独发送，格式见 ready 消息
+const (
+	MessageText        = "text"        // 追加文本，凑成整句后开始合成
+	MessageFlush       = "flush"       // 合成尚未凑成整句的文本
+	MessageInterrupt   = "interrupt"   // 停止当前播放，丢弃未合成的文本和未发送的音频
+	MessageReady       = "ready"       // 会话建立，附音频格式和引擎
+	MessageSentence    = "sentence"    // 句子开始合成
+	MessageWord        = "word"        // 单词开始播放，时间相对所属话语音频的开始
+	MessageDone        = "done"        // 话语结束，附音频时长；被打断或失败时附 interrupted 或 error
+	MessageInterrupted = "interrupted" // interrupt 已生效，此后的音频属于新的话语
+	MessageError       = "error"
+)
+
+// StreamMessage 流式会话中的消息，WebSocket 会话中以 JSON 发送
+//
+// 每个话语的音频首尾相接：word 的 start_ms、end_ms 相对话语音频的开始，
+// 话语的音频时长见其 done 消息，收到 interrupted 后从新的话语开始计算
+type StreamMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"` // text 的文本，sentence 的句子，done 的话语文本
+
+	Word    string `json:"word,omitempty"`
+	StartMs int64  `json:"start_ms,omitempty"`
+	EndMs   int64  `json:"end_ms,omitempty"`
+
+	UtteranceID uint64 `json:"utterance_id,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"`
+	Interrupted bool   `json:"interrupted,omitempty"`
+	Error       string `json:"error,omitempty"`
+
+	SampleRate    int    `json:"sample_rate,omitempty"`
+	Channels      int    `json:"channels,omitempty"`
+	BitsPerSample int    `json:"bits_per_sample,omitempty"`
+	Engine        string `json:"engine,omitempty"`
+}
+
+// sentenceTerminators 结束一句的字符，收到后把此前的文本交给合成
+const sentenceTerminators = "。！？；.!?;\n"
+
+// sessionSendQueue 待发送的消息数，写满时播放器阻塞，形成背压
+const sessionSendQueue = 64
+
+// outgoing 待发送的音频或消息
+type outgoing struct {
+	audio   []byte
+	message *StreamMessage
+	epoch   uint64 // 音频所属的播放轮次，被打断的轮次的音频不再发送
+}
+
+// session 流式合成会话，拥有独立的 TextToAudioStream 和引擎实例
+// 由 WebSocket 和 gRPC 共用：传输层把收到的消息交给 handle，从 send 取出待发送的内容
+type session struct {
+	server *Server
+	tts    *realtimetts.TextToAudioStream
+	output *sessionOutput
+	format *realtimetts.AudioConfiguration // 发送的音频格式
+	logger *slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	send   chan outgoing
+	events sync.WaitGroup
+
+	pending  strings.Builder // 尚未凑成整句的文本，仅在传输层的读取协程中访问
+	lastDone chan struct{}   // 上一个话语的 done 消息已排队时关闭，使 done 按输入顺序发送；仅在读取协程中访问
+
+	mu          sync.Mutex
+	outstanding int           // 已输入但尚未结束的话语数
+	drained     chan struct{} // finish 等待 outstanding 归零
+}
+
+// openSession 为语音构造引擎并创建会话，服务关闭后返回 ErrServerClosed
+func (s *Server) openSession(voice string, logger *slog.Logger) (*session, error) {
+	engines, err := s.buildEngines(voice)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ss := &session{
+		server: s,
+		format: s.config.Audio,
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+		send:   make(chan outgoing, sessionSendQueue),
+	}
+	ss.lastDone = make(chan struct{})
+	close(ss.lastDone)
+	ss.output = &sessionOutput{session: ss}
+
+	config := realtimetts.DefaultStreamConfig()
+	config.AudioConfig = s.config.Audio
+	config.Failover = s.config.Failover
+	config.Output = ss.output
+	ss.tts = realtimetts.NewTextToAudioStream(engines, config)
+	ss.tts.SetLogger(logger)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ss.close()
+		return nil, ErrServerClosed
+	}
+	s.sessions[ss] = struct{}{}
+	s.mu.Unlock()
+	return ss, nil
+}
+
+// start 发送 ready 消息并开始播放
+func (ss *session) start() error {
+	events := ss.tts.Subscribe(ss.ctx, &realtimetts.EventConfig{BufferSize: 1024, Overflow: realtimetts.DropOldest})
+	ss.events.Add(1)
+	go func() {
+		defer ss.events.Done()
+		ss.forwardEvents(events)
+	}()
+
+	ss.sendMessage(StreamMessage{
+		Type:          MessageReady,
+		SampleRate:    ss.format.SampleRate,
+		Channels:      ss.format.Channels,
+		BitsPerSample: ss.format.BitsPerSample,
+		Engine:        ss.tts.GetStatus().CurrentEngine,
+	})
+	return ss.tts.Play()
+}
+
+// close 结束会话，关闭流和其中的引擎
+func (ss *session) close() {
+	ss.cancel()
+	ss.events.Wait()
+	if err := ss.tts.Close(); err != nil {
+		ss.logger.Warn("关闭会话失败", realtimetts.LogKeyError, err)
+	}
+
+	ss.server.mu.Lock()
+	delete(ss.server.sessions, ss)
+	ss.server.mu.Unlock()
+}
+
+// handle 处理客户端消息
+func (ss *session) handle(message StreamMessage) {
+	switch message.Type {
+	case MessageText:
+		ss.pending.WriteString(message.Text)
+		ss.feed(false)
+	case MessageFlush:
+		ss.feed(true)
+	case MessageInterrupt:
+		ss.interrupt()
+	default:
+		ss.sendError(errors.New("未知的消息类型: " + message.Type))
+	}
+}
+
+// feed 把已凑成整句的文本交给合成，all 为 true 或文本超过长度上限时全部交出
+func (ss *session) feed(all bool) {
+	text := ss.pending.String()
+	cut := len(text)
+	if !all && len([]rune(text)) <= ss.server.config.MaxTextLength {
+		cut = strings.LastIndexAny(text, sentenceTerminators)
+		if cut < 0 {
+			return
+		}
+		_, size := utf8.DecodeRuneInString(text[cut:])
+		cut += size
+	}
+
+	ss.pending.Reset()
+	ss.pending.WriteString(text[cut:])
+	if strings.TrimSpace(text[:cut]) == "" {
+		return
+	}
+
+	// 先计数再输入，话语结束不会早于计数
+	ss.mu.Lock()
+	ss.outstanding++
+	ss.mu.Unlock()
+	result, err := ss.tts.FeedWithResult(context.Background(), text[:cut])
+	if err != nil {
+		ss.completed()
+		ss.sendError(err)
+		return
+	}
+
+	previous, done := ss.lastDone, make(chan struct{})
+	ss.lastDone = done
+	go func() {
+		defer close(done)
+		ss.forwardResult(result, previous)
+	}()
+}
+
+// forwardResult 等待话语结束并发送 done 消息，previous 关闭后才发送以保持输入顺序
+// 结果不经过事件订阅，读取缓慢的客户端也不会丢失 done 消息；会话结束时直接返回
+func (ss *session) forwardResult(result <-chan realtimetts.UtteranceResult, previous <-chan struct{}) {
+	var r realtimetts.UtteranceResult
+	select {
+	case r = <-result:
+	case <-ss.ctx.Done():
+		return
+	}
+	select {
+	case <-previous:
+	case <-ss.ctx.Done():
+		return
+	}
+
+	message := StreamMessage{
+		Type:        MessageDone,
+		Text:        r.Text,
+		UtteranceID: r.UtteranceID,
+		DurationMs:  r.AudioDuration.Milliseconds(),
+	}
+	switch {
+	case errors.Is(r.Err, context.Canceled):
+		message.Interrupted = true
+	case r.Err != nil:
+		message.Error = realtimetts.Redact(r.Err.Error())
+	}
+	ss.sendMessage(message)
+	ss.completed()
+}
+
+// interrupt 停止当前播放并丢弃未合成的文本，然后开始新一轮播放
+// 停止时 sessionOutput 的轮次递增，已排队的旧音频不再发送
+func (ss *session) interrupt() {
+	ss.pending.Reset()
+	if err := ss.tts.Stop(); err != nil {
+		ss.logger.Warn("停止播放失败", realtimetts.LogKeyError, err)
+	}
+	if err := ss.tts.Play(); err != nil {
+		ss.sendError(err)
+		return
+	}
+	ss.sendMessage(StreamMessage{Type: MessageInterrupted})
+}
+
+// finish 输入结束：合成剩余文本并等待所有话语结束，返回时它们的 done 消息都已排队
+// 会话提前结束时立即返回
+func (ss *session) finish() {
+	ss.feed(true)
+
+	ss.mu.Lock()
+	if ss.outstanding == 0 {
+		ss.mu.Unlock()
+		return
+	}
+	drained := make(chan struct{})
+	ss.drained = drained
+	ss.mu.Unlock()
+
+	select {
+	case <-drained:
+	case <-ss.ctx.Done():
+	}
+}
+
+// completed 一个话语结束
+func (ss *session) completed() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.outstanding--
+	if ss.outstanding == 0 && ss.drained != nil {
+		close(ss.drained)
+		ss.drained = nil
+	}
+}
+
+// forwardEvents 把流的事件转换为消息
+// 事件订阅在客户端读取缓慢时会丢弃事件，done 消息由 forwardResult 发送
+func (ss *session) forwardEvents(events <-chan realtimetts.Event) {
+	for event := range events {
+		switch e := event.(type) {
+		case realtimetts.SentenceEvent:
+			ss.sendMessage(StreamMessage{Type: MessageSentence, Text: e.Sentence})
+		case realtimetts.WordEvent:
+			ss.sendMessage(StreamMessage{Type: MessageWord, Word: e.Word, StartMs: e.StartTime.Milliseconds(), EndMs: e.EndTime.Milliseconds()})
+		case realtimetts.ErrorEvent:
+			if !errors.Is(e.Err, context.Canceled) {
+				ss.sendError(e.Err)
+			}
+		}
+	}
+}
+
+// sendMessage 排队发送消息，会话结束后丢弃
+func (ss *session) sendMessage(message StreamMessage) {
+	select {
+	case ss.send <- outgoing{message: &message}:
+	case <-ss.ctx.Done():
+	}
+}
+
+// sendError 发送脱敏的错误消息
+func (ss *session) sendError(err error) {
+	ss.sendMessage(StreamMessage{Type: MessageError, Error: realtimetts.Redact(err.Error())})
+}
+
+// stale 是否为被打断的轮次的音频
+func (ss *session) stale(o outgoing) bool {
+	return o.audio != nil && o.epoch != ss.output.currentEpoch()
+}
+
+// sessionOutput 把播放器写出的音频交给会话发送的 AudioOutput
+// 写入在发送队列满时阻塞，播放器因此按客户端读取的速度推进；音量和静音由客户端控制，这里只记录设置
+type sessionOutput struct {
+	session *session
+
+	mu     sync.Mutex
+	active bool
+	epoch  uint64 // 每次 StopStream 递增
+	volume float64
+	muted  bool
+}
+
+// currentEpoch 返回当前的播放轮次
+func (o *sessionOutput) currentEpoch() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.epoch
+}
+
+// OpenStream 实现 AudioOutput
+func (o *sessionOutput) OpenStream() error {
+	return nil
+}
+
+// StartStream 开始发送
+func (o *sessionOutput) StartStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.active = true
+	return nil
+}
+
+// StopStream 停止发送并丢弃已排队的音频，此后的写入返回 ErrStreamNotActive
+func (o *sessionOutput) StopStream() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.active {
+		o.active = false
+		o.epoch++
+	}
+	return nil
+}
+
+// CloseStream 实现 AudioOutput
+func (o *sessionOutput) CloseStream() error {
+	return o.StopStream()
+}
+
+// WriteAudioData 排队发送一段音频，会话结束时返回上下文的错误
+func (o *sessionOutput) WriteAudioData(data []byte) error {
+	o.mu.Lock()
+	if !o.active {
+		o.mu.Unlock()
+		return realtimetts.ErrStreamNotActive
+	}
+	epoch := o.epoch
+	o.mu.Unlock()
+
+	message := outgoing{audio: append([]byte(nil), data...), epoch: epoch}
+	select {
+	case o.session.send <- message:
+		return nil
+	case <-o.session.ctx.Done():
+		return o.session.ctx.Err()
+	}
+}
+
+// SetVolume 实现 AudioOutput
+func (o *sessionOutput) SetVolume(volume float64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.volume = volume
+	return nil
+}
+
+// GetVolume 实现 AudioOutput
+func (o *sessionOutput) GetVolume() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.volume
+}
+
+// SetMuted 实现 AudioOutput
+func (o *sessionOutput) SetMuted(muted bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.muted = muted
+	return nil
+}
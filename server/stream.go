@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	realtimetts "realtimetts/pkg"
+)
+
+// WebSocket 会话的保活参数
+const (
+	streamWriteWait    = 10 * time.Second
+	streamPongWait     = 60 * time.Second
+	streamPingInterval = 30 * time.Second
+)
+
+// handleStream 处理 GET /v1/stream，升级为 WebSocket 会话
+// 查询参数 voice 指定语音；消息以 JSON 文本消息收发，音频以二进制消息发送；
+// 会话在客户端断开或服务关闭时结束
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	id := atomic.AddUint64(&s.requestSeq, 1)
+	ss, err := s.openSession(r.URL.Query().Get("voice"), s.logger.With("session_id", id))
+	switch {
+	case errors.Is(err, ErrUnknownVoice):
+		writeError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, ErrServerClosed):
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer ss.close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: s.config.CheckOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 已写出错误响应
+		return
+	}
+	defer conn.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		writeLoop(conn, ss)
+	}()
+	defer wg.Wait()
+	defer ss.cancel()
+
+	if err := ss.start(); err != nil {
+		ss.sendError(err)
+		return
+	}
+	readLoop(conn, ss)
+}
+
+// readLoop 读取客户端消息交给会话，直到连接断开
+func readLoop(conn *websocket.Conn, ss *session) {
+	conn.SetReadLimit(1 << 20)
+	conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+
+	for {
+		var message StreamMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				ss.sendError(err)
+				continue
+			}
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				ss.logger.Debug("会话连接断开", realtimetts.LogKeyError, err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		ss.handle(message)
+	}
+}
+
+// writeLoop 按顺序写出消息和音频并定时发送 ping，写出失败时结束会话
+func writeLoop(conn *websocket.Conn, ss *session) {
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message := <-ss.send:
+			if ss.stale(message) {
+				continue
+			}
+			messageType, data := websocket.BinaryMessage, message.audio
+			if message.message != nil {
+				var err error
+				if data, err = json.Marshal(message.message); err != nil {
+					ss.logger.Warn("编码消息失败", realtimetts.LogKeyError, err)
+					continue
+				}
+				messageType = websocket.TextMessage
+			}
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				ss.logger.Debug("写出消息失败", realtimetts.LogKeyError, err)
+				ss.cancel()
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				ss.cancel()
+				return
+			}
+		case <-ss.ctx.Done():
+			// 服务关闭时通知客户端，并让读取协程在等待客户端确认后结束
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(streamWriteWait))
+			conn.SetReadDeadline(time.Now().Add(streamWriteWait))
+			return
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	realtimetts "realtimetts/pkg"
+	"realtimetts/server"
+)
+
+// streamClient 测试用的 WebSocket 会话客户端
+type streamClient struct {
+	t     *testing.T
+	conn  *websocket.Conn
+	audio int // 收到的音频字节数
+}
+
+// dialStream 建立会话并读取 ready 消息
+func dialStream(t *testing.T, config server.Config) (*streamClient, server.StreamMessage) {
+	t.Helper()
+	ts := newTestServer(t, config)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/v1/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client := &streamClient{t: t, conn: conn}
+	ready := client.next(server.MessageReady)
+	return client, ready
+}
+
+func (c *streamClient) send(message server.StreamMessage) {
+	c.t.Helper()
+	if err := c.conn.WriteJSON(message); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// read 读取下一条 JSON 消息，途中的音频计入 audio
+func (c *streamClient) read() server.StreamMessage {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		kind, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.t.Fatalf("读取消息失败: %v", err)
+		}
+		if kind == websocket.BinaryMessage {
+			c.audio += len(data)
+			continue
+		}
+		var message server.StreamMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.t.Fatal(err)
+		}
+		return message
+	}
+}
+
+// next 读取消息直到收到指定类型的消息，途中的其他消息交给 seen
+func (c *streamClient) next(messageType string, seen ...func(server.StreamMessage)) server.StreamMessage {
+	c.t.Helper()
+	for {
+		message := c.read()
+		if message.Type == messageType {
+			return message
+		}
+		for _, fn := range seen {
+			fn(message)
+		}
+	}
+}
+
+func TestStreamSession(t *testing.T) {
+	client, ready := dialStream(t, server.Config{})
+	format := realtimetts.DefaultAudioConfig()
+	if ready.SampleRate != format.SampleRate || ready.Channels != format.Channels || ready.Engine != "tone" {
+		t.Fatalf("ready 消息不正确: %+v", ready)
+	}
+
+	// 文本分多次到达，凑成整句后才开始合成
+	client.send(server.StreamMessage{Type: server.MessageText, Text: "hello "})
+	client.send(server.StreamMessage{Type: server.MessageText, Text: "world. and"})
+
+	var sentences []string
+	var words []server.StreamMessage
+	done := client.next(server.MessageDone, func(message server.StreamMessage) {
+		switch message.Type {
+		case server.MessageSentence:
+			sentences = append(sentences, message.Text)
+		case server.MessageWord:
+			words = append(words, message)
+		case server.MessageError:
+			t.Fatalf("不应出错: %s", message.Error)
+		}
+	})
+	if done.Text != "hello world." || done.Error != "" || done.Interrupted {
+		t.Fatalf("done 消息不正确: %+v", done)
+	}
+	if len(sentences) != 1 || sentences[0] != "hello world" {
+		t.Fatalf("sentence 消息不正确: %v", sentences)
+	}
+	if len(words) != 2 || words[0].Word != "hello" || words[1].Word != "world" || words[1].StartMs <= words[0].StartMs {
+		t.Fatalf("word 消息不正确: %+v", words)
+	}
+
+	// 话语的音频时长与收到的字节数一致
+	duration := time.Duration(client.audio) * time.Second / time.Duration(format.GetBytesPerSecond())
+	if diff := duration - time.Duration(done.DurationMs)*time.Millisecond; diff < 0 || diff > time.Millisecond {
+		t.Fatalf("音频 %v 与 done 的时长 %dms 不一致", duration, done.DurationMs)
+	}
+
+	// flush 合成未凑成整句的剩余文本
+	client.send(server.StreamMessage{Type: server.MessageFlush})
+	if done := client.next(server.MessageDone); done.Text != " and" {
+		t.Fatalf("flush 后应合成剩余文本: %+v", done)
+	}
+}
+
+func TestStreamSessionInterrupt(t *testing.T) {
+	client, _ := dialStream(t, server.Config{
+		Chain: toneChain(t, `{"word_duration": "20ms", "chunk_interval": "20ms", "chunk_size": 320}`),
+	})
+
+	client.send(server.StreamMessage{Type: server.MessageText, Text: strings.Repeat("word ", 50) + "."})
+	client.next(server.MessageWord)
+
+	// 话语被取消的 done 与 interrupted 由不同协程发送，顺序不固定
+	client.send(server.StreamMessage{Type: server.MessageInterrupt})
+	var interrupted, cancelled bool
+	for !interrupted || !cancelled {
+		switch message := client.read(); {
+		case message.Type == server.MessageInterrupted:
+			interrupted = true
+		case message.Type == server.MessageDone:
+			if !message.Interrupted {
+				t.Fatalf("被打断的话语不应正常完成: %+v", message)
+			}
+			cancelled = true
+		}
+	}
+
+	// 打断后会话继续可用
+	client.send(server.StreamMessage{Type: server.MessageText, Text: "again."})
+	if done := client.next(server.MessageDone); done.Text != "again." || done.Interrupted {
+		t.Fatalf("打断后的话语应正常完成: %+v", done)
+	}
+}
+
+func TestStreamSessionUnknownVoice(t *testing.T) {
+	ts := newTestServer(t, server.Config{})
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/v1/stream?voice=nope", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("未知语音应在握手时返回 400: %v", err)
+	}
+}
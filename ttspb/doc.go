@@ -0,0 +1,4 @@
+// Package ttspb 是 tts.proto 生成的 gRPC 语音合成服务定义，服务端实现见 server 包的 RegisterGRPC
+package ttspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tts.proto
@@ -0,0 +1,204 @@
+// realtimetts 的 gRPC 语音合成服务，与 HTTP 服务共用引擎链和会话实现
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: ttspb/tts.proto
+
+package ttspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TTSService_Synthesize_FullMethodName    = "/realtimetts.v1.TTSService/Synthesize"
+	TTSService_ListVoices_FullMethodName    = "/realtimetts.v1.TTSService/ListVoices"
+	TTSService_GetEngineInfo_FullMethodName = "/realtimetts.v1.TTSService/GetEngineInfo"
+)
+
+// TTSServiceClient is the client API for TTSService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TTSServiceClient interface {
+	// Synthesize 流式合成会话：客户端边发送文本边接收音频、时间信息和事件
+	// 第一条消息可以是 StartSession 以选择语音；客户端关闭发送方向后，
+	// 服务端合成剩余文本，所有话语结束后结束流
+	Synthesize(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SynthesizeRequest, SynthesizeResponse], error)
+	// ListVoices 列出默认引擎链中各引擎的语音
+	ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error)
+	// GetEngineInfo 返回默认引擎链中各引擎的信息和健康状态
+	GetEngineInfo(ctx context.Context, in *GetEngineInfoRequest, opts ...grpc.CallOption) (*GetEngineInfoResponse, error)
+}
+
+type tTSServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTTSServiceClient(cc grpc.ClientConnInterface) TTSServiceClient {
+	return &tTSServiceClient{cc}
+}
+
+func (c *tTSServiceClient) Synthesize(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SynthesizeRequest, SynthesizeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TTSService_ServiceDesc.Streams[0], TTSService_Synthesize_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SynthesizeRequest, SynthesizeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TTSService_SynthesizeClient = grpc.BidiStreamingClient[SynthesizeRequest, SynthesizeResponse]
+
+func (c *tTSServiceClient) ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVoicesResponse)
+	err := c.cc.Invoke(ctx, TTSService_ListVoices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tTSServiceClient) GetEngineInfo(ctx context.Context, in *GetEngineInfoRequest, opts ...grpc.CallOption) (*GetEngineInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEngineInfoResponse)
+	err := c.cc.Invoke(ctx, TTSService_GetEngineInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TTSServiceServer is the server API for TTSService service.
+// All implementations must embed UnimplementedTTSServiceServer
+// for forward compatibility.
+type TTSServiceServer interface {
+	// Synthesize 流式合成会话：客户端边发送文本边接收音频、时间信息和事件
+	// 第一条消息可以是 StartSession 以选择语音；客户端关闭发送方向后，
+	// 服务端合成剩余文本，所有话语结束后结束流
+	Synthesize(grpc.BidiStreamingServer[SynthesizeRequest, SynthesizeResponse]) error
+	// ListVoices 列出默认引擎链中各引擎的语音
+	ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error)
+	// GetEngineInfo 返回默认引擎链中各引擎的信息和健康状态
+	GetEngineInfo(context.Context, *GetEngineInfoRequest) (*GetEngineInfoResponse, error)
+	mustEmbedUnimplementedTTSServiceServer()
+}
+
+// UnimplementedTTSServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTTSServiceServer struct{}
+
+func (UnimplementedTTSServiceServer) Synthesize(grpc.BidiStreamingServer[SynthesizeRequest, SynthesizeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Synthesize not implemented")
+}
+func (UnimplementedTTSServiceServer) ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVoices not implemented")
+}
+func (UnimplementedTTSServiceServer) GetEngineInfo(context.Context, *GetEngineInfoRequest) (*GetEngineInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEngineInfo not implemented")
+}
+func (UnimplementedTTSServiceServer) mustEmbedUnimplementedTTSServiceServer() {}
+func (UnimplementedTTSServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeTTSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TTSServiceServer will
+// result in compilation errors.
+type UnsafeTTSServiceServer interface {
+	mustEmbedUnimplementedTTSServiceServer()
+}
+
+func RegisterTTSServiceServer(s grpc.ServiceRegistrar, srv TTSServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTTSServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TTSService_ServiceDesc, srv)
+}
+
+func _TTSService_Synthesize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TTSServiceServer).Synthesize(&grpc.GenericServerStream[SynthesizeRequest, SynthesizeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TTSService_SynthesizeServer = grpc.BidiStreamingServer[SynthesizeRequest, SynthesizeResponse]
+
+func _TTSService_ListVoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TTSServiceServer).ListVoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TTSService_ListVoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TTSServiceServer).ListVoices(ctx, req.(*ListVoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TTSService_GetEngineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEngineInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TTSServiceServer).GetEngineInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TTSService_GetEngineInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TTSServiceServer).GetEngineInfo(ctx, req.(*GetEngineInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TTSService_ServiceDesc is the grpc.ServiceDesc for TTSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TTSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "realtimetts.v1.TTSService",
+	HandlerType: (*TTSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListVoices",
+			Handler:    _TTSService_ListVoices_Handler,
+		},
+		{
+			MethodName: "GetEngineInfo",
+			Handler:    _TTSService_GetEngineInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Synthesize",
+			Handler:       _TTSService_Synthesize_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ttspb/tts.proto",
+}